@@ -6,10 +6,14 @@ import (
 	influxdb "github.com/influxdata/influxdb1-client/v2"
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/evaluator"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
 	"sync"
@@ -60,6 +64,10 @@ type QueryCommandRunnerConfig struct {
 	Timeout time.Duration
 	// Verbose indicates whether to output more logs or not
 	Verbose bool
+	// SchemaMapper maps PromQL metrics to InfluxDB measurements and fields, default is schema.PromWriteMapper
+	SchemaMapper schema.Mapper
+	// ChunkSize sets the maximum number of points of every chunk InfluxDB responds to Stream, default is InfluxDB's 10000
+	ChunkSize int
 }
 
 type QueryCommandRunnerOpts struct {
@@ -85,6 +93,13 @@ func (receiver *QueryCommandRunner) ApplyOpts(opts QueryCommandRunnerOpts) {
 	}
 }
 
+func (receiver *QueryCommandRunner) schemaMapper() schema.Mapper {
+	if receiver.Cfg.SchemaMapper != nil {
+		return receiver.Cfg.SchemaMapper
+	}
+	return schema.PromWriteMapper{}
+}
+
 // handleExprTranspileResult evaluates influxql.Expr itself locally.
 func (receiver *QueryCommandRunner) handleExprTranspileResult(cmd models.PromCommand, expr parser.Expr, n influxql.Expr, resultChan chan models.RunResult, handleErr func(err error)) {
 	if transpiler.YieldsFloat(expr) {
@@ -106,7 +121,10 @@ func (receiver *QueryCommandRunner) handleExprTranspileResult(cmd models.PromCom
 }
 
 // handleStatementTranspileResult delegates remote InfluxDB server to evaluate InfluxQL statements for us with the help of influxdb.Client.
-func (receiver *QueryCommandRunner) handleStatementTranspileResult(cmd models.PromCommand, expr parser.Expr, influxCmd string, resultChan chan models.RunResult, handleErr func(err error)) {
+// Results of range queries are re-stamped onto the requested step grid according to stepModifier.
+// If rangeFunc is not nil, the results are raw samples from which the range function and its enclosing expression are evaluated locally.
+func (receiver *QueryCommandRunner) handleStatementTranspileResult(cmd models.PromCommand, expr parser.Expr, stepModifier transpiler.StepModifier,
+	rangeFunc *transpiler.RangeFunc, influxCmd string, resultChan chan models.RunResult, handleErr func(err error)) {
 	resp, err := receiver.Client.Query(influxdb.NewQuery(influxCmd, cmd.Database, ""))
 	if err != nil {
 		handleErr(errors.Wrap(err, "error from influxdb api"))
@@ -125,17 +143,48 @@ func (receiver *QueryCommandRunner) handleStatementTranspileResult(cmd models.Pr
 	switch cmd.DataType {
 	case models.LABEL_VALUES_DATA:
 		var dest []string
-		if err = receiver.InfluxResultToStringSlice(resp.Results, &dest); err != nil {
+		if cmd.LabelValuesMode == models.LABEL_VALUES_ACCURATE {
+			dest, err = receiver.InfluxResultToTagValues(resp.Results, escaping.UnescapeName(cmd.LabelName))
+		} else {
+			err = receiver.InfluxResultToStringSlice(resp.Results, &dest)
+		}
+		if err != nil {
 			handleErr(errors.Wrap(err, "fail to convert result from influxdb format to string slice"))
 			return
 		}
 		result = dest[:]
+	case models.SERIES_DATA:
+		if result, err = receiver.InfluxResultToLabelSets(resp.Results, cmd); err != nil {
+			handleErr(errors.Wrap(err, "fail to convert result from influxdb format to label sets"))
+			return
+		}
+	case models.LABEL_NAMES_DATA:
+		if result, err = receiver.InfluxResultToLabelNames(resp.Results); err != nil {
+			handleErr(errors.Wrap(err, "fail to convert result from influxdb format to label names"))
+			return
+		}
 	default:
+		if rangeFunc != nil {
+			result, resultType, err = receiver.InfluxResultToPromQLValue(resp.Results, rangeFunc.Arg, cmd)
+			if err != nil {
+				handleErr(errors.Wrap(err, "fail to convert result from influxdb format to native prometheus format"))
+				return
+			}
+			matrix, _ := result.(promql.Matrix)
+			if result, resultType, err = receiver.evalRangeFunc(matrix, cmd, stepModifier, rangeFunc); err != nil {
+				handleErr(errors.Wrap(err, "fail to evaluate range function locally"))
+				return
+			}
+			break
+		}
 		result, resultType, err = receiver.InfluxResultToPromQLValue(resp.Results, expr, cmd)
 		if err != nil {
 			handleErr(errors.Wrap(err, "fail to convert result from influxdb format to native prometheus format"))
 			return
 		}
+		if matrix, ok := result.(promql.Matrix); ok && cmd.QueryType == models.RANGE_QUERY {
+			result = receiver.restampMatrix(matrix, cmd, stepModifier)
+		}
 	}
 	resultChan <- models.RunResult{
 		Result:     result,
@@ -143,6 +192,84 @@ func (receiver *QueryCommandRunner) handleStatementTranspileResult(cmd models.Pr
 	}
 }
 
+// handleMetricNamesCase lists metric names, i.e. values of __name__ label, selected by cmd.Cmd which is optional.
+// Metric names are measurement names rather than tag values in InfluxDB, so SHOW TAG VALUES doesn't work for them.
+func (receiver *QueryCommandRunner) handleMetricNamesCase(cmd models.PromCommand, resultChan chan models.RunResult, handleErr func(err error)) {
+	var matchers []*labels.Matcher
+	if stringutils.IsNotEmpty(cmd.Cmd) {
+		var err error
+		if matchers, err = parser.ParseMetricSelector(cmd.Cmd); err != nil {
+			handleErr(errors.Wrap(err, "to get label values the PromQL command must be vector selector"))
+			return
+		}
+	}
+	t := &transpiler.Transpiler{
+		PromCommand:  cmd,
+		SchemaMapper: receiver.schemaMapper(),
+	}
+	node, err := t.TranspileMetricNames(matchers)
+	if err != nil {
+		handleErr(errors.Wrap(err, "command execute fail"))
+		return
+	}
+	influxCmd := node.String()
+	if receiver.Cfg.Verbose {
+		zlogger.Info().Msgf("PromQL: %s => InfluxQL: %s", cmd.Cmd, influxCmd)
+	}
+	resp, err := receiver.Client.Query(influxdb.NewQuery(influxCmd, cmd.Database, ""))
+	if err != nil {
+		handleErr(errors.Wrap(err, "error from influxdb api"))
+		return
+	}
+	if stringutils.IsNotEmpty(resp.Err) {
+		handleErr(errors.Errorf("error from influxdb api: %s", resp.Err))
+		return
+	}
+	result, err := receiver.InfluxResultToMetricNames(resp.Results, matchers)
+	if err != nil {
+		handleErr(errors.Wrap(err, "fail to convert result from influxdb format to metric names"))
+		return
+	}
+	resultChan <- models.RunResult{
+		Result: result,
+	}
+}
+
+// handleMetadataCase lists metric families and their types, which are inferred from field keys as InfluxDB doesn't store them.
+// cmd.Cmd is an optional metric family name.
+func (receiver *QueryCommandRunner) handleMetadataCase(cmd models.PromCommand, resultChan chan models.RunResult, handleErr func(err error)) {
+	t := &transpiler.Transpiler{
+		PromCommand:  cmd,
+		SchemaMapper: receiver.schemaMapper(),
+	}
+	node, err := t.TranspileMetadata()
+	if err != nil {
+		handleErr(errors.Wrap(err, "command execute fail"))
+		return
+	}
+	influxCmd := node.String()
+	if receiver.Cfg.Verbose {
+		zlogger.Info().Msgf("PromQL: %s => InfluxQL: %s", cmd.Cmd, influxCmd)
+	}
+	resp, err := receiver.Client.Query(influxdb.NewQuery(influxCmd, cmd.Database, ""))
+	if err != nil {
+		handleErr(errors.Wrap(err, "error from influxdb api"))
+		return
+	}
+	if stringutils.IsNotEmpty(resp.Err) {
+		handleErr(errors.Errorf("error from influxdb api: %s", resp.Err))
+		return
+	}
+	result, err := receiver.InfluxResultToMetricTypes(resp.Results, cmd.Cmd)
+	if err != nil {
+		handleErr(errors.Wrap(err, "fail to convert result from influxdb format to metric types"))
+		return
+	}
+	resultChan <- models.RunResult{
+		Result: result,
+	}
+}
+
 func (receiver *QueryCommandRunner) handleCmdNotEmptyCase(cmd models.PromCommand, resultChan chan models.RunResult, handleErr func(err error)) {
 	switch cmd.DataType {
 	case models.LABEL_VALUES_DATA:
@@ -152,6 +279,16 @@ func (receiver *QueryCommandRunner) handleCmdNotEmptyCase(cmd models.PromCommand
 			handleErr(errors.Wrap(err, "to get label values the PromQL command must be vector selector"))
 			return
 		}
+	case models.SERIES_DATA:
+		if _, err := parser.ParseMetricSelector(cmd.Cmd); err != nil {
+			handleErr(errors.Wrap(err, "to get series the PromQL command must be vector selector"))
+			return
+		}
+	case models.LABEL_NAMES_DATA:
+		if _, err := parser.ParseMetricSelector(cmd.Cmd); err != nil {
+			handleErr(errors.Wrap(err, "to get label names the PromQL command must be vector selector"))
+			return
+		}
 	default:
 	}
 	// Parse cmd.Cmd to PromQL ast
@@ -160,8 +297,27 @@ func (receiver *QueryCommandRunner) handleCmdNotEmptyCase(cmd models.PromCommand
 		handleErr(errors.Wrap(err, "command parse fail"))
 		return
 	}
+	switch cmd.DataType {
+	case models.LABEL_VALUES_DATA, models.SERIES_DATA, models.LABEL_NAMES_DATA:
+	default:
+		if call, ok := histogramQuantileCall(expr); ok {
+			receiver.handleHistogramQuantileCase(cmd, call, resultChan, handleErr)
+			return
+		}
+		if selectors, fieldLabels := receiver.fieldSelectors(cmd, expr); len(selectors) > 0 {
+			receiver.handleMultiFieldCase(cmd, expr, selectors, fieldLabels, resultChan, handleErr)
+			return
+		}
+	}
+	receiver.handleExpr(cmd, expr, resultChan, handleErr)
+}
+
+// handleExpr transpiles PromQL ast to InfluxQL ast and evaluates it
+func (receiver *QueryCommandRunner) handleExpr(cmd models.PromCommand, expr parser.Expr, resultChan chan models.RunResult, handleErr func(err error)) {
 	t := &transpiler.Transpiler{
-		PromCommand: cmd,
+		PromCommand:  cmd,
+		SchemaMapper: receiver.schemaMapper(),
+		FieldKeys:    receiver.fieldKeys(cmd),
 	}
 	// Transpile PromQL ast to InfluxQL ast
 	node, err := t.Transpile(expr)
@@ -169,6 +325,8 @@ func (receiver *QueryCommandRunner) handleCmdNotEmptyCase(cmd models.PromCommand
 		handleErr(errors.Wrap(err, "command execute fail"))
 		return
 	}
+	// Keep the field key and field label resolved by the schema mapper for rebuilding metric names from query results
+	cmd.ValueFieldKey, cmd.ValueFieldLabel = t.FieldKey(), t.FieldLabel()
 	// Get string representation of InfluxQL query expression from the ast
 	influxCmd := node.String()
 	if receiver.Cfg.Verbose {
@@ -180,7 +338,7 @@ func (receiver *QueryCommandRunner) handleCmdNotEmptyCase(cmd models.PromCommand
 		receiver.handleExprTranspileResult(cmd, expr, n, resultChan, handleErr)
 	case influxql.Statement:
 		// Evaluate influxql.Statement
-		receiver.handleStatementTranspileResult(cmd, expr, influxCmd, resultChan, handleErr)
+		receiver.handleStatementTranspileResult(cmd, expr, t.StepModifier(), t.RangeFunc(), influxCmd, resultChan, handleErr)
 	default:
 		handleErr(transpiler.ErrPromExprNotSupported)
 	}
@@ -205,27 +363,57 @@ func (receiver *QueryCommandRunner) Run(ctx context.Context, cmd models.PromComm
 				Error: err,
 			}
 		}
-		// If cmd is models.LABEL_VALUES_DATA, cmd.Cmd may be empty.
+		if cmd.DataType == models.METADATA_DATA {
+			receiver.handleMetadataCase(cmd, resultChan, handleErr)
+			return
+		}
+		if cmd.DataType == models.LABEL_VALUES_DATA && cmd.LabelName == labels.MetricName {
+			receiver.handleMetricNamesCase(cmd, resultChan, handleErr)
+			return
+		}
+		// If cmd is models.LABEL_VALUES_DATA or models.LABEL_NAMES_DATA, cmd.Cmd may be empty.
 		// Here we handle the other case.
 		if stringutils.IsNotEmpty(cmd.Cmd) {
 			receiver.handleCmdNotEmptyCase(cmd, resultChan, handleErr)
 			return
 		}
-		// If cmd.Cmd is empty, we go here. We only handle models.LABEL_VALUES_DATA case here currently.
+		// If cmd.Cmd is empty, we go here. We only handle models.LABEL_VALUES_DATA and models.LABEL_NAMES_DATA cases here currently,
+		// the others get an error rather than waiting for the timeout.
 		switch cmd.DataType {
 		case models.LABEL_VALUES_DATA:
-			// It's a SHOW TAG VALUES statement.
-			node := &influxql.ShowTagValuesStatement{
-				Database:   cmd.Database,
-				Op:         influxql.EQ,
-				TagKeyExpr: &influxql.StringLiteral{Val: cmd.LabelName},
+			// It's a SHOW TAG VALUES statement, or a time-bounded SELECT statement in models.LABEL_VALUES_ACCURATE mode.
+			t := &transpiler.Transpiler{
+				PromCommand:  cmd,
+				SchemaMapper: receiver.schemaMapper(),
+			}
+			node, err := t.TranspileLabelValues()
+			if err != nil {
+				handleErr(errors.Wrap(err, "command execute fail"))
+				return
+			}
+			influxCmd := node.String()
+			if receiver.Cfg.Verbose {
+				zlogger.Info().Msgf("PromQL: %s => InfluxQL: %s", cmd.Cmd, influxCmd)
+			}
+			receiver.handleStatementTranspileResult(cmd, nil, transpiler.StepModifier{}, nil, influxCmd, resultChan, handleErr)
+		case models.LABEL_NAMES_DATA:
+			// It's a SHOW TAG KEYS statement over all measurements.
+			t := &transpiler.Transpiler{
+				PromCommand:  cmd,
+				SchemaMapper: receiver.schemaMapper(),
+			}
+			node, err := t.TranspileLabelNames()
+			if err != nil {
+				handleErr(errors.Wrap(err, "command execute fail"))
+				return
 			}
 			influxCmd := node.String()
 			if receiver.Cfg.Verbose {
 				zlogger.Info().Msgf("PromQL: %s => InfluxQL: %s", cmd.Cmd, influxCmd)
 			}
-			receiver.handleStatementTranspileResult(cmd, nil, influxCmd, resultChan, handleErr)
+			receiver.handleStatementTranspileResult(cmd, nil, transpiler.StepModifier{}, nil, influxCmd, resultChan, handleErr)
 		default:
+			handleErr(errors.Errorf("empty command of data type %d is not supported", cmd.DataType))
 		}
 	}()
 	for {
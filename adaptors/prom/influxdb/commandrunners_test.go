@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"github.com/golang/mock/gomock"
 	_ "github.com/influxdata/influxdb1-client"
+	influxmodels "github.com/influxdata/influxdb1-client/models"
 	client "github.com/influxdata/influxdb1-client/v2"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/textparse"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -323,6 +325,31 @@ func TestQueryCommandRunner_Run_Matrix_Table(t *testing.T) {
 			want:    expected,
 			wantErr: false,
 		},
+		{
+			name: "",
+			fields: fields{
+				Cfg: QueryCommandRunnerConfig{
+					Timeout: MustParseDuration("1m", t),
+					Verbose: true,
+				},
+				Client:  mockClient,
+				Factory: SingletonQueryCommandRunnerFactory,
+			},
+			args: args{
+				ctx: context.Background(),
+				cmd: models.PromCommand{
+					Cmd:           `cpu{host=~"tele.*"}[5m]`,
+					Database:      "telegraf",
+					Start:         &endTime2,
+					End:           &endTime2,
+					Timezone:      timezone,
+					QueryType:     models.INSTANT_QUERY,
+					ValueFieldKey: "usage_idle",
+				},
+			},
+			want:    expected,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -352,7 +379,7 @@ func TestQueryCommandRunner_Run_Matrix_Graph(t *testing.T) {
 	defer ctrl.Finish()
 
 	database := "telegraf"
-	influxCmd := "SELECT *::tag, max(usage_idle) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' AND host =~ /^(?:tele.*)$/ GROUP BY *, time(5m) TZ('Asia/Shanghai')"
+	influxCmd := "SELECT *::tag, max(usage_idle) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:55:00Z' AND host =~ /^(?:tele.*)$/ GROUP BY *, time(5m) fill(none) TZ('Asia/Shanghai')"
 
 	var response client.Response
 	testFile := filepath.Join(testDir, "querycommandrunner_test_response3.json")
@@ -370,7 +397,7 @@ func TestQueryCommandRunner_Run_Matrix_Graph(t *testing.T) {
 		Return(&response, nil).
 		AnyTimes()
 
-	expectedJson := `{"Result":[{"metric":{"__name__":"cpu","cpu":"cpu-total","host":"telegraf"},"values":[[1672977600,"90.74457083759839"],[1672977900,"90.75324675339002"],[1672978200,"90.31413612562693"],[1672978500,"90.60887512896518"],[1672978800,"89.13721413722703"],[1672979100,"88.36006207967873"],[1672979400,"91.61021365301805"],[1672979700,"90.26411185911505"],[1672980000,"90.93277748832477"],[1672980300,"90.05208333332118"],[1672980600,"90.03147953819195"],[1672980900,"89.74895397493552"],[1672981200,"89.86415882965589"],[1672981500,"90.03131524020385"],[1672981800,"90.83810515352737"],[1672982100,"89.53427524855331"],[1672982400,"90.89490114462777"],[1672982700,"90.45643153542379"],[1672983000,"89.66421825812485"],[1672983300,"91.16735537208687"],[1672983600,"90.10256410271963"],[1672983900,"91.60621761659938"],[1672984200,"90.92331768379768"],[1672984500,"89.690721649616"],[1672984800,"89.66770508816808"],[1672985100,"91.13070539409503"],[1672985400,"90.49095607223623"],[1672985700,"91.95402298846905"],[1672986000,"90.30745179773368"],[1672986300,"91.20082815729282"],[1672986600,"90.0207900208699"],[1672986900,"91.16883116864621"],[1672987200,"90.68450849203128"],[1672987500,"90.39958484689137"],[1672987800,"90.79627714595279"],[1672988100,"90.08307372792021"],[1672988400,"72.8971962615382"]]},{"metric":{"__name__":"cpu","cpu":"cpu0","host":"telegraf"},"values":[[1672977600,"90.20618556703738"],[1672977900,"89.62655601659688"],[1672978200,"89.35950413225174"],[1672978500,"89.73305954826014"],[1672978800,"86.57024793386474"],[1672979100,"90.6952965234944"],[1672979400,"92.32343909928672"],[1672979700,"88.70466321246461"],[1672980000,"89.9377593360869"],[1672980300,"88.96982310094472"],[1672980600,"89.51781970650791"],[1672980900,"88.96982310096142"],[1672981200,"89.31140801637174"],[1672981500,"88.94681960377416"],[1672981800,"90.95634095638498"],[1672982100,"88.72651357005597"],[1672982400,"91.53766769873853"],[1672982700,"90.3292181071228"],[1672983000,"89.20041536871138"],[1672983300,"90.81527347794298"],[1672983600,"89.73577235777317"],[1672983900,"90.67357512931491"],[1672984200,"89.93775933622267"],[1672984500,"88.79753340201395"],[1672984800,"88.72802481919553"],[1672985100,"90.75804776752726"],[1672985400,"89.51695786236921"],[1672985700,"93.82716049376802"],[1672986000,"89.61578400831239"],[1672986300,"89.18640576709376"],[1672986600,"88.75128998956865"],[1672986900,"90.16563147001298"],[1672987200,"90.36885245900173"],[1672987500,"93.32648870641889"],[1672987800,"90.08264462802588"],[1672988100,"89.38605619117084"],[1672988400,"71.00103199162488"]]},{"metric":{"__name__":"cpu","cpu":"cpu1","host":"telegraf"},"values":[[1672977600,"91.4315569487316"],[1672977900,"91.97916666661693"],[1672978200,"91.58780231347214"],[1672978500,"91.5800415800617"],[1672978800,"93.0062630480362"],[1672979100,"88.67924528301023"],[1672979400,"92.73858921150801"],[1672979700,"91.99584199580788"],[1672980000,"91.64926931089664"],[1672980300,"91.04166666665246"],[1672980600,"90.71729957814522"],[1672980900,"90.63157894737455"],[1672981200,"90.90909090913237"],[1672981500,"91.29979035636998"],[1672981800,"91.39559286471633"],[1672982100,"90.24134312690178"],[1672982400,"91.16424116418975"],[1672982700,"90.59561128518209"],[1672983000,"90.04237288133372"],[1672983300,"92.04188481679908"],[1672983600,"90.38262668023901"],[1672983900,"92.53886010361038"],[1672984200,"92.11356466877744"],[1672984500,"90.76763485464025"],[1672984800,"90.53069719025379"],[1672985100,"91.50259067364841"],[1672985400,"91.476091476166"],[1672985700,"89.81972428427824"],[1672986000,"91.18572927600619"],[1672986300,"93.34027055165664"],[1672986600,"91.39559286471633"],[1672986900,"91.97916666662908"],[1672987200,"91.09730848845939"],[1672987500,"88.42105263178856"],[1672987800,"91.45833333326576"],[1672988100,"90.67357512958837"],[1672988400,"74.81713688631181"]]}],"ResultType":"matrix","Error":null}`
+	expectedJson := `{"Result":[{"metric":{"__name__":"cpu","cpu":"cpu-total","host":"telegraf"},"values":[[1672977900,"90.74457083759839"],[1672978200,"90.75324675339002"],[1672978500,"90.31413612562693"],[1672978800,"90.60887512896518"],[1672979100,"89.13721413722703"],[1672979400,"88.36006207967873"],[1672979700,"91.61021365301805"],[1672980000,"90.26411185911505"],[1672980300,"90.93277748832477"],[1672980600,"90.05208333332118"],[1672980900,"90.03147953819195"],[1672981200,"89.74895397493552"],[1672981500,"89.86415882965589"],[1672981800,"90.03131524020385"],[1672982100,"90.83810515352737"],[1672982400,"89.53427524855331"],[1672982700,"90.89490114462777"],[1672983000,"90.45643153542379"],[1672983300,"89.66421825812485"],[1672983600,"91.16735537208687"],[1672983900,"90.10256410271963"],[1672984200,"91.60621761659938"],[1672984500,"90.92331768379768"],[1672984800,"89.690721649616"],[1672985100,"89.66770508816808"],[1672985400,"91.13070539409503"],[1672985700,"90.49095607223623"],[1672986000,"91.95402298846905"],[1672986300,"90.30745179773368"],[1672986600,"91.20082815729282"],[1672986900,"90.0207900208699"],[1672987200,"91.16883116864621"],[1672987500,"90.68450849203128"],[1672987800,"90.39958484689137"],[1672988100,"90.79627714595279"],[1672988400,"90.08307372792021"]]},{"metric":{"__name__":"cpu","cpu":"cpu0","host":"telegraf"},"values":[[1672977900,"90.20618556703738"],[1672978200,"89.62655601659688"],[1672978500,"89.35950413225174"],[1672978800,"89.73305954826014"],[1672979100,"86.57024793386474"],[1672979400,"90.6952965234944"],[1672979700,"92.32343909928672"],[1672980000,"88.70466321246461"],[1672980300,"89.9377593360869"],[1672980600,"88.96982310094472"],[1672980900,"89.51781970650791"],[1672981200,"88.96982310096142"],[1672981500,"89.31140801637174"],[1672981800,"88.94681960377416"],[1672982100,"90.95634095638498"],[1672982400,"88.72651357005597"],[1672982700,"91.53766769873853"],[1672983000,"90.3292181071228"],[1672983300,"89.20041536871138"],[1672983600,"90.81527347794298"],[1672983900,"89.73577235777317"],[1672984200,"90.67357512931491"],[1672984500,"89.93775933622267"],[1672984800,"88.79753340201395"],[1672985100,"88.72802481919553"],[1672985400,"90.75804776752726"],[1672985700,"89.51695786236921"],[1672986000,"93.82716049376802"],[1672986300,"89.61578400831239"],[1672986600,"89.18640576709376"],[1672986900,"88.75128998956865"],[1672987200,"90.16563147001298"],[1672987500,"90.36885245900173"],[1672987800,"93.32648870641889"],[1672988100,"90.08264462802588"],[1672988400,"89.38605619117084"]]},{"metric":{"__name__":"cpu","cpu":"cpu1","host":"telegraf"},"values":[[1672977900,"91.4315569487316"],[1672978200,"91.97916666661693"],[1672978500,"91.58780231347214"],[1672978800,"91.5800415800617"],[1672979100,"93.0062630480362"],[1672979400,"88.67924528301023"],[1672979700,"92.73858921150801"],[1672980000,"91.99584199580788"],[1672980300,"91.64926931089664"],[1672980600,"91.04166666665246"],[1672980900,"90.71729957814522"],[1672981200,"90.63157894737455"],[1672981500,"90.90909090913237"],[1672981800,"91.29979035636998"],[1672982100,"91.39559286471633"],[1672982400,"90.24134312690178"],[1672982700,"91.16424116418975"],[1672983000,"90.59561128518209"],[1672983300,"90.04237288133372"],[1672983600,"92.04188481679908"],[1672983900,"90.38262668023901"],[1672984200,"92.53886010361038"],[1672984500,"92.11356466877744"],[1672984800,"90.76763485464025"],[1672985100,"90.53069719025379"],[1672985400,"91.50259067364841"],[1672985700,"91.476091476166"],[1672986000,"89.81972428427824"],[1672986300,"91.18572927600619"],[1672986600,"93.34027055165664"],[1672986900,"91.39559286471633"],[1672987200,"91.97916666662908"],[1672987500,"91.09730848845939"],[1672987800,"88.42105263178856"],[1672988100,"91.45833333326576"],[1672988400,"90.67357512958837"]]}],"ResultType":"matrix","Error":null}`
 
 	var expected map[string]interface{}
 	if err = json.Unmarshal([]byte(expectedJson), &expected); err != nil {
@@ -409,6 +436,7 @@ func TestQueryCommandRunner_Run_Matrix_Graph(t *testing.T) {
 					Cmd:           `max_over_time(cpu{host=~"tele.*"}[5m])`,
 					Database:      "telegraf",
 					Start:         &startTime2,
+					QueryType:     models.RANGE_QUERY,
 					End:           &endTime2,
 					Timezone:      timezone,
 					Evaluation:    nil,
@@ -450,6 +478,11 @@ func TestQueryCommandRunner_Run_Grafana_Datasource_Test(t *testing.T) {
 	if err := json.Unmarshal([]byte(expectedJson), &expected); err != nil {
 		t.Fatal(err)
 	}
+	expectedInfJson := `{"Result":[1672988400,"+Inf"],"ResultType":"scalar","Error":null}`
+	var expectedInf map[string]interface{}
+	if err := json.Unmarshal([]byte(expectedInfJson), &expectedInf); err != nil {
+		t.Fatal(err)
+	}
 
 	type fields struct {
 		Cfg     QueryCommandRunnerConfig
@@ -489,6 +522,27 @@ func TestQueryCommandRunner_Run_Grafana_Datasource_Test(t *testing.T) {
 			want:    expected,
 			wantErr: false,
 		},
+		{
+			name: "",
+			fields: fields{
+				Cfg: QueryCommandRunnerConfig{
+					Timeout: MustParseDuration("1m", t),
+					Verbose: true,
+				},
+				Factory: SingletonQueryCommandRunnerFactory,
+			},
+			args: args{
+				ctx: context.Background(),
+				cmd: models.PromCommand{
+					Cmd:      `1/0`,
+					Database: "prometheus",
+					End:      &endTime2,
+					Timezone: timezone,
+				},
+			},
+			want:    expectedInf,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -846,6 +900,192 @@ func TestQueryCommandRunner_Run_LabelValuesNoCmd(t *testing.T) {
 	}
 }
 
+func TestQueryCommandRunner_Run_MetricNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := "prometheus"
+	response := client.Response{
+		Results: []client.Result{
+			{
+				Series: []influxmodels.Row{
+					{
+						Name:    "measurements",
+						Columns: []string{"name"},
+						Values: [][]interface{}{
+							{"go_threads"},
+							{"go_goroutines"},
+							{"go_gc_duration_seconds"},
+						},
+					},
+				},
+			},
+		},
+	}
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SHOW MEASUREMENTS ON prometheus WITH MEASUREMENT =~ /^(?:go_.*)$/ WHERE job = 'prometheus'`, database, "")).
+		Return(&response, nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SHOW MEASUREMENTS ON prometheus`, database, "")).
+		Return(&response, nil).
+		AnyTimes()
+
+	tests := []struct {
+		name    string
+		cmd     string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:    "",
+			cmd:     `{__name__=~"go_.*", __name__!="go_threads", job="prometheus"}`,
+			want:    []string{"go_gc_duration_seconds", "go_goroutines"},
+			wantErr: false,
+		},
+		{
+			name:    "",
+			cmd:     "",
+			want:    []string{"go_gc_duration_seconds", "go_goroutines", "go_threads"},
+			wantErr: false,
+		},
+		{
+			name:    "",
+			cmd:     `rate(go_goroutines[5m])`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &QueryCommandRunner{
+				Cfg: QueryCommandRunnerConfig{
+					Timeout: MustParseDuration("1m", t),
+				},
+				Client: mockClient,
+			}
+			got, err := receiver.Run(context.Background(), models.PromCommand{
+				Cmd:       tt.cmd,
+				Database:  database,
+				End:       &endTime3,
+				Timezone:  timezone,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.LABEL_VALUES_DATA,
+				LabelName: "__name__",
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Result, tt.want) {
+				t.Errorf("Run() got = %v, want %v", got.Result, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryCommandRunner_Run_Metadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := "prometheus"
+	response := client.Response{
+		Results: []client.Result{
+			{
+				Series: []influxmodels.Row{
+					{
+						Name:    "go_goroutines",
+						Columns: []string{"fieldKey", "fieldType"},
+						Values:  [][]interface{}{{"value", "float"}},
+					},
+					{
+						Name:    "http_request_duration_seconds_bucket",
+						Columns: []string{"fieldKey", "fieldType"},
+						Values:  [][]interface{}{{"value", "float"}},
+					},
+					{
+						Name:    "http_request_duration_seconds_count",
+						Columns: []string{"fieldKey", "fieldType"},
+						Values:  [][]interface{}{{"value", "float"}},
+					},
+					{
+						Name:    "http_request_duration_seconds_sum",
+						Columns: []string{"fieldKey", "fieldType"},
+						Values:  [][]interface{}{{"value", "float"}},
+					},
+					{
+						Name:    "http_requests_total",
+						Columns: []string{"fieldKey", "fieldType"},
+						Values:  [][]interface{}{{"value", "float"}},
+					},
+					{
+						Name:    "build_info",
+						Columns: []string{"fieldKey", "fieldType"},
+						Values:  [][]interface{}{{"value", "string"}},
+					},
+				},
+			},
+		},
+	}
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SHOW FIELD KEYS ON prometheus`, database, "")).
+		Return(&response, nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SHOW FIELD KEYS ON prometheus FROM /^http_request_duration_seconds(?:_bucket|_sum|_count)?$/`, database, "")).
+		Return(&response, nil).
+		AnyTimes()
+
+	tests := []struct {
+		name    string
+		cmd     string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "",
+			cmd:  "",
+			want: map[string]textparse.MetricType{
+				"go_goroutines":                 textparse.MetricTypeGauge,
+				"http_request_duration_seconds": textparse.MetricTypeHistogram,
+				"http_requests_total":           textparse.MetricTypeCounter,
+			},
+			wantErr: false,
+		},
+		{
+			name: "",
+			cmd:  "http_request_duration_seconds",
+			want: map[string]textparse.MetricType{
+				"http_request_duration_seconds": textparse.MetricTypeHistogram,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &QueryCommandRunner{
+				Cfg: QueryCommandRunnerConfig{
+					Timeout: MustParseDuration("1m", t),
+				},
+				Client: mockClient,
+			}
+			got, err := receiver.Run(context.Background(), models.PromCommand{
+				Cmd:      tt.cmd,
+				Database: database,
+				DataType: models.METADATA_DATA,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Result, tt.want) {
+				t.Errorf("Run() got = %v, want %v", got.Result, tt.want)
+			}
+		})
+	}
+}
+
 func TestQueryCommandRunner_Run_ContextCancel(t *testing.T) {
 	receiver := &QueryCommandRunner{}
 	ctx, cancel := context.WithCancel(context.Background())
@@ -856,12 +1096,44 @@ func TestQueryCommandRunner_Run_ContextCancel(t *testing.T) {
 }
 
 func TestQueryCommandRunner_Run_ContextDeadlineExceeded(t *testing.T) {
-	receiver := &QueryCommandRunner{}
-	got, err := receiver.Run(context.Background(), models.PromCommand{})
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.
+		EXPECT().Query(gomock.Any()).
+		DoAndReturn(func(q client.Query) (*client.Response, error) {
+			time.Sleep(100 * time.Millisecond)
+			return &client.Response{}, nil
+		}).
+		AnyTimes()
+	receiver := &QueryCommandRunner{
+		Client: mockClient,
+	}
+	got, err := receiver.Run(context.Background(), models.PromCommand{
+		DataType: models.LABEL_NAMES_DATA,
+	})
 	assert.True(t, errors.Is(err, context.DeadlineExceeded))
 	assert.Zero(t, got)
 }
 
+func TestQueryCommandRunner_Run_EmptyCmd(t *testing.T) {
+	receiver := &QueryCommandRunner{
+		Cfg: QueryCommandRunnerConfig{
+			Timeout: 60 * time.Second,
+		},
+	}
+	for _, dataType := range []models.DataType{models.TABLE_DATA, models.GRAPH_DATA, models.SERIES_DATA, 0} {
+		start := time.Now()
+		got, err := receiver.Run(context.Background(), models.PromCommand{
+			DataType: dataType,
+		})
+		assert.EqualError(t, err, fmt.Sprintf("empty command of data type %d is not supported", dataType))
+		assert.Zero(t, got)
+		assert.Less(t, time.Since(start), time.Second)
+	}
+}
+
 func TestQueryCommandRunner_Run_ContextDeadlineExceeded1(t *testing.T) {
 	receiver := &QueryCommandRunner{
 		Cfg: QueryCommandRunnerConfig{
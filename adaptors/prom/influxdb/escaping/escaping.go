@@ -0,0 +1,113 @@
+// Package escaping implements reversible escaping of InfluxDB measurement names and tag keys that are not valid
+// Prometheus metric names or label names, e.g. cpu.usage measurement or http-method tag key written by Telegraf.
+//
+// It follows the value encoding escaping scheme of Prometheus 3:
+//   - valid names are kept as they are
+//   - other names are prefixed with U__, underscores are doubled and each invalid character is replaced
+//     with its lowercase hex code point surrounded by underscores
+//
+// For example, cpu.usage is escaped to U__cpu_2e_usage and http-method is escaped to U__http_2d_method.
+// Valid names starting with U__ are escaped as well, so that UnescapeName is always the inverse of escaping.
+package escaping
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const escapedPrefix = "U__"
+
+// EscapeMetricName escapes InfluxDB measurement name to valid Prometheus metric name
+func EscapeMetricName(name string) string {
+	return escapeName(name, true)
+}
+
+// EscapeLabelName escapes InfluxDB tag key to valid Prometheus label name
+func EscapeLabelName(name string) string {
+	return escapeName(name, false)
+}
+
+// UnescapeName reverses EscapeMetricName and EscapeLabelName. Names that are not escaped or
+// not escaped correctly are returned as they are.
+func UnescapeName(name string) string {
+	if !strings.HasPrefix(name, escapedPrefix) {
+		return name
+	}
+	escapedName := strings.TrimPrefix(name, escapedPrefix)
+	var sb strings.Builder
+TOP:
+	for i := 0; i < len(escapedName); i++ {
+		if escapedName[i] != '_' {
+			sb.WriteByte(escapedName[i])
+			continue
+		}
+		i++
+		if i >= len(escapedName) {
+			return name
+		}
+		// a double underscore is a single underscore
+		if escapedName[i] == '_' {
+			sb.WriteByte('_')
+			continue
+		}
+		var codePoint rune
+		// the max code point \U0010FFFF has 6 hex digits
+		for j := 0; i < len(escapedName) && j <= 6; j++ {
+			if escapedName[i] == '_' {
+				if !utf8.ValidRune(codePoint) {
+					return name
+				}
+				sb.WriteRune(codePoint)
+				continue TOP
+			}
+			digit, err := strconv.ParseUint(escapedName[i:i+1], 16, 8)
+			if err != nil {
+				return name
+			}
+			codePoint = codePoint*16 + rune(digit)
+			i++
+		}
+		// closing underscore not found
+		return name
+	}
+	return sb.String()
+}
+
+func escapeName(name string, allowColon bool) string {
+	if name == "" {
+		return name
+	}
+	if isValidName(name, allowColon) && !strings.HasPrefix(name, escapedPrefix) {
+		return name
+	}
+	var sb strings.Builder
+	sb.WriteString(escapedPrefix)
+	for i, r := range name {
+		switch {
+		case r == '_':
+			sb.WriteString("__")
+		case isValidRune(r, i, allowColon):
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+			sb.WriteString(strconv.FormatInt(int64(r), 16))
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+func isValidName(name string, allowColon bool) bool {
+	for i, r := range name {
+		if !isValidRune(r, i, allowColon) {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidRune checks r against [a-zA-Z_:][a-zA-Z0-9_:]* for metric names and [a-zA-Z_][a-zA-Z0-9_]* for label names
+func isValidRune(r rune, i int, allowColon bool) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || (allowColon && r == ':') || (r >= '0' && r <= '9' && i > 0)
+}
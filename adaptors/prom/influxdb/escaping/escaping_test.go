@@ -0,0 +1,138 @@
+package escaping
+
+import (
+	"regexp"
+	"testing"
+)
+
+var (
+	metricNameRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRE  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+func TestEscapeName(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantMetric string
+		wantLabel  string
+	}{
+		{
+			name:       "",
+			input:      "go_goroutines",
+			wantMetric: "go_goroutines",
+			wantLabel:  "go_goroutines",
+		},
+		{
+			name:       "",
+			input:      "cpu.usage",
+			wantMetric: "U__cpu_2e_usage",
+			wantLabel:  "U__cpu_2e_usage",
+		},
+		{
+			name:       "",
+			input:      "http-requests_total",
+			wantMetric: "U__http_2d_requests__total",
+			wantLabel:  "U__http_2d_requests__total",
+		},
+		{
+			name:       "",
+			input:      "job:rate5m",
+			wantMetric: "job:rate5m",
+			wantLabel:  "U__job_3a_rate5m",
+		},
+		{
+			name:       "",
+			input:      "1xx",
+			wantMetric: "U___31_xx",
+			wantLabel:  "U___31_xx",
+		},
+		{
+			name:       "",
+			input:      "U__cpu",
+			wantMetric: "U__U____cpu",
+			wantLabel:  "U__U____cpu",
+		},
+		{
+			name:       "",
+			input:      "温度",
+			wantMetric: "U___6e29__5ea6_",
+			wantLabel:  "U___6e29__5ea6_",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMetric := EscapeMetricName(tt.input)
+			if gotMetric != tt.wantMetric {
+				t.Errorf("EscapeMetricName() = %v, want %v", gotMetric, tt.wantMetric)
+			}
+			if !metricNameRE.MatchString(gotMetric) {
+				t.Errorf("EscapeMetricName() = %v is not valid metric name", gotMetric)
+			}
+			gotLabel := EscapeLabelName(tt.input)
+			if gotLabel != tt.wantLabel {
+				t.Errorf("EscapeLabelName() = %v, want %v", gotLabel, tt.wantLabel)
+			}
+			if !labelNameRE.MatchString(gotLabel) {
+				t.Errorf("EscapeLabelName() = %v is not valid label name", gotLabel)
+			}
+			if got := UnescapeName(gotMetric); got != tt.input {
+				t.Errorf("UnescapeName(%v) = %v, want %v", gotMetric, got, tt.input)
+			}
+			if got := UnescapeName(gotLabel); got != tt.input {
+				t.Errorf("UnescapeName(%v) = %v, want %v", gotLabel, got, tt.input)
+			}
+		})
+	}
+}
+
+func TestUnescapeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "",
+			input: "cpu",
+			want:  "cpu",
+		},
+		{
+			name:  "",
+			input: "U__cpu_2E_usage",
+			want:  "cpu.usage",
+		},
+		{
+			name:  "",
+			input: "U__cpu_",
+			want:  "U__cpu_",
+		},
+		{
+			name:  "",
+			input: "U__cpu_2e",
+			want:  "U__cpu_2e",
+		},
+		{
+			name:  "",
+			input: "U__cpu_zz_",
+			want:  "U__cpu_zz_",
+		},
+		{
+			name:  "",
+			input: "U__cpu_d800_",
+			want:  "U__cpu_d800_",
+		},
+		{
+			name:  "",
+			input: "U__cpu_1234567_",
+			want:  "U__cpu_1234567_",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnescapeName(tt.input); got != tt.want {
+				t.Errorf("UnescapeName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
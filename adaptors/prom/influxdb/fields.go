@@ -0,0 +1,369 @@
+package influxdb
+
+import (
+	influxdb "github.com/influxdata/influxdb1-client/v2"
+	"github.com/influxdata/influxql"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// maxConcurrentFieldEvaluations limits how many fields selected by a VectorSelector are evaluated against InfluxDB at the same time
+const maxConcurrentFieldEvaluations = 4
+
+// fieldSelectors returns VectorSelector in expr whose field keys are resolved before transpiling, in the order of parser.Inspect,
+// along with the names of the label matchers to be resolved. They are the ones having transpiler.FieldLabelName matchers,
+// and the ones mapped to schema.Source whose FieldLabel isn't selected by an equality matcher, like histogram buckets
+// and summaries written by Telegraf with metric_version = 1 selected without le or quantile label.
+func (receiver *QueryCommandRunner) fieldSelectors(cmd models.PromCommand, expr parser.Expr) ([]*parser.VectorSelector, []string) {
+	var (
+		selectors   []*parser.VectorSelector
+		fieldLabels []string
+	)
+	t := &transpiler.Transpiler{
+		PromCommand:  cmd,
+		SchemaMapper: receiver.schemaMapper(),
+		FieldKeys:    receiver.fieldKeys(cmd),
+	}
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		v, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		for _, item := range v.LabelMatchers {
+			if item.Name == transpiler.FieldLabelName {
+				selectors = append(selectors, v)
+				fieldLabels = append(fieldLabels, transpiler.FieldLabelName)
+				return nil
+			}
+		}
+		// Selectors failing to be mapped are left to the transpiler to report the error
+		if source, err := t.SelectorSource(v); err == nil && source.FieldKey == "" && source.FieldLabel != "" {
+			selectors = append(selectors, v)
+			fieldLabels = append(fieldLabels, source.FieldLabel)
+		}
+		return nil
+	})
+	return selectors, fieldLabels
+}
+
+// handleMultiFieldCase evaluates PromQL expression containing VectorSelector returned by fieldSelectors.
+// The label matchers of each VectorSelector are resolved to field keys of its own measurement at first, and rewritten to
+// an equality matcher which the transpiler takes as the field key. A VectorSelector selecting no field selects no series,
+// so the result is empty if that empties the expression, see emptiedBy. At most one VectorSelector may select more than one field,
+// then the expression is evaluated once for each of its fields, at most maxConcurrentFieldEvaluations at the same time.
+// As a consequence, aggregations are calculated per field.
+// Result series of the fields selected by transpiler.FieldLabelName are labeled with it at last, if they differ from evaluation
+// to evaluation or the expression has only one VectorSelector. The other fields are labeled by the schema mapper.
+func (receiver *QueryCommandRunner) handleMultiFieldCase(cmd models.PromCommand, expr parser.Expr, selectors []*parser.VectorSelector,
+	fieldLabels []string, resultChan chan models.RunResult, handleErr func(err error)) {
+	selectorFieldKeys := make([][]string, len(selectors))
+	// varying is the index of the VectorSelector selecting more than one field, -1 means none
+	varying := -1
+	empty := false
+	for i, selector := range selectors {
+		fieldKeys, err := receiver.findFieldKeys(cmd, selector, fieldLabels[i])
+		if err != nil {
+			handleErr(errors.Wrap(err, "fail to find field keys"))
+			return
+		}
+		if len(fieldKeys) == 0 {
+			if !emptiedBy(expr, selector) {
+				handleErr(errors.Errorf("%s selects no field, which can't be evaluated in %s", selector, expr))
+				return
+			}
+			if receiver.Cfg.Verbose {
+				zlogger.Info().Msgf("%s selects no field, so %s is empty", selector, expr)
+			}
+			empty = true
+		}
+		if len(fieldKeys) > 1 {
+			if varying >= 0 {
+				handleErr(errors.New("only one vector selector can select more than one field"))
+				return
+			}
+			varying = i
+		}
+		selectorFieldKeys[i] = fieldKeys
+	}
+	if empty {
+		result, resultType, err := receiver.InfluxResultToPromQLValue([]influxdb.Result{{}}, expr, cmd)
+		if err != nil {
+			handleErr(err)
+			return
+		}
+		resultChan <- models.RunResult{
+			Result:     result,
+			ResultType: resultType,
+		}
+		return
+	}
+	// labeled is the index of the VectorSelector whose field keys label the result series with transpiler.FieldLabelName,
+	// -1 means none
+	labeled := -1
+	switch {
+	case varying >= 0:
+		if fieldLabels[varying] == transpiler.FieldLabelName {
+			labeled = varying
+		}
+	case len(selectors) == 1 && len(parser.ExtractSelectors(expr)) == 1:
+		if fieldLabels[0] == transpiler.FieldLabelName {
+			labeled = 0
+		}
+	}
+	evaluations := 1
+	if varying >= 0 {
+		evaluations = len(selectorFieldKeys[varying])
+	}
+	// fieldKey returns the field key of the i-th VectorSelector in the n-th evaluation
+	fieldKey := func(i, n int) string {
+		if i == varying {
+			return selectorFieldKeys[i][n]
+		}
+		return selectorFieldKeys[i][0]
+	}
+	fieldResults := make([]models.RunResult, evaluations)
+	var wg sync.WaitGroup
+	wg.Add(evaluations)
+	sem := make(chan struct{}, maxConcurrentFieldEvaluations)
+	for n := 0; n < evaluations; n++ {
+		sem <- struct{}{}
+		go func(n int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			// The transpiler may modify the ast, so each evaluation needs its own one.
+			fieldExpr, err := parser.ParseExpr(cmd.Cmd)
+			if err != nil {
+				fieldResults[n] = models.RunResult{Error: err}
+				return
+			}
+			fieldSelectors, _ := receiver.fieldSelectors(cmd, fieldExpr)
+			for i, selector := range fieldSelectors {
+				selector.LabelMatchers = withFieldMatcher(selector.LabelMatchers, fieldLabels[i], fieldKey(i, n))
+			}
+			fieldResultChan := make(chan models.RunResult, 1)
+			receiver.handleExpr(cmd, fieldExpr, fieldResultChan, func(err error) {
+				fieldResultChan <- models.RunResult{
+					Error: err,
+				}
+			})
+			fieldResults[n] = <-fieldResultChan
+		}(n)
+	}
+	wg.Wait()
+	if evaluations == 1 && labeled < 0 {
+		// Nothing to be merged or labeled, the result is returned as it is
+		if fieldResults[0].Error != nil {
+			handleErr(fieldResults[0].Error)
+			return
+		}
+		resultChan <- fieldResults[0]
+		return
+	}
+	var (
+		matrix     promql.Matrix
+		vector     promql.Vector
+		resultType string
+	)
+	label := func(metric labels.Labels, n int) labels.Labels {
+		if labeled < 0 {
+			return metric
+		}
+		return withFieldLabel(metric, fieldKey(labeled, n))
+	}
+	for n, fieldResult := range fieldResults {
+		if fieldResult.Error != nil {
+			handleErr(fieldResult.Error)
+			return
+		}
+		resultType = fieldResult.ResultType
+		switch value := fieldResult.Result.(type) {
+		case promql.Matrix:
+			for _, series := range value {
+				series.Metric = label(series.Metric, n)
+				matrix = append(matrix, series)
+			}
+		case promql.Vector:
+			for _, sample := range value {
+				sample.Metric = label(sample.Metric, n)
+				vector = append(vector, sample)
+			}
+		default:
+			// Values without series like scalars have nothing to be labeled
+			if evaluations == 1 {
+				resultChan <- fieldResult
+				return
+			}
+			handleErr(errors.Errorf("unsupported PromQL value type for more than one field: %T", value))
+			return
+		}
+	}
+	var result interface{}
+	switch parser.ValueType(resultType) {
+	case parser.ValueTypeMatrix:
+		if matrix == nil {
+			matrix = promql.Matrix{}
+		}
+		sort.Sort(matrix)
+		result = matrix
+	default:
+		if vector == nil {
+			vector = promql.Vector{}
+		}
+		result = vector
+	}
+	resultChan <- models.RunResult{
+		Result:     result,
+		ResultType: resultType,
+	}
+}
+
+// emptiedBy checks the result of expr is empty if selector selects no series. It is not the case if selector is under
+// the PromQL or operator, the right-hand side of unless operator, or absent and absent_over_time functions.
+func emptiedBy(expr parser.Expr, selector *parser.VectorSelector) bool {
+	emptied := true
+	parser.Inspect(expr, func(node parser.Node, path []parser.Node) error {
+		if node != selector {
+			return nil
+		}
+		for i, parent := range path {
+			var child parser.Node = node
+			if i+1 < len(path) {
+				child = path[i+1]
+			}
+			switch p := parent.(type) {
+			case *parser.BinaryExpr:
+				if p.Op == parser.LOR || (p.Op == parser.LUNLESS && child == p.RHS) {
+					emptied = false
+				}
+			case *parser.Call:
+				if p.Func.Name == "absent" || p.Func.Name == "absent_over_time" {
+					emptied = false
+				}
+			}
+		}
+		return nil
+	})
+	return emptied
+}
+
+// findFieldKeys returns sorted field keys matching all fieldLabel matchers of selector. They are looked up in the measurement
+// which the schema mapper maps selector to. If there is an equality matcher, InfluxDB is not queried. Field keys resolved from
+// the other labels than transpiler.FieldLabelName must be numbers, which tells them from the other fields like sum and count.
+func (receiver *QueryCommandRunner) findFieldKeys(cmd models.PromCommand, selector *parser.VectorSelector, fieldLabel string) ([]string, error) {
+	var matchers []*labels.Matcher
+	for _, matcher := range selector.LabelMatchers {
+		if matcher.Name == fieldLabel {
+			matchers = append(matchers, matcher)
+		}
+	}
+	var candidates []string
+	for _, matcher := range matchers {
+		if matcher.Type == labels.MatchEqual {
+			candidates = []string{matcher.Value}
+			break
+		}
+	}
+	if candidates == nil {
+		t := &transpiler.Transpiler{
+			PromCommand:  cmd,
+			SchemaMapper: receiver.schemaMapper(),
+			FieldKeys:    receiver.fieldKeys(cmd),
+		}
+		source, err := t.SelectorSource(selector)
+		if err != nil {
+			return nil, err
+		}
+		if candidates, err = receiver.measurementFieldKeys(cmd, source.Measurement); err != nil {
+			return nil, err
+		}
+	}
+	var fieldKeys []string
+	seen := make(map[string]struct{})
+LOOP:
+	for _, candidate := range candidates {
+		if _, exists := seen[candidate]; exists || candidate == "" {
+			continue
+		}
+		seen[candidate] = struct{}{}
+		if fieldLabel != transpiler.FieldLabelName {
+			if _, err := strconv.ParseFloat(candidate, 64); err != nil {
+				continue
+			}
+		}
+		for _, matcher := range matchers {
+			if !matcher.Matches(candidate) {
+				continue LOOP
+			}
+		}
+		fieldKeys = append(fieldKeys, candidate)
+	}
+	sort.Strings(fieldKeys)
+	return fieldKeys, nil
+}
+
+// measurementFieldKeys returns field keys of measurement in cmd.Database
+func (receiver *QueryCommandRunner) measurementFieldKeys(cmd models.PromCommand, measurement string) ([]string, error) {
+	statement := &influxql.ShowFieldKeysStatement{
+		Database: cmd.Database,
+		Sources:  []influxql.Source{&influxql.Measurement{Name: measurement}},
+	}
+	resp, err := receiver.Client.Query(influxdb.NewQuery(statement.String(), cmd.Database, ""))
+	if err != nil {
+		return nil, errors.Wrap(err, "error from influxdb api")
+	}
+	if stringutils.IsNotEmpty(resp.Err) {
+		return nil, errors.Errorf("error from influxdb api: %s", resp.Err)
+	}
+	var fieldKeys []string
+	for _, result := range resp.Results {
+		if stringutils.IsNotEmpty(result.Err) {
+			return nil, errors.New(result.Err)
+		}
+		// SHOW FIELD KEYS returns fieldKey and fieldType columns for each measurement
+		for _, series := range result.Series {
+			for _, row := range series.Values {
+				if fieldKey, ok := row[0].(string); ok {
+					fieldKeys = append(fieldKeys, fieldKey)
+				}
+			}
+		}
+	}
+	return fieldKeys, nil
+}
+
+// fieldKeys returns transpiler.Transpiler's FieldKeys looking up measurements in cmd.Database
+func (receiver *QueryCommandRunner) fieldKeys(cmd models.PromCommand) func(measurement string) ([]string, error) {
+	return func(measurement string) ([]string, error) {
+		return receiver.measurementFieldKeys(cmd, measurement)
+	}
+}
+
+// withFieldMatcher returns a copy of matchers with fieldLabel matchers replaced by an equality matcher of fieldKey
+func withFieldMatcher(matchers []*labels.Matcher, fieldLabel string, fieldKey string) []*labels.Matcher {
+	result := make([]*labels.Matcher, 0, len(matchers))
+	for _, matcher := range matchers {
+		if matcher.Name != fieldLabel {
+			result = append(result, matcher)
+		}
+	}
+	return append(result, labels.MustNewMatcher(labels.MatchEqual, fieldLabel, fieldKey))
+}
+
+// withFieldLabel returns a copy of metric with transpiler.FieldLabelName label
+func withFieldLabel(metric labels.Labels, fieldKey string) labels.Labels {
+	return append(metric.Copy(), labels.Label{
+		Name:  transpiler.FieldLabelName,
+		Value: fieldKey,
+	})
+}
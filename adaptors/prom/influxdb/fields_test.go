@@ -0,0 +1,451 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/copier"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/mock"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func mustUnmarshalResponse(t *testing.T, responseJson string) *client.Response {
+	var response client.Response
+	if err := json.Unmarshal([]byte(responseJson), &response); err != nil {
+		t.Fatal(err)
+	}
+	return &response
+}
+
+func TestQueryCommandRunner_Run_MultiField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := "telegraf"
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM cpu", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","columns":["fieldKey","fieldType"],"values":[["usage_idle","float"],["usage_system","float"],["usage_user","float"]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_idle) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND host = 'telegraf' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",84.9]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_user) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND host = 'telegraf' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",10.1]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_system) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND host = 'telegraf' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",5]]}]}]}`), nil).
+		AnyTimes()
+
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_idle) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:00Z' AND host = 'telegraf' GROUP BY *, time(1m) fill(none) TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",84.9]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_system) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:00Z' AND host = 'telegraf' GROUP BY *, time(1m) fill(none) TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",5]]}]}]}`), nil).
+		AnyTimes()
+
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_user) / last(usage_system) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND host = 'telegraf' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last_last"],"values":[["2023-01-06T14:59:00+08:00",2.02]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_idle) - last(usage_system) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND host = 'telegraf' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last_last"],"values":[["2023-01-06T14:59:00+08:00",79.9]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_user) - last(usage_system) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND host = 'telegraf' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last_last"],"values":[["2023-01-06T14:59:00+08:00",5.1]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(usage_user) / last(value) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND host = 'telegraf' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"cpu","tags":{"cpu":"cpu0","host":"telegraf"},"columns":["time","last_last"],"values":[["2023-01-06T14:59:00+08:00",0.1]]}]}]}`), nil).
+		AnyTimes()
+
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM prometheus", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"prometheus","columns":["fieldKey","fieldType"],"values":[["go_goroutines","float"],["go_threads","float"],["up","float"]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(go_goroutines) FROM prometheus WHERE time <= '2023-01-06T07:00:00Z' AND job = 'prometheus' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"prometheus","tags":{"job":"prometheus"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",30]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SELECT *::tag, last(go_threads) FROM prometheus WHERE time <= '2023-01-06T07:00:00Z' AND job = 'prometheus' GROUP BY * TZ('Asia/Shanghai')", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"prometheus","tags":{"job":"prometheus"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",8]]}]}]}`), nil).
+		AnyTimes()
+
+	tests := []struct {
+		name         string
+		cmd          models.PromCommand
+		mapper       schema.Mapper
+		expectedJson string
+		wantErr      bool
+	}{
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__=~"usage_(idle|user)", host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"cpu","__field__":"usage_idle","cpu":"cpu0","host":"telegraf"},"value":[1672988340,"84.9"]},{"metric":{"__name__":"cpu","__field__":"usage_user","cpu":"cpu0","host":"telegraf"},"value":[1672988340,"10.1"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__="usage_system", host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"cpu","__field__":"usage_system","cpu":"cpu0","host":"telegraf"},"value":[1672988340,"5"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:       `cpu{__field__=~"usage_(idle|system)", host="telegraf"}`,
+				Database:  database,
+				Start:     &startTime2,
+				QueryType: models.RANGE_QUERY,
+				End:       &endTime2,
+				Timezone:  timezone,
+				Step:      MustParseDuration("1m", t),
+				DataType:  models.GRAPH_DATA,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"cpu","__field__":"usage_idle","cpu":"cpu0","host":"telegraf"},"values":[[1672988400,"84.9"]]},{"metric":{"__name__":"cpu","__field__":"usage_system","cpu":"cpu0","host":"telegraf"},"values":[[1672988400,"5"]]}],"ResultType":"matrix","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__=~"usage_guest.*", host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			// A vector selector selecting no field empties the arithmetic operation, whatever the other selects
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__=~"usage_(idle|user)", host="telegraf"} - cpu{__field__=~"usage_guest.*", host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			// absent of a vector selector selecting no field isn't empty
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `absent(cpu{__field__=~"usage_guest.*", host="telegraf"})`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			wantErr: true,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__="usage_user", host="telegraf"} / cpu{__field__="usage_system", host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"cpu","cpu":"cpu0","host":"telegraf"},"value":[1672988340,"2.02"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__=~"usage_(idle|user)", host="telegraf"} - cpu{__field__="usage_system", host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"cpu","__field__":"usage_idle","cpu":"cpu0","host":"telegraf"},"value":[1672988340,"79.9"]},{"metric":{"__name__":"cpu","__field__":"usage_user","cpu":"cpu0","host":"telegraf"},"value":[1672988340,"5.1"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__="usage_user", host="telegraf"} / cpu{host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"cpu","cpu":"cpu0","host":"telegraf"},"value":[1672988340,"0.1"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `cpu{__field__=~"usage_(idle|user)", host="telegraf"} / cpu{__field__=~"usage_(idle|system)", host="telegraf"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			wantErr: true,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `go_goroutines{__field__=~"go_.*", job="prometheus"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			mapper:       schema.TelegrafV2Mapper{},
+			expectedJson: `{"Result":[{"metric":{"__name__":"go_goroutines","__field__":"go_goroutines","job":"prometheus"},"value":[1672988340,"30"]},{"metric":{"__name__":"go_threads","__field__":"go_threads","job":"prometheus"},"value":[1672988340,"8"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := SingletonQueryCommandRunnerFactory.Build(mockClient, QueryCommandRunnerConfig{
+				Timeout:      MustParseDuration("1m", t),
+				SchemaMapper: tt.mapper,
+			})
+			defer receiver.Recycle()
+			got, err := receiver.Run(context.Background(), tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var expected map[string]interface{}
+			if err = json.Unmarshal([]byte(tt.expectedJson), &expected); err != nil {
+				t.Fatal(err)
+			}
+			var gotCopy map[string]interface{}
+			copier.DeepCopy(got, &gotCopy)
+			if !reflect.DeepEqual(gotCopy, expected) {
+				gotJ, _ := json.Marshal(got)
+				t.Errorf("Run() got = %s, want %v", gotJ, tt.expectedJson)
+			}
+		})
+	}
+}
+
+func TestQueryCommandRunner_Run_TelegrafV1(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := "telegraf"
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM http_request_duration_seconds", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"http_request_duration_seconds","columns":["fieldKey","fieldType"],"values":[["+Inf","float"],["0.5","float"],["1","float"],["count","float"],["sum","float"]]}]}]}`), nil).
+		AnyTimes()
+	for fieldKey, value := range map[string]string{"0.5": "3", "1": "5", "+Inf": "6"} {
+		mockClient.
+			EXPECT().Query(client.NewQuery(`SELECT sum(last) FROM (SELECT *::tag, last("`+fieldKey+`") FROM http_request_duration_seconds WHERE handler = '/api' GROUP BY *) WHERE time <= '2023-01-06T07:00:00Z' GROUP BY le TZ('Asia/Shanghai')`, database, "")).
+			Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"http_request_duration_seconds","columns":["time","sum"],"values":[["2023-01-06T14:59:00+08:00",`+value+`]]}]}]}`), nil).
+			AnyTimes()
+	}
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SELECT *::tag, last("0.99") FROM rpc_duration_seconds WHERE time <= '2023-01-06T07:00:00Z' GROUP BY * TZ('Asia/Shanghai')`, database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"rpc_duration_seconds","tags":{"service":"api"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",0.2]]}]}]}`), nil).
+		AnyTimes()
+	// Summaries selected without quantile equality matcher are expanded to their quantiles
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM rpc_duration_seconds", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"rpc_duration_seconds","columns":["fieldKey","fieldType"],"values":[["0.5","float"],["0.99","float"],["count","float"],["sum","float"]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SELECT *::tag, last("0.5") FROM rpc_duration_seconds WHERE time <= '2023-01-06T07:00:00Z' GROUP BY * TZ('Asia/Shanghai')`, database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"rpc_duration_seconds","tags":{"service":"api"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",0.1]]}]}]}`), nil).
+		AnyTimes()
+	// Gauges named like counts of histograms or summaries are stored in measurements of their own names
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM disk", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"disk","columns":["fieldKey","fieldType"],"values":[["free","integer"],["used","integer"]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM disk_count", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"disk_count","columns":["fieldKey","fieldType"],"values":[["gauge","float"]]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SELECT *::tag, last(gauge) FROM disk_count WHERE time <= '2023-01-06T07:00:00Z' GROUP BY * TZ('Asia/Shanghai')`, database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"disk_count","tags":{"host":"a"},"columns":["time","last"],"values":[["2023-01-06T14:59:00+08:00",2]]}]}]}`), nil).
+		AnyTimes()
+	// Untyped metrics stored in field value are neither gauges nor summaries
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM build_info", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"build_info","columns":["fieldKey","fieldType"],"values":[["value","float"]]}]}]}`), nil).
+		AnyTimes()
+
+	tests := []struct {
+		name         string
+		cmd          models.PromCommand
+		expectedJson string
+		wantErr      bool
+	}{
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `sum by (le) (http_request_duration_seconds_bucket{handler="/api", le!="1"})`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"http_request_duration_seconds_bucket","le":"+Inf"},"value":[1672988340,"6"]},{"metric":{"__name__":"http_request_duration_seconds_bucket","le":"0.5"},"value":[1672988340,"3"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `histogram_quantile(0.75, sum by (le) (http_request_duration_seconds_bucket{handler="/api"}))`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{},"value":[1672988340,"0.875"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `rpc_duration_seconds{quantile="0.99"}`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"rpc_duration_seconds","quantile":"0.99","service":"api"},"value":[1672988340,"0.2"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `rpc_duration_seconds`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"rpc_duration_seconds","quantile":"0.5","service":"api"},"value":[1672988340,"0.1"]},{"metric":{"__name__":"rpc_duration_seconds","quantile":"0.99","service":"api"},"value":[1672988340,"0.2"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `disk_count`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			expectedJson: `{"Result":[{"metric":{"__name__":"disk_count","host":"a"},"value":[1672988340,"2"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `build_info`,
+				Database: database,
+				End:      &endTime2,
+				Timezone: timezone,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := SingletonQueryCommandRunnerFactory.Build(mockClient, QueryCommandRunnerConfig{
+				Timeout:      MustParseDuration("1m", t),
+				SchemaMapper: schema.TelegrafV1Mapper{},
+			})
+			defer receiver.Recycle()
+			got, err := receiver.Run(context.Background(), tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var expected map[string]interface{}
+			if err = json.Unmarshal([]byte(tt.expectedJson), &expected); err != nil {
+				t.Fatal(err)
+			}
+			var gotCopy map[string]interface{}
+			copier.DeepCopy(got, &gotCopy)
+			if !reflect.DeepEqual(gotCopy, expected) {
+				gotJ, _ := json.Marshal(got)
+				t.Errorf("Run() got = %s, want %v", gotJ, tt.expectedJson)
+			}
+		})
+	}
+}
+
+func TestQueryCommandRunner_Run_MultiField_Concurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := "telegraf"
+	var fieldKeys []string
+	for i := 0; i < 3*maxConcurrentFieldEvaluations; i++ {
+		fieldKeys = append(fieldKeys, `["field`+strconv.Itoa(i)+`","float"]`)
+	}
+	var (
+		mutex               sync.Mutex
+		running, maxRunning int
+	)
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.
+		EXPECT().Query(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM mem", database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"mem","columns":["fieldKey","fieldType"],"values":[`+strings.Join(fieldKeys, ",")+`]}]}]}`), nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(gomock.Not(client.NewQuery("SHOW FIELD KEYS ON telegraf FROM mem", database, ""))).
+		DoAndReturn(func(q client.Query) (*client.Response, error) {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return mustUnmarshalResponse(t, `{"Results":[{"statement_id":0}]}`), nil
+		}).
+		Times(len(fieldKeys))
+
+	receiver := SingletonQueryCommandRunnerFactory.Build(mockClient, QueryCommandRunnerConfig{
+		Timeout: MustParseDuration("1m", t),
+	})
+	defer receiver.Recycle()
+	if _, err := receiver.Run(context.Background(), models.PromCommand{
+		Cmd:      `mem{__field__=~"field.*"}`,
+		Database: database,
+		End:      &endTime2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if maxRunning > maxConcurrentFieldEvaluations {
+		t.Errorf("got %d fields evaluated at the same time, want at most %d", maxRunning, maxConcurrentFieldEvaluations)
+	}
+}
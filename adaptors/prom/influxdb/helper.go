@@ -7,13 +7,19 @@ import (
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
 	"github.com/prometheus/prometheus/model/timestamp"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
 	prommodels "github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"math"
 	"sort"
+	"strconv"
 	"time"
 )
 
@@ -44,32 +50,48 @@ func (receiver *QueryCommandRunner) InfluxLiteralToPromQLValue(result influxql.L
 	}
 }
 
+// promLabels builds Prometheus label set from InfluxDB measurement name, field key and tags.
+// Metric name and extra labels are rebuilt from measurement, field key and field label by mapper.
+// Metric name and tag keys which are not valid Prometheus names are escaped by escaping package,
+// and the transpiler unescapes them back when they are used in PromQL query expressions.
+func promLabels(mapper schema.Mapper, measurement string, fieldKey string, fieldLabel string, tags map[string]string) labels.Labels {
+	metricName, extraLabels := mapper.Metric(measurement, fieldKey, fieldLabel)
+	kvs := make(map[string]string, len(tags)+len(extraLabels))
+	for k, v := range tags {
+		// Series without the tag come back with empty tag value, and Prometheus treats empty label value as absent label
+		if stringutils.IsEmpty(v) {
+			continue
+		}
+		kvs[escaping.EscapeLabelName(k)] = v
+	}
+	for _, item := range extraLabels {
+		kvs[item.Name] = item.Value
+	}
+	metric := labels.FromMap(kvs)
+	metric = append(metric, labels.FromStrings("__name__", escaping.EscapeMetricName(metricName))...)
+	return metric
+}
+
 // populatePromSeries populates *promql.Series slice from models.Row returned by InfluxDB
-func (receiver *QueryCommandRunner) populatePromSeries(promSeries *[]*promql.Series, item models.Row) error {
-	metric := labels.FromMap(item.Tags)
-	metric = append(metric, labels.FromStrings("__name__", item.Name)...)
+func (receiver *QueryCommandRunner) populatePromSeries(promSeries *[]*promql.Series, item models.Row, cmd prommodels.PromCommand) error {
+	metric := promLabels(receiver.schemaMapper(), item.Name, cmd.ValueFieldKey, cmd.ValueFieldLabel, item.Tags)
 	var points []promql.Point
 	for _, item1 := range item.Values {
-		ts, err := time.Parse(time.RFC3339Nano, item1[0].(string))
+		ts, err := pointTime(item1[0])
 		if err != nil {
 			return errors.Wrap(err, "parse time fail")
 		}
-		point := promql.Point{
-			T: timestamp.FromTime(ts),
+		v, ok := pointValue(item1[len(item1)-1], cmd.Fill)
+		if !ok {
+			continue
 		}
-		switch number := item1[len(item1)-1].(type) {
-		case json.Number:
-			if v, err := number.Float64(); err == nil {
-				point.V = v
-			} else {
-				if v, err := number.Int64(); err == nil {
-					point.V = float64(v)
-				}
-			}
-		case float64:
-			point.V = number
-		}
-		points = append(points, point)
+		points = append(points, promql.Point{
+			T: timestamp.FromTime(ts),
+			V: v,
+		})
+	}
+	if len(points) == 0 {
+		return nil
 	}
 	*promSeries = append(*promSeries, &promql.Series{
 		Metric: metric,
@@ -78,38 +100,82 @@ func (receiver *QueryCommandRunner) populatePromSeries(promSeries *[]*promql.Ser
 	return nil
 }
 
+// pointTime parses time column value returned by InfluxDB
+func pointTime(value interface{}) (time.Time, error) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, errors.Errorf("unexpected time value %v", value)
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// pointValue converts value returned by InfluxDB to sample value. Null values of empty time buckets are converted to NaN
+// if fill is prommodels.FILL_NULL, otherwise they are dropped as Prometheus does for steps without samples.
+// Values which are not numbers, like boolean fields, are dropped as well.
+func pointValue(value interface{}, fill prommodels.FillType) (float64, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		return parseFloat(number.String())
+	case float64:
+		return number, true
+	case int64:
+		return float64(number), true
+	case string:
+		// String fields are only taken as samples if they are numbers, including NaN and ±Inf
+		return parseFloat(number)
+	case nil:
+		if fill == prommodels.FILL_NULL {
+			return math.NaN(), true
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// parseFloat parses s to float64 as Prometheus does. NaN, Inf, +Inf and -Inf are accepted,
+// and numbers out of float64 range are parsed to ±Inf.
+func parseFloat(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); !ok || numErr.Err != strconv.ErrRange {
+			return 0, false
+		}
+	}
+	return v, true
+}
+
+// rowTags returns tag key/value pairs of row from table columns, skipping the first time column and the last value column.
+// Null tag values mean the series doesn't have the tag, so they are skipped as well.
+func rowTags(columns []string, row []interface{}) map[string]string {
+	kvs := make(map[string]string)
+	for i, col := range row {
+		if i == 0 || i == len(row)-1 || i >= len(columns) {
+			continue
+		}
+		if v, ok := col.(string); ok {
+			kvs[columns[i]] = v
+		}
+	}
+	return kvs
+}
+
 // populateSeriesMap populates series map seriesMap with hash of labels.Labels as map key
 // and *promql.Series as map value from models.Row returned from InfluxDB
-func (receiver *QueryCommandRunner) populateSeriesMap(seriesMap map[uint64]*promql.Series, table models.Row) error {
+func (receiver *QueryCommandRunner) populateSeriesMap(seriesMap map[uint64]*promql.Series, table models.Row, cmd prommodels.PromCommand) error {
 	for _, row := range table.Values {
-		kvs := make(map[string]string)
-		for i, col := range row {
-			if i == 0 || i == len(row)-1 {
-				continue
-			}
-			kvs[table.Columns[i]] = col.(string)
-		}
-		metric := labels.FromMap(kvs)
-		metric = append(metric, labels.FromStrings("__name__", table.Name)...)
+		metric := promLabels(receiver.schemaMapper(), table.Name, cmd.ValueFieldKey, cmd.ValueFieldLabel, rowTags(table.Columns, row))
 
-		ts, err := time.Parse(time.RFC3339Nano, row[0].(string))
+		ts, err := pointTime(row[0])
 		if err != nil {
 			return errors.Wrap(err, "parse time fail")
 		}
+		v, ok := pointValue(row[len(row)-1], cmd.Fill)
+		if !ok {
+			continue
+		}
 		point := promql.Point{
 			T: timestamp.FromTime(ts),
-		}
-		switch number := row[len(row)-1].(type) {
-		case json.Number:
-			if v, err := number.Float64(); err == nil {
-				point.V = v
-			} else {
-				if v, err := number.Int64(); err == nil {
-					point.V = float64(v)
-				}
-			}
-		case float64:
-			point.V = number
+			V: v,
 		}
 
 		if series, exists := seriesMap[metric.Hash()]; exists {
@@ -127,19 +193,15 @@ func (receiver *QueryCommandRunner) populateSeriesMap(seriesMap map[uint64]*prom
 }
 
 // populateSeriesSlice populates *promql.Series slice from series map seriesMap
-func (receiver *QueryCommandRunner) populateSeriesSlice(promSeries *[]*promql.Series, seriesMap map[uint64]*promql.Series, table models.Row) {
+func (receiver *QueryCommandRunner) populateSeriesSlice(promSeries *[]*promql.Series, seriesMap map[uint64]*promql.Series, table models.Row, cmd prommodels.PromCommand) {
 	m := make(map[*promql.Series]struct{})
 	for _, row := range table.Values {
-		kvs := make(map[string]string)
-		for i, col := range row {
-			if i == 0 || i == len(row)-1 {
-				continue
-			}
-			kvs[table.Columns[i]] = col.(string)
+		metric := promLabels(receiver.schemaMapper(), table.Name, cmd.ValueFieldKey, cmd.ValueFieldLabel, rowTags(table.Columns, row))
+		series, ok := seriesMap[metric.Hash()]
+		if !ok {
+			// all values of the series are dropped
+			continue
 		}
-		metric := labels.FromMap(kvs)
-		metric = append(metric, labels.FromStrings("__name__", table.Name)...)
-		series := seriesMap[metric.Hash()]
 		if _, exists := m[series]; !exists {
 			*promSeries = append(*promSeries, series)
 			m[series] = struct{}{}
@@ -149,21 +211,22 @@ func (receiver *QueryCommandRunner) populateSeriesSlice(promSeries *[]*promql.Se
 
 // groupResultBySeries is used to populate *promql.Series slice from models.Row returned from InfluxDB
 // when raw result has not grouped by series(measurement + tag key/value pairs).
-func (receiver *QueryCommandRunner) groupResultBySeries(promSeries *[]*promql.Series, table models.Row) error {
+func (receiver *QueryCommandRunner) groupResultBySeries(promSeries *[]*promql.Series, table models.Row, cmd prommodels.PromCommand) error {
 	// 1. Iterate the whole result table to collect all series into seriesMap. The map key is hash of label set, the map value is
 	// a pointer to promql.Series. Each series may contain one or more points.
 	seriesMap := make(map[uint64]*promql.Series)
-	if err := receiver.populateSeriesMap(seriesMap, table); err != nil {
+	if err := receiver.populateSeriesMap(seriesMap, table, cmd); err != nil {
 		return errors.Wrap(err, caller.NewCaller().String())
 	}
 
 	// 2. We iterate the whole result table again in order to append each series to promSeries while keep the same order
 	// as in the result table
-	receiver.populateSeriesSlice(promSeries, seriesMap, table)
+	receiver.populateSeriesSlice(promSeries, seriesMap, table, cmd)
 	return nil
 }
 
-// InfluxResultToPromQLValue converts influxdb.Result slice to parser.Value of Prometheus
+// InfluxResultToPromQLValue converts influxdb.Result slice to parser.Value of Prometheus.
+// cmd's ValueFieldKey and ValueFieldLabel should be the field key and field label of the transpiled query for rebuilding metric names.
 func (receiver *QueryCommandRunner) InfluxResultToPromQLValue(results []influxdb.Result, expr parser.Expr, cmd prommodels.PromCommand) (value parser.Value, resultType string, err error) {
 	if len(results) == 0 {
 		return nil, "", nil
@@ -175,11 +238,11 @@ func (receiver *QueryCommandRunner) InfluxResultToPromQLValue(results []influxdb
 	var promSeries []*promql.Series
 	for _, item := range result.Series {
 		if len(item.Tags) > 0 {
-			if err := receiver.populatePromSeries(&promSeries, item); err != nil {
+			if err := receiver.populatePromSeries(&promSeries, item, cmd); err != nil {
 				return nil, "", errors.Wrap(err, "error from populatePromSeries")
 			}
 		} else {
-			if err := receiver.groupResultBySeries(&promSeries, item); err != nil {
+			if err := receiver.groupResultBySeries(&promSeries, item, cmd); err != nil {
 				return nil, "", errors.Wrap(err, "error from populatePromSeries")
 			}
 		}
@@ -188,8 +251,9 @@ func (receiver *QueryCommandRunner) InfluxResultToPromQLValue(results []influxdb
 	case parser.ValueTypeMatrix:
 		return receiver.handleValueTypeMatrix(promSeries), string(parser.ValueTypeMatrix), nil
 	case parser.ValueTypeVector:
-		switch cmd.DataType {
-		case prommodels.GRAPH_DATA:
+		// Range queries always yield matrix as Prometheus does
+		switch cmd.QueryType {
+		case prommodels.RANGE_QUERY:
 			return receiver.handleValueTypeMatrix(promSeries), string(parser.ValueTypeMatrix), nil
 		default:
 			value, err = receiver.handleValueTypeVector(promSeries)
@@ -218,7 +282,10 @@ func (receiver *QueryCommandRunner) InfluxResultToStringSlice(results []influxdb
 			if len(item1) <= 1 {
 				continue
 			}
-			tagValue := item1[1].(string)
+			tagValue, ok := item1[1].(string)
+			if !ok {
+				continue
+			}
 			if _, exists := tagValueMap[tagValue]; exists {
 				continue
 			} else {
@@ -230,6 +297,181 @@ func (receiver *QueryCommandRunner) InfluxResultToStringSlice(results []influxdb
 	return nil
 }
 
+// InfluxResultToTagValues collects values of tag tagKey from series of influxdb.Result slice grouped by the tag.
+// Series without the tag come back with empty tag value, so they are skipped. Duplicates are removed.
+func (receiver *QueryCommandRunner) InfluxResultToTagValues(results []influxdb.Result, tagKey string) ([]string, error) {
+	var tagValues []string
+	tagValueMap := make(map[string]struct{})
+	for _, result := range results {
+		if stringutils.IsNotEmpty(result.Err) {
+			return nil, errors.New(result.Err)
+		}
+		for _, item := range result.Series {
+			tagValue := item.Tags[tagKey]
+			if stringutils.IsEmpty(tagValue) {
+				continue
+			}
+			if _, exists := tagValueMap[tagValue]; exists {
+				continue
+			}
+			tagValueMap[tagValue] = struct{}{}
+			tagValues = append(tagValues, tagValue)
+		}
+	}
+	return tagValues, nil
+}
+
+// InfluxResultToMetricNames converts influxdb.Result slice of SHOW MEASUREMENTS or SHOW FIELD KEYS statement to sorted metric names
+// which match all __name__ matchers in matchers. Duplicates are removed.
+func (receiver *QueryCommandRunner) InfluxResultToMetricNames(results []influxdb.Result, matchers []*labels.Matcher) ([]string, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+	result := results[0]
+	if stringutils.IsNotEmpty(result.Err) {
+		return nil, errors.New(result.Err)
+	}
+	var metricNames []string
+	metricNameMap := make(map[string]struct{})
+	for _, item := range result.Series {
+	ROWS:
+		for _, row := range item.Values {
+			if len(row) == 0 {
+				continue
+			}
+			name, ok := row[0].(string)
+			if !ok {
+				continue
+			}
+			var metricName string
+			if len(item.Columns) > 0 && item.Columns[0] == "fieldKey" {
+				// Rows of SHOW FIELD KEYS are field keys of measurement item.Name
+				metricName, _ = receiver.schemaMapper().Metric(item.Name, name, "")
+			} else {
+				metricName, _ = receiver.schemaMapper().Metric(name, "", "")
+			}
+			metricName = escaping.EscapeMetricName(metricName)
+			for _, matcher := range matchers {
+				if matcher.Name == labels.MetricName && !matcher.Matches(metricName) {
+					continue ROWS
+				}
+			}
+			if _, exists := metricNameMap[metricName]; exists {
+				continue
+			}
+			metricNameMap[metricName] = struct{}{}
+			metricNames = append(metricNames, metricName)
+		}
+	}
+	sort.Strings(metricNames)
+	return metricNames, nil
+}
+
+// InfluxResultToMetricTypes converts influxdb.Result slice of SHOW FIELD KEYS statement to metric families and their types
+// inferred by schema.MetricTypes. Fields of string or boolean type are skipped as they are never returned as samples.
+// If metric is not empty, only the family named metric is returned.
+func (receiver *QueryCommandRunner) InfluxResultToMetricTypes(results []influxdb.Result, metric string) (map[string]textparse.MetricType, error) {
+	if len(results) == 0 {
+		return map[string]textparse.MetricType{}, nil
+	}
+	result := results[0]
+	if stringutils.IsNotEmpty(result.Err) {
+		return nil, errors.New(result.Err)
+	}
+	var metricNames []string
+	for _, item := range result.Series {
+		for _, row := range item.Values {
+			if len(row) < 2 {
+				continue
+			}
+			fieldKey, ok := row[0].(string)
+			if !ok {
+				continue
+			}
+			if fieldType, _ := row[1].(string); fieldType != "float" && fieldType != "integer" && fieldType != "unsigned" {
+				continue
+			}
+			metricName, _ := receiver.schemaMapper().Metric(item.Name, fieldKey, "")
+			metricNames = append(metricNames, escaping.EscapeMetricName(metricName))
+		}
+	}
+	metricTypes := schema.MetricTypes(metricNames)
+	if stringutils.IsEmpty(metric) {
+		return metricTypes, nil
+	}
+	filtered := make(map[string]textparse.MetricType)
+	if metricType, ok := metricTypes[metric]; ok {
+		filtered[metric] = metricType
+	}
+	return filtered, nil
+}
+
+// InfluxResultToLabelNames converts influxdb.Result slice of SHOW TAG KEYS statement to label names.
+// Tag keys are escaped the same way as in query results, and __name__ is always included as every series has a metric name.
+func (receiver *QueryCommandRunner) InfluxResultToLabelNames(results []influxdb.Result) ([]string, error) {
+	labelNames := []string{labels.MetricName}
+	if len(results) == 0 {
+		return labelNames, nil
+	}
+	result := results[0]
+	if stringutils.IsNotEmpty(result.Err) {
+		return nil, errors.New(result.Err)
+	}
+	labelNameMap := map[string]struct{}{
+		labels.MetricName: {},
+	}
+	for _, item := range result.Series {
+		for _, row := range item.Values {
+			if len(row) == 0 {
+				continue
+			}
+			tagKey, ok := row[0].(string)
+			if !ok {
+				continue
+			}
+			labelName := escaping.EscapeLabelName(tagKey)
+			if _, exists := labelNameMap[labelName]; exists {
+				continue
+			}
+			labelNameMap[labelName] = struct{}{}
+			labelNames = append(labelNames, labelName)
+		}
+	}
+	return labelNames, nil
+}
+
+// InfluxResultToLabelSets converts influxdb.Result slice to sorted label sets of the series in it, duplicates are removed
+func (receiver *QueryCommandRunner) InfluxResultToLabelSets(results []influxdb.Result, cmd prommodels.PromCommand) ([]labels.Labels, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+	result := results[0]
+	if stringutils.IsNotEmpty(result.Err) {
+		return nil, errors.New(result.Err)
+	}
+	var labelSets []labels.Labels
+	seen := make(map[uint64]struct{})
+	for _, item := range result.Series {
+		tagSets := []map[string]string{item.Tags}
+		if len(item.Tags) == 0 {
+			// Tags are returned as columns of every row if the result has not grouped by series
+			tagSets = tagSets[:0]
+			for _, row := range item.Values {
+				tagSets = append(tagSets, rowTags(item.Columns, row))
+			}
+		}
+		for _, tags := range tagSets {
+			metric := labels.New(promLabels(receiver.schemaMapper(), item.Name, cmd.ValueFieldKey, cmd.ValueFieldLabel, tags)...)
+			if _, exists := seen[metric.Hash()]; exists {
+				continue
+			}
+			seen[metric.Hash()] = struct{}{}
+			labelSets = append(labelSets, metric)
+		}
+	}
+	return labelSets, nil
+}
+
 func (receiver *QueryCommandRunner) handleValueTypeMatrix(promSeries []*promql.Series) promql.Matrix {
 	matrix := make(promql.Matrix, 0, len(promSeries))
 	for _, ser := range promSeries {
@@ -252,3 +494,79 @@ func (receiver *QueryCommandRunner) handleValueTypeVector(promSeries []*promql.S
 	}
 	return vector, nil
 }
+
+// restampMatrix re-stamps points of range query results onto the requested step grid as Prometheus does.
+// If every step is pinned to a single timestamp by @ modifier, the only point of each series is repeated at every step
+// from cmd.Start to cmd.End, otherwise points are shifted forward by offset modifier back to the time of their steps,
+// and stamped with the end time of their GROUP BY time() buckets instead of the start time.
+func (receiver *QueryCommandRunner) restampMatrix(matrix promql.Matrix, cmd prommodels.PromCommand, stepModifier transpiler.StepModifier) promql.Matrix {
+	if stepModifier.Pinned() {
+		steps := stepTimestamps(cmd)
+		for i, series := range matrix {
+			if len(series.Points) == 0 {
+				continue
+			}
+			v := series.Points[len(series.Points)-1].V
+			points := make([]promql.Point, 0, len(steps))
+			for _, ts := range steps {
+				points = append(points, promql.Point{
+					T: ts,
+					V: v,
+				})
+			}
+			matrix[i].Points = points
+		}
+		return matrix
+	}
+	if stepModifier.Offset == 0 && stepModifier.Interval == 0 {
+		return matrix
+	}
+	shift := (stepModifier.Offset + stepModifier.Interval).Milliseconds()
+	var start, end int64 = math.MinInt64, math.MaxInt64
+	if stepModifier.Interval > 0 {
+		// time buckets out of the requested time range are only used for calculating the results of the edge steps
+		if cmd.Start != nil {
+			start = timestamp.FromTime(*cmd.Start)
+		}
+		if cmd.End != nil {
+			end = timestamp.FromTime(*cmd.End)
+		}
+	}
+	restamped := matrix[:0]
+	for _, series := range matrix {
+		points := series.Points[:0]
+		for _, point := range series.Points {
+			point.T += shift
+			if point.T < start || point.T > end {
+				continue
+			}
+			points = append(points, point)
+		}
+		if len(points) == 0 {
+			continue
+		}
+		series.Points = points
+		restamped = append(restamped, series)
+	}
+	return restamped
+}
+
+// stepTimestamps returns timestamps in milliseconds of every step of range query cmd
+func stepTimestamps(cmd prommodels.PromCommand) []int64 {
+	end := time.Now()
+	if cmd.End != nil {
+		end = *cmd.End
+	}
+	start := end
+	if cmd.Start != nil {
+		start = *cmd.Start
+	}
+	if cmd.Step <= 0 {
+		return []int64{timestamp.FromTime(start)}
+	}
+	var steps []int64
+	for ts := start; !ts.After(end); ts = ts.Add(cmd.Step) {
+		steps = append(steps, timestamp.FromTime(ts))
+	}
+	return steps
+}
@@ -1,14 +1,23 @@
 package influxdb
 
 import (
+	"encoding/json"
+	influxmodels "github.com/influxdata/influxdb1-client/models"
 	client "github.com/influxdata/influxdb1-client/v2"
 	"github.com/influxdata/influxql"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/timestamp"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/testinghelper"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"math"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestQueryCommandRunner_InfluxLiteralToPromQLValue(t *testing.T) {
@@ -67,3 +76,533 @@ func TestQueryCommandRunner_InfluxLiteralToPromQLValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_promLabels(t *testing.T) {
+	type args struct {
+		mapper      schema.Mapper
+		measurement string
+		fieldKey    string
+		fieldLabel  string
+		tags        map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want labels.Labels
+	}{
+		{
+			name: "",
+			args: args{
+				mapper:      schema.PromWriteMapper{},
+				measurement: "cpu",
+				tags: map[string]string{
+					"host": "telegraf",
+				},
+			},
+			want: append(labels.FromStrings("host", "telegraf"), labels.FromStrings("__name__", "cpu")...),
+		},
+		{
+			name: "",
+			args: args{
+				mapper:      schema.PromWriteMapper{},
+				measurement: "cpu.usage",
+				tags: map[string]string{
+					"http-method": "GET",
+					"host":        "telegraf",
+				},
+			},
+			want: append(labels.FromStrings("U__http_2d_method", "GET", "host", "telegraf"), labels.FromStrings("__name__", "U__cpu_2e_usage")...),
+		},
+		{
+			name: "",
+			args: args{
+				mapper:      schema.TelegrafV2Mapper{},
+				measurement: "prometheus",
+				fieldKey:    "go_goroutines",
+				tags: map[string]string{
+					"instance": "localhost:9090",
+				},
+			},
+			want: append(labels.FromStrings("instance", "localhost:9090"), labels.FromStrings("__name__", "go_goroutines")...),
+		},
+		{
+			name: "",
+			args: args{
+				mapper:      schema.TelegrafV1Mapper{},
+				measurement: "http_request_duration_seconds",
+				fieldKey:    "0.5",
+				tags: map[string]string{
+					"handler": "/api",
+				},
+			},
+			want: append(labels.FromStrings("handler", "/api", "le", "0.5"), labels.FromStrings("__name__", "http_request_duration_seconds_bucket")...),
+		},
+		{
+			name: "",
+			args: args{
+				mapper:      schema.TelegrafV1Mapper{},
+				measurement: "rpc_duration_seconds",
+				fieldKey:    "0.99",
+				fieldLabel:  "quantile",
+				tags: map[string]string{
+					"service": "api",
+				},
+			},
+			want: append(labels.FromStrings("quantile", "0.99", "service", "api"), labels.FromStrings("__name__", "rpc_duration_seconds")...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := promLabels(tt.args.mapper, tt.args.measurement, tt.args.fieldKey, tt.args.fieldLabel, tt.args.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryCommandRunner_restampMatrix(t *testing.T) {
+	start := time.Date(2023, 1, 6, 12, 0, 0, 0, time.Local)
+	end := start.Add(2 * time.Minute)
+	at := start.Add(-time.Hour)
+	metric := labels.FromStrings("__name__", "cpu")
+	type args struct {
+		matrix       promql.Matrix
+		cmd          models.PromCommand
+		stepModifier transpiler.StepModifier
+	}
+	tests := []struct {
+		name string
+		args args
+		want promql.Matrix
+	}{
+		{
+			name: "",
+			args: args{
+				matrix: promql.Matrix{
+					{
+						Metric: metric,
+						Points: []promql.Point{{T: timestamp.FromTime(start.Add(-time.Hour)), V: 1}, {T: timestamp.FromTime(start.Add(-59 * time.Minute)), V: 2}},
+					},
+				},
+				cmd: models.PromCommand{
+					Start: &start,
+					End:   &end,
+					Step:  time.Minute,
+				},
+				stepModifier: transpiler.StepModifier{
+					Offset: time.Hour,
+				},
+			},
+			want: promql.Matrix{
+				{
+					Metric: metric,
+					Points: []promql.Point{{T: timestamp.FromTime(start), V: 1}, {T: timestamp.FromTime(start.Add(time.Minute)), V: 2}},
+				},
+			},
+		},
+		{
+			name: "",
+			args: args{
+				matrix: promql.Matrix{
+					{
+						Metric: metric,
+						Points: []promql.Point{{T: timestamp.FromTime(at), V: 3}},
+					},
+				},
+				cmd: models.PromCommand{
+					Start: &start,
+					End:   &end,
+					Step:  time.Minute,
+				},
+				stepModifier: transpiler.StepModifier{
+					Offset: time.Minute,
+					At:     &at,
+				},
+			},
+			want: promql.Matrix{
+				{
+					Metric: metric,
+					Points: []promql.Point{
+						{T: timestamp.FromTime(start), V: 3},
+						{T: timestamp.FromTime(start.Add(time.Minute)), V: 3},
+						{T: timestamp.FromTime(end), V: 3},
+					},
+				},
+			},
+		},
+		{
+			name: "",
+			args: args{
+				matrix: promql.Matrix{
+					{
+						Metric: metric,
+						Points: []promql.Point{
+							{T: timestamp.FromTime(start.Add(-time.Minute)), V: 1},
+							{T: timestamp.FromTime(start.Add(time.Minute)), V: 2},
+							{T: timestamp.FromTime(end), V: 3},
+						},
+					},
+				},
+				cmd: models.PromCommand{
+					Start: &start,
+					End:   &end,
+					Step:  time.Minute,
+				},
+				stepModifier: transpiler.StepModifier{
+					Interval: time.Minute,
+				},
+			},
+			want: promql.Matrix{
+				{
+					Metric: metric,
+					Points: []promql.Point{{T: timestamp.FromTime(start), V: 1}, {T: timestamp.FromTime(end), V: 2}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &QueryCommandRunner{}
+			if got := receiver.restampMatrix(tt.args.matrix, tt.args.cmd, tt.args.stepModifier); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("restampMatrix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pointValue(t *testing.T) {
+	type args struct {
+		value interface{}
+		fill  models.FillType
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   float64
+		wantOk bool
+	}{
+		{
+			name: "",
+			args: args{
+				value: json.Number("1.5"),
+			},
+			want:   1.5,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				value: float64(2),
+				fill:  models.FILL_PREVIOUS,
+			},
+			want:   2,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				value: nil,
+				fill:  models.FILL_NONE,
+			},
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name: "",
+			args: args{
+				value: nil,
+			},
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name: "",
+			args: args{
+				value: json.Number("1e400"),
+			},
+			want:   math.Inf(1),
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				value: "-Inf",
+			},
+			want:   math.Inf(-1),
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				value: int64(3),
+			},
+			want:   3,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				value: "running",
+			},
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name: "",
+			args: args{
+				value: true,
+			},
+			want:   0,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := pointValue(tt.args.value, tt.args.fill)
+			if got != tt.want {
+				t.Errorf("pointValue() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("pointValue() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+	if got, ok := pointValue(nil, models.FILL_NULL); !ok || !math.IsNaN(got) {
+		t.Errorf("pointValue() = %v, %v, want NaN, true", got, ok)
+	}
+	if got, ok := pointValue("NaN", models.FILL_NONE); !ok || !math.IsNaN(got) {
+		t.Errorf("pointValue() = %v, %v, want NaN, true", got, ok)
+	}
+}
+
+func Test_rowTags(t *testing.T) {
+	type args struct {
+		columns []string
+		row     []interface{}
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]string
+	}{
+		{
+			name: "",
+			args: args{
+				columns: []string{"time", "cpu", "host", "last"},
+				row:     []interface{}{"2023-01-06T07:00:00Z", "cpu0", "telegraf", json.Number("1")},
+			},
+			want: map[string]string{"cpu": "cpu0", "host": "telegraf"},
+		},
+		{
+			name: "",
+			args: args{
+				columns: []string{"time", "cpu", "host", "last"},
+				row:     []interface{}{"2023-01-06T07:00:00Z", nil, "telegraf", json.Number("1")},
+			},
+			want: map[string]string{"host": "telegraf"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rowTags(tt.args.columns, tt.args.row); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rowTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryCommandRunner_InfluxResultToPromQLValue_Null(t *testing.T) {
+	results := []client.Result{
+		{
+			Series: []influxmodels.Row{
+				{
+					Name:    "cpu",
+					Columns: []string{"time", "cpu", "host", "last"},
+					Values: [][]interface{}{
+						{"2023-01-06T07:00:00Z", nil, "telegraf", json.Number("1")},
+						{"2023-01-06T07:00:00Z", "cpu0", "telegraf", nil},
+						{"2023-01-06T07:00:00Z", "cpu1", "telegraf", "NaN"},
+					},
+				},
+				{
+					Name:    "cpu",
+					Tags:    map[string]string{"cpu": "", "host": "node"},
+					Columns: []string{"time", "last"},
+					Values: [][]interface{}{
+						{"2023-01-06T07:00:00Z", json.Number("2")},
+					},
+				},
+			},
+		},
+	}
+	receiver := &QueryCommandRunner{}
+	got, _, err := receiver.InfluxResultToPromQLValue(results, testinghelper.VectorSelector(`cpu`), models.PromCommand{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotJ, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"metric":{"__name__":"cpu","host":"telegraf"},"value":[1672988400,"1"]},{"metric":{"__name__":"cpu","cpu":"cpu1","host":"telegraf"},"value":[1672988400,"NaN"]},{"metric":{"__name__":"cpu","host":"node"},"value":[1672988400,"2"]}]`
+	if string(gotJ) != want {
+		t.Errorf("InfluxResultToPromQLValue() got = %s, want %s", gotJ, want)
+	}
+}
+
+func TestQueryCommandRunner_InfluxResultToLabelSets(t *testing.T) {
+	results := []client.Result{
+		{
+			Series: []influxmodels.Row{
+				{
+					Name:    "cpu",
+					Tags:    map[string]string{"cpu": "cpu1", "host": "telegraf"},
+					Columns: []string{"time", "last"},
+					Values: [][]interface{}{
+						{"2023-01-06T07:00:00Z", json.Number("1")},
+					},
+				},
+				{
+					Name:    "cpu",
+					Tags:    map[string]string{"cpu": "", "host": "node"},
+					Columns: []string{"time", "last"},
+					Values: [][]interface{}{
+						{"2023-01-06T07:00:00Z", json.Number("2")},
+					},
+				},
+				{
+					Name:    "cpu",
+					Columns: []string{"time", "cpu", "host", "last"},
+					Values: [][]interface{}{
+						{"2023-01-06T07:00:00Z", "cpu0", "telegraf", json.Number("3")},
+						{"2023-01-06T07:00:00Z", "cpu1", "telegraf", json.Number("4")},
+					},
+				},
+			},
+		},
+	}
+	receiver := &QueryCommandRunner{}
+	got, err := receiver.InfluxResultToLabelSets(results, models.PromCommand{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotJ, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"__name__":"cpu","cpu":"cpu1","host":"telegraf"},{"__name__":"cpu","host":"node"},{"__name__":"cpu","cpu":"cpu0","host":"telegraf"}]`
+	if string(gotJ) != want {
+		t.Errorf("InfluxResultToLabelSets() got = %s, want %s", gotJ, want)
+	}
+}
+
+func TestQueryCommandRunner_InfluxResultToLabelNames(t *testing.T) {
+	results := []client.Result{
+		{
+			Series: []influxmodels.Row{
+				{
+					Name:    "cpu",
+					Columns: []string{"tagKey"},
+					Values: [][]interface{}{
+						{"cpu"},
+						{"host"},
+					},
+				},
+				{
+					Name:    "disk",
+					Columns: []string{"tagKey"},
+					Values: [][]interface{}{
+						{"host"},
+						{"mount.point"},
+					},
+				},
+			},
+		},
+	}
+	receiver := &QueryCommandRunner{}
+	got, err := receiver.InfluxResultToLabelNames(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"__name__", "cpu", "host", escaping.EscapeLabelName("mount.point")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InfluxResultToLabelNames() got = %v, want %v", got, want)
+	}
+}
+
+func TestQueryCommandRunner_InfluxResultToMetricNames(t *testing.T) {
+	results := []client.Result{
+		{
+			Series: []influxmodels.Row{
+				{
+					Name:    "prometheus",
+					Columns: []string{"fieldKey", "fieldType"},
+					Values: [][]interface{}{
+						{"go_threads", "float"},
+						{"go_goroutines", "float"},
+						{"process.open.fds", "float"},
+					},
+				},
+			},
+		},
+	}
+	receiver := &QueryCommandRunner{
+		Cfg: QueryCommandRunnerConfig{
+			SchemaMapper: schema.TelegrafV2Mapper{},
+		},
+	}
+	matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchNotEqual, labels.MetricName, "go_threads")}
+	got, err := receiver.InfluxResultToMetricNames(results, matchers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{escaping.EscapeMetricName("process.open.fds"), "go_goroutines"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InfluxResultToMetricNames() got = %v, want %v", got, want)
+	}
+}
+
+func TestQueryCommandRunner_InfluxResultToTagValues(t *testing.T) {
+	results := []client.Result{
+		{
+			Series: []influxmodels.Row{
+				{
+					Name:    "go_goroutines",
+					Tags:    map[string]string{"job": "prometheus"},
+					Columns: []string{"time", "last"},
+					Values:  [][]interface{}{{"2023-01-06T07:00:00Z", json.Number("1")}},
+				},
+				{
+					Name:    "go_goroutines",
+					Tags:    map[string]string{"job": ""},
+					Columns: []string{"time", "last"},
+					Values:  [][]interface{}{{"2023-01-06T07:00:00Z", json.Number("2")}},
+				},
+				{
+					Name:    "go_threads",
+					Tags:    map[string]string{"job": "prometheus"},
+					Columns: []string{"time", "last"},
+					Values:  [][]interface{}{{"2023-01-06T07:00:00Z", json.Number("3")}},
+				},
+				{
+					Name:    "go_threads",
+					Tags:    map[string]string{"job": "node"},
+					Columns: []string{"time", "last"},
+					Values:  [][]interface{}{{"2023-01-06T07:00:00Z", json.Number("4")}},
+				},
+			},
+		},
+	}
+	receiver := &QueryCommandRunner{}
+	got, err := receiver.InfluxResultToTagValues(results, "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"prometheus", "node"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InfluxResultToTagValues() got = %v, want %v", got, want)
+	}
+	if _, err = receiver.InfluxResultToTagValues([]client.Result{{Err: "database not found"}}, "job"); err == nil {
+		t.Errorf("InfluxResultToTagValues() error = nil, wantErr true")
+	}
+}
@@ -0,0 +1,229 @@
+package influxdb
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/evaluator"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// histogramQuantileCall returns the PromQL histogram_quantile function call if the whole expression is the call
+func histogramQuantileCall(expr parser.Expr) (*parser.Call, bool) {
+	for {
+		paren, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.Expr
+	}
+	call, ok := expr.(*parser.Call)
+	if !ok || call.Func.Name != "histogram_quantile" {
+		return nil, false
+	}
+	return call, true
+}
+
+// handleHistogramQuantileCase evaluates PromQL histogram_quantile function locally, as InfluxQL has no equivalent.
+// The bucket argument is evaluated as a command of its own at first, then its series are grouped by labels other than
+// le and metric name, and the quantile of each group is calculated from its buckets at every timestamp as Prometheus does.
+func (receiver *QueryCommandRunner) handleHistogramQuantileCase(cmd models.PromCommand, call *parser.Call, resultChan chan models.RunResult,
+	handleErr func(err error)) {
+	if !transpiler.YieldsFloat(call.Args[0]) {
+		handleErr(errors.Errorf("quantile of %s must be a scalar", call))
+		return
+	}
+	var e evaluator.Evaluator
+	q := e.EvalYieldsFloatExpr(call.Args[0]).Val
+	bucketCmd := cmd
+	bucketCmd.Cmd = call.Args[1].String()
+	bucketResultChan := make(chan models.RunResult, 1)
+	receiver.handleCmdNotEmptyCase(bucketCmd, bucketResultChan, func(err error) {
+		bucketResultChan <- models.RunResult{
+			Error: err,
+		}
+	})
+	bucketResult := <-bucketResultChan
+	if bucketResult.Error != nil {
+		handleErr(bucketResult.Error)
+		return
+	}
+	var matrix promql.Matrix
+	switch value := bucketResult.Result.(type) {
+	case promql.Matrix:
+		matrix = value
+	case promql.Vector:
+		for _, sample := range value {
+			matrix = append(matrix, promql.Series{
+				Metric: sample.Metric,
+				Points: []promql.Point{sample.Point},
+			})
+		}
+	default:
+		handleErr(errors.Errorf("unsupported PromQL value type of histogram buckets: %T", value))
+		return
+	}
+	result := histogramQuantile(q, matrix)
+	if _, ok := bucketResult.Result.(promql.Matrix); ok {
+		resultChan <- models.RunResult{
+			Result:     result,
+			ResultType: bucketResult.ResultType,
+		}
+		return
+	}
+	vector := make(promql.Vector, 0, len(result))
+	for _, series := range result {
+		vector = append(vector, promql.Sample{
+			Metric: series.Metric,
+			Point:  series.Points[0],
+		})
+	}
+	resultChan <- models.RunResult{
+		Result:     vector,
+		ResultType: bucketResult.ResultType,
+	}
+}
+
+// bucket is a histogram bucket of cumulative count of observations less than or equal to upperBound
+type bucket struct {
+	upperBound float64
+	count      float64
+}
+
+// histogramQuantile calculates the q-quantile of buckets in matrix at every timestamp. Series are grouped into histograms by labels
+// other than le and metric name, and series without a valid le label are ignored.
+func histogramQuantile(q float64, matrix promql.Matrix) promql.Matrix {
+	type histogram struct {
+		metric  labels.Labels
+		buckets map[int64][]bucket
+	}
+	histograms := make(map[uint64]*histogram)
+	for _, series := range matrix {
+		upperBound, err := strconv.ParseFloat(series.Metric.Get(labels.BucketLabel), 64)
+		if err != nil {
+			continue
+		}
+		metric := labels.NewBuilder(series.Metric).Del(labels.BucketLabel, labels.MetricName).Labels(nil)
+		hash := metric.Hash()
+		h, ok := histograms[hash]
+		if !ok {
+			h = &histogram{
+				metric:  metric,
+				buckets: make(map[int64][]bucket),
+			}
+			histograms[hash] = h
+		}
+		for _, point := range series.Points {
+			h.buckets[point.T] = append(h.buckets[point.T], bucket{
+				upperBound: upperBound,
+				count:      point.V,
+			})
+		}
+	}
+	result := make(promql.Matrix, 0, len(histograms))
+	for _, h := range histograms {
+		points := make([]promql.Point, 0, len(h.buckets))
+		for ts, buckets := range h.buckets {
+			points = append(points, promql.Point{
+				T: ts,
+				V: bucketQuantile(q, buckets),
+			})
+		}
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].T < points[j].T
+		})
+		result = append(result, promql.Series{
+			Metric: h.metric,
+			Points: points,
+		})
+	}
+	sort.Sort(result)
+	return result
+}
+
+// bucketQuantile is ported from Prometheus's promql/quantile.go. It calculates the quantile q based on the given buckets,
+// assuming a linear distribution within a bucket. If the highest bucket isn't +Inf, NaN is returned. If q is between the lower
+// bound of the +Inf bucket and +Inf, the upper bound of the second highest bucket is returned. If the lowest bucket has an upper
+// bound greater than zero, the lower bound of it is assumed to be zero.
+func bucketQuantile(q float64, buckets []bucket) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].upperBound < buckets[j].upperBound
+	})
+	if !math.IsInf(buckets[len(buckets)-1].upperBound, +1) {
+		return math.NaN()
+	}
+	buckets = coalesceBuckets(buckets)
+	ensureMonotonic(buckets)
+
+	if len(buckets) < 2 {
+		return math.NaN()
+	}
+	observations := buckets[len(buckets)-1].count
+	if observations == 0 {
+		return math.NaN()
+	}
+	rank := q * observations
+	b := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].count >= rank })
+
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].upperBound
+	}
+	if b == 0 && buckets[0].upperBound <= 0 {
+		return buckets[0].upperBound
+	}
+	var (
+		bucketStart float64
+		bucketEnd   = buckets[b].upperBound
+		count       = buckets[b].count
+	)
+	if b > 0 {
+		bucketStart = buckets[b-1].upperBound
+		count -= buckets[b-1].count
+		rank -= buckets[b-1].count
+	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
+
+// coalesceBuckets merges buckets with the same upper bound. The input buckets must be sorted.
+func coalesceBuckets(buckets []bucket) []bucket {
+	last := buckets[0]
+	i := 0
+	for _, b := range buckets[1:] {
+		if b.upperBound == last.upperBound {
+			last.count += b.count
+		} else {
+			buckets[i] = last
+			last = b
+			i++
+		}
+	}
+	buckets[i] = last
+	return buckets[:i+1]
+}
+
+// ensureMonotonic raises counts of buckets lower than the ones of their previous buckets, which happens when buckets are
+// scraped or calculated at slightly different times. The input buckets must be sorted.
+func ensureMonotonic(buckets []bucket) {
+	max := math.Inf(-1)
+	for i := range buckets {
+		if buckets[i].count > max {
+			max = buckets[i].count
+		} else if buckets[i].count < max {
+			buckets[i].count = max
+		}
+	}
+}
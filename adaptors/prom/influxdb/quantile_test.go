@@ -0,0 +1,83 @@
+package influxdb
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_bucketQuantile(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       float64
+		buckets []bucket
+		want    float64
+	}{
+		{
+			name:    "",
+			q:       0.75,
+			buckets: []bucket{{upperBound: math.Inf(1), count: 6}, {upperBound: 0.5, count: 3}, {upperBound: 1, count: 5}},
+			want:    0.875,
+		},
+		{
+			name:    "",
+			q:       0.9,
+			buckets: []bucket{{upperBound: 0.5, count: 3}, {upperBound: 1, count: 5}, {upperBound: math.Inf(1), count: 6}},
+			want:    1,
+		},
+		{
+			name:    "",
+			q:       0.5,
+			buckets: []bucket{{upperBound: 0.5, count: 3}, {upperBound: 1, count: 2}, {upperBound: math.Inf(1), count: 6}},
+			want:    0.5,
+		},
+		{
+			name:    "",
+			q:       0.5,
+			buckets: []bucket{{upperBound: 0.5, count: 3}, {upperBound: 1, count: 5}},
+			want:    math.NaN(),
+		},
+		{
+			name:    "",
+			q:       1.5,
+			buckets: []bucket{{upperBound: 0.5, count: 3}, {upperBound: math.Inf(1), count: 6}},
+			want:    math.Inf(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bucketQuantile(tt.q, tt.buckets)
+			if got != tt.want && !(math.IsNaN(got) && math.IsNaN(tt.want)) {
+				t.Errorf("bucketQuantile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_histogramQuantile(t *testing.T) {
+	matrix := promql.Matrix{
+		{
+			Metric: labels.FromStrings(labels.MetricName, "http_request_duration_seconds_bucket", "handler", "/api", "le", "0.5"),
+			Points: []promql.Point{{T: 1000, V: 3}, {T: 2000, V: 4}},
+		},
+		{
+			Metric: labels.FromStrings(labels.MetricName, "http_request_duration_seconds_bucket", "handler", "/api", "le", "+Inf"),
+			Points: []promql.Point{{T: 1000, V: 6}, {T: 2000, V: 8}},
+		},
+		{
+			Metric: labels.FromStrings(labels.MetricName, "http_request_duration_seconds_bucket", "handler", "/api"),
+			Points: []promql.Point{{T: 1000, V: 100}},
+		},
+	}
+	want := promql.Matrix{
+		{
+			Metric: labels.FromStrings("handler", "/api"),
+			Points: []promql.Point{{T: 1000, V: 0.5}, {T: 2000, V: 0.5}},
+		},
+	}
+	if got := histogramQuantile(0.5, matrix); !reflect.DeepEqual(got, want) {
+		t.Errorf("histogramQuantile() = %v, want %v", got, want)
+	}
+}
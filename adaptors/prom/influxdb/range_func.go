@@ -0,0 +1,463 @@
+package influxdb
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/evaluator"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
+	prommodels "github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"math"
+	"sort"
+	"time"
+)
+
+// evalRangeFunc evaluates PromQL range function rangeFunc.Call locally from raw samples of every series in matrix at every
+// step of range query cmd, or at the evaluation time of instant query cmd. As Prometheus does, the window of each step is
+// shifted back by offset modifier, or pinned by @ modifier, and metric names are dropped from the results. Then the
+// expression enclosing the call is evaluated from the results.
+func (receiver *QueryCommandRunner) evalRangeFunc(matrix promql.Matrix, cmd prommodels.PromCommand, stepModifier transpiler.StepModifier,
+	rangeFunc *transpiler.RangeFunc) (parser.Value, string, error) {
+	var steps []int64
+	if cmd.QueryType == prommodels.RANGE_QUERY {
+		steps = stepTimestamps(cmd)
+	} else {
+		steps = []int64{timestamp.FromTime(evaluationTime(cmd))}
+	}
+	var e evaluator.Evaluator
+	var param float64
+	if len(rangeFunc.Call.Args) > 1 {
+		param = e.EvalYieldsFloatExpr(rangeFunc.Call.Args[0]).Val
+	}
+	var result promql.Matrix
+	for _, series := range matrix {
+		var points []promql.Point
+		for _, ts := range steps {
+			rangeEnd := ts
+			if stepModifier.Pinned() {
+				rangeEnd = timestamp.FromTime(*stepModifier.At)
+			}
+			rangeEnd -= stepModifier.Offset.Milliseconds()
+			rangeStart := rangeEnd - rangeFunc.Range.Milliseconds()
+			v, ok := evalRangeFuncWindow(rangeFunc.Call.Func.Name, param, windowPoints(series.Points, rangeStart, rangeEnd), rangeStart, rangeEnd, rangeFunc.Range)
+			if !ok {
+				continue
+			}
+			points = append(points, promql.Point{
+				T: ts,
+				V: v,
+			})
+		}
+		if len(points) == 0 {
+			continue
+		}
+		result = append(result, promql.Series{
+			Metric: labels.NewBuilder(series.Metric).Del(labels.MetricName).Labels(nil),
+			Points: points,
+		})
+	}
+	result, err := evalRangeFuncExpr(rangeFunc.Expr, rangeFunc.Call, result)
+	if err != nil {
+		return nil, "", err
+	}
+	if cmd.QueryType == prommodels.RANGE_QUERY {
+		if result == nil {
+			result = promql.Matrix{}
+		}
+		sort.Sort(result)
+		return result, string(parser.ValueTypeMatrix), nil
+	}
+	vector := make(promql.Vector, 0, len(result))
+	for _, series := range result {
+		vector = append(vector, promql.Sample{
+			Metric: series.Metric,
+			Point:  series.Points[0],
+		})
+	}
+	return vector, string(parser.ValueTypeVector), nil
+}
+
+// evalRangeFuncWindow evaluates PromQL range function fn from points within the window from rangeStart to rangeEnd
+// as Prometheus does. param is the quantile of quantile_over_time. It returns false if there are not enough points.
+func evalRangeFuncWindow(fn string, param float64, points []promql.Point, rangeStart, rangeEnd int64, selectRange time.Duration) (float64, bool) {
+	switch fn {
+	case "rate", "increase":
+		return extrapolatedRate(points, rangeStart, rangeEnd, selectRange, true, fn == "rate")
+	case "deriv":
+		// No sense in trying to compute a derivative without at least two points.
+		if len(points) < 2 {
+			return 0, false
+		}
+		slope, _ := linearRegression(points, points[0].T)
+		return slope, true
+	}
+	if len(points) == 0 {
+		return 0, false
+	}
+	values := make([]float64, 0, len(points))
+	for _, point := range points {
+		values = append(values, point.V)
+	}
+	switch fn {
+	case "sum_over_time":
+		return aggregateValues(parser.SUM, values), true
+	case "avg_over_time":
+		return aggregateValues(parser.AVG, values), true
+	case "max_over_time":
+		return aggregateValues(parser.MAX, values), true
+	case "min_over_time":
+		return aggregateValues(parser.MIN, values), true
+	case "count_over_time":
+		return aggregateValues(parser.COUNT, values), true
+	case "stddev_over_time":
+		return aggregateValues(parser.STDDEV, values), true
+	case "quantile_over_time":
+		return quantile(param, values), true
+	}
+	return 0, false
+}
+
+// evalRangeFuncExpr evaluates PromQL expression expr enclosing range function call from results of the call, which are
+// parentheses, aggregations and arithmetic or comparison operations with numbers as transpiler.RangeFunc describes
+func evalRangeFuncExpr(expr parser.Expr, call *parser.Call, result promql.Matrix) (promql.Matrix, error) {
+	switch e := expr.(type) {
+	case *parser.ParenExpr:
+		return evalRangeFuncExpr(e.Expr, call, result)
+	case *parser.Call:
+		if e == call {
+			return result, nil
+		}
+	case *parser.AggregateExpr:
+		inner, err := evalRangeFuncExpr(e.Expr, call, result)
+		if err != nil {
+			return nil, err
+		}
+		return aggregateMatrix(e, inner), nil
+	case *parser.BinaryExpr:
+		var ev evaluator.Evaluator
+		if transpiler.YieldsFloat(e.LHS) {
+			inner, err := evalRangeFuncExpr(e.RHS, call, result)
+			if err != nil {
+				return nil, err
+			}
+			return scalarBinop(e, inner, ev.EvalYieldsFloatExpr(e.LHS).Val, true), nil
+		}
+		inner, err := evalRangeFuncExpr(e.LHS, call, result)
+		if err != nil {
+			return nil, err
+		}
+		return scalarBinop(e, inner, ev.EvalYieldsFloatExpr(e.RHS).Val, false), nil
+	}
+	return nil, errors.Errorf("PromQL expression %s can't be evaluated locally", expr)
+}
+
+// aggregateMatrix applies PromQL aggregation a to every step of matrix. Series are grouped by labels of a.Grouping,
+// or by labels other than a.Grouping and metric name if a.Without is true.
+func aggregateMatrix(a *parser.AggregateExpr, matrix promql.Matrix) promql.Matrix {
+	type group struct {
+		metric labels.Labels
+		values map[int64][]float64
+	}
+	groups := make(map[uint64]*group)
+	for _, series := range matrix {
+		var metric labels.Labels
+		if a.Without {
+			metric = labels.NewBuilder(series.Metric).Del(append([]string{labels.MetricName}, a.Grouping...)...).Labels(nil)
+		} else {
+			metric = labels.NewBuilder(series.Metric).Keep(a.Grouping...).Labels(nil)
+		}
+		hash := metric.Hash()
+		g, ok := groups[hash]
+		if !ok {
+			g = &group{
+				metric: metric,
+				values: make(map[int64][]float64),
+			}
+			groups[hash] = g
+		}
+		for _, point := range series.Points {
+			g.values[point.T] = append(g.values[point.T], point.V)
+		}
+	}
+	result := make(promql.Matrix, 0, len(groups))
+	for _, g := range groups {
+		points := make([]promql.Point, 0, len(g.values))
+		for ts, values := range g.values {
+			points = append(points, promql.Point{
+				T: ts,
+				V: aggregateValues(a.Op, values),
+			})
+		}
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].T < points[j].T
+		})
+		result = append(result, promql.Series{
+			Metric: g.metric,
+			Points: points,
+		})
+	}
+	return result
+}
+
+// aggregateValues aggregates values by PromQL aggregation operator op as Prometheus does, values must not be empty
+func aggregateValues(op parser.ItemType, values []float64) float64 {
+	switch op {
+	case parser.SUM:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	case parser.AVG:
+		return aggregateValues(parser.SUM, values) / float64(len(values))
+	case parser.MAX:
+		max := values[0]
+		for _, v := range values[1:] {
+			if v > max || math.IsNaN(max) {
+				max = v
+			}
+		}
+		return max
+	case parser.MIN:
+		min := values[0]
+		for _, v := range values[1:] {
+			if v < min || math.IsNaN(min) {
+				min = v
+			}
+		}
+		return min
+	case parser.COUNT:
+		return float64(len(values))
+	case parser.STDDEV:
+		var count, mean, aux float64
+		for _, v := range values {
+			count++
+			delta := v - mean
+			mean += delta / count
+			aux += delta * (v - mean)
+		}
+		return math.Sqrt(aux / count)
+	}
+	return math.NaN()
+}
+
+// scalarBinop applies PromQL binary operation e between every point of matrix and scalar as Prometheus does.
+// If swap is true, the scalar is the left-hand side. Points are filtered out by comparisons without bool modifier,
+// and metric names are dropped by arithmetic operations.
+func scalarBinop(e *parser.BinaryExpr, matrix promql.Matrix, scalar float64, swap bool) promql.Matrix {
+	result := matrix[:0]
+	for _, series := range matrix {
+		points := series.Points[:0]
+		for _, point := range series.Points {
+			lhs, rhs := point.V, scalar
+			if swap {
+				lhs, rhs = rhs, lhs
+			}
+			v, keep := vectorElemBinop(e.Op, lhs, rhs)
+			// The vector's value is always kept by comparisons even if it is on the right-hand side
+			if e.Op.IsComparisonOperator() && swap {
+				v = rhs
+			}
+			if e.ReturnBool {
+				v = 0
+				if keep {
+					v = 1
+				}
+				keep = true
+			}
+			if keep {
+				points = append(points, promql.Point{
+					T: point.T,
+					V: v,
+				})
+			}
+		}
+		if len(points) == 0 {
+			continue
+		}
+		if !e.Op.IsComparisonOperator() || e.ReturnBool {
+			series.Metric = labels.NewBuilder(series.Metric).Del(labels.MetricName).Labels(nil)
+		}
+		series.Points = points
+		result = append(result, series)
+	}
+	return result
+}
+
+// vectorElemBinop is ported from Prometheus's promql/engine.go. It evaluates a binary operation between two values,
+// the returned bool is false if the comparison doesn't hold.
+func vectorElemBinop(op parser.ItemType, lhs, rhs float64) (float64, bool) {
+	switch op {
+	case parser.ADD:
+		return lhs + rhs, true
+	case parser.SUB:
+		return lhs - rhs, true
+	case parser.MUL:
+		return lhs * rhs, true
+	case parser.DIV:
+		return lhs / rhs, true
+	case parser.POW:
+		return math.Pow(lhs, rhs), true
+	case parser.MOD:
+		return math.Mod(lhs, rhs), true
+	case parser.EQLC:
+		return lhs, lhs == rhs
+	case parser.NEQ:
+		return lhs, lhs != rhs
+	case parser.GTR:
+		return lhs, lhs > rhs
+	case parser.LSS:
+		return lhs, lhs < rhs
+	case parser.GTE:
+		return lhs, lhs >= rhs
+	case parser.LTE:
+		return lhs, lhs <= rhs
+	}
+	return math.NaN(), false
+}
+
+// evaluationTime returns evaluation time of instant query cmd
+func evaluationTime(cmd prommodels.PromCommand) time.Time {
+	if cmd.End != nil {
+		return *cmd.End
+	}
+	if cmd.Evaluation != nil {
+		return *cmd.Evaluation
+	}
+	return time.Now()
+}
+
+// windowPoints returns points within [rangeStart, rangeEnd] from points sorted by time
+func windowPoints(points []promql.Point, rangeStart, rangeEnd int64) []promql.Point {
+	from := sort.Search(len(points), func(i int) bool {
+		return points[i].T >= rangeStart
+	})
+	to := sort.Search(len(points), func(i int) bool {
+		return points[i].T > rangeEnd
+	})
+	return points[from:to]
+}
+
+// extrapolatedRate is ported from Prometheus's promql/functions.go. It calculates the rate (allowing for counter resets
+// if isCounter is true), extrapolates if the first/last sample is close to the boundary, and returns the result as either
+// per-second (if isRate is true) or overall. It returns false if there are less than two samples.
+func extrapolatedRate(points []promql.Point, rangeStart, rangeEnd int64, selectRange time.Duration, isCounter, isRate bool) (float64, bool) {
+	// No sense in trying to compute a rate without at least two points.
+	if len(points) < 2 {
+		return 0, false
+	}
+	first, last := points[0], points[len(points)-1]
+	resultValue := last.V - first.V
+	if isCounter {
+		prevValue := first.V
+		for _, point := range points[1:] {
+			if point.V < prevValue {
+				resultValue += prevValue
+			}
+			prevValue = point.V
+		}
+	}
+
+	// Duration between first/last samples and boundary of range.
+	durationToStart := float64(first.T-rangeStart) / 1000
+	durationToEnd := float64(rangeEnd-last.T) / 1000
+
+	sampledInterval := float64(last.T-first.T) / 1000
+	averageDurationBetweenSamples := sampledInterval / float64(len(points)-1)
+
+	if isCounter && resultValue > 0 && first.V >= 0 {
+		// Counters cannot be negative. If we have any slope at all (i.e. resultValue went up), we can extrapolate
+		// the zero point of the counter. If the duration to the zero point is shorter than the durationToStart,
+		// we take the zero point as the start of the series, thereby avoiding extrapolation to negative counter values.
+		durationToZero := sampledInterval * (first.V / resultValue)
+		if durationToZero < durationToStart {
+			durationToStart = durationToZero
+		}
+	}
+
+	// If the first/last samples are close to the boundaries of the range, extrapolate the result. This is as we expect
+	// that another sample will exist given the spacing between samples we've seen thus far, with an allowance for noise.
+	extrapolationThreshold := averageDurationBetweenSamples * 1.1
+	extrapolateToInterval := sampledInterval
+
+	if durationToStart < extrapolationThreshold {
+		extrapolateToInterval += durationToStart
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+	if durationToEnd < extrapolationThreshold {
+		extrapolateToInterval += durationToEnd
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+	resultValue = resultValue * (extrapolateToInterval / sampledInterval)
+	if isRate {
+		resultValue = resultValue / selectRange.Seconds()
+	}
+	return resultValue, true
+}
+
+// linearRegression is ported from Prometheus's promql/functions.go. It returns the slope and the intercept at
+// interceptTime of the least squares fit of points.
+func linearRegression(points []promql.Point, interceptTime int64) (slope, intercept float64) {
+	var (
+		n          float64
+		sumX, sumY float64
+		sumXY      float64
+		sumX2      float64
+		initY      float64
+		constY     bool
+	)
+	initY = points[0].V
+	constY = true
+	for i, point := range points {
+		// Set constY to false if any new y values are encountered.
+		if constY && i > 0 && point.V != initY {
+			constY = false
+		}
+		n += 1.0
+		x := float64(point.T-interceptTime) / 1e3
+		sumX += x
+		sumY += point.V
+		sumXY += x * point.V
+		sumX2 += x * x
+	}
+	if constY {
+		if math.IsInf(initY, 0) {
+			return math.NaN(), math.NaN()
+		}
+		return 0, initY
+	}
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+
+	slope = covXY / varX
+	intercept = sumY/n - slope*sumX/n
+	return slope, intercept
+}
+
+// quantile is ported from Prometheus's promql/quantile.go. It calculates the q-quantile of values by linear interpolation
+// between the nearest ranks, values must not be empty.
+func quantile(q float64, values []float64) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	sort.Float64s(values)
+	n := float64(len(values))
+	// When the quantile lies between two values, we use a weighted average of the two values.
+	rank := q * (n - 1)
+
+	lowerIndex := math.Max(0, math.Floor(rank))
+	upperIndex := math.Min(n-1, lowerIndex+1)
+
+	weight := rank - math.Floor(rank)
+	return values[int(lowerIndex)]*(1-weight) + values[int(upperIndex)]*weight
+}
@@ -0,0 +1,448 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/copier"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/mock"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// linearPoints returns one point every 10 seconds from from to to seconds with value equal to the timestamp in seconds plus base
+func linearPoints(from, to int64, base float64) []promql.Point {
+	var points []promql.Point
+	for ts := from; ts <= to; ts += 10 {
+		points = append(points, promql.Point{T: ts * 1000, V: float64(ts) + base})
+	}
+	return points
+}
+
+func Test_extrapolatedRate(t *testing.T) {
+	type args struct {
+		points     []promql.Point
+		rangeStart int64
+		rangeEnd   int64
+		isRate     bool
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   float64
+		wantOk bool
+	}{
+		{
+			name: "",
+			args: args{
+				points:     linearPoints(0, 60, 0),
+				rangeStart: 0,
+				rangeEnd:   60000,
+				isRate:     true,
+			},
+			want:   1,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				points:     linearPoints(0, 60, 0),
+				rangeStart: 0,
+				rangeEnd:   60000,
+			},
+			want:   60,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				points: []promql.Point{
+					{T: 0, V: 10},
+					{T: 15000, V: 20},
+					{T: 30000, V: 30},
+					{T: 45000, V: 5},
+					{T: 60000, V: 15},
+				},
+				rangeStart: 0,
+				rangeEnd:   60000,
+			},
+			want:   35,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				points:     linearPoints(5, 55, -4),
+				rangeStart: 0,
+				rangeEnd:   60000,
+			},
+			want:   56,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				points: []promql.Point{
+					{T: 5000, V: 1},
+					{T: 15000, V: 2},
+					{T: 25000, V: 3},
+					{T: 35000, V: 4},
+					{T: 45000, V: 5},
+					{T: 55000, V: 6},
+				},
+				rangeStart: 0,
+				rangeEnd:   60000,
+			},
+			want:   6,
+			wantOk: true,
+		},
+		{
+			name: "",
+			args: args{
+				points:     []promql.Point{{T: 5000, V: 1}},
+				rangeStart: 0,
+				rangeEnd:   60000,
+			},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := extrapolatedRate(tt.args.points, tt.args.rangeStart, tt.args.rangeEnd, time.Minute, true, tt.args.isRate)
+			if gotOk != tt.wantOk {
+				t.Errorf("extrapolatedRate() gotOk = %v, want %v", gotOk, tt.wantOk)
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("extrapolatedRate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newRangeFunc returns transpiler.RangeFunc of input found by transpiling it as a graph data range query whose step
+// is shorter than ranges of input in models.RATE_EXTRAPOLATED mode
+func newRangeFunc(t *testing.T, input string) *transpiler.RangeFunc {
+	expr, err := parser.ParseExpr(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := time.Unix(120, 0)
+	tr := &transpiler.Transpiler{
+		PromCommand: models.PromCommand{
+			End:       &end,
+			Step:      time.Second,
+			QueryType: models.RANGE_QUERY,
+			DataType:  models.GRAPH_DATA,
+			RateMode:  models.RATE_EXTRAPOLATED,
+		},
+	}
+	if _, err = tr.Transpile(expr); err != nil {
+		t.Fatal(err)
+	}
+	return tr.RangeFunc()
+}
+
+func TestQueryCommandRunner_evalRangeFunc(t *testing.T) {
+	end := time.Unix(120, 0)
+	start := time.Unix(60, 0)
+	at := time.Unix(60, 0)
+	matrix := promql.Matrix{
+		{
+			Metric: labels.FromStrings("__name__", "http_requests_total", "host", "a"),
+			Points: linearPoints(0, 120, 0),
+		},
+		{
+			Metric: labels.FromStrings("__name__", "http_requests_total", "host", "b"),
+			Points: []promql.Point{{T: 0, V: 1}},
+		},
+	}
+	type args struct {
+		cmd          models.PromCommand
+		stepModifier transpiler.StepModifier
+		expr         string
+	}
+	tests := []struct {
+		name           string
+		args           args
+		want           parser.Value
+		wantResultType string
+	}{
+		{
+			name: "",
+			args: args{
+				cmd: models.PromCommand{
+					End:       &end,
+					QueryType: models.INSTANT_QUERY,
+				},
+				expr: `rate(http_requests_total[1m])`,
+			},
+			want: promql.Vector{
+				{
+					Metric: labels.FromStrings("host", "a"),
+					Point:  promql.Point{T: timestamp.FromTime(end), V: 1},
+				},
+			},
+			wantResultType: string(parser.ValueTypeVector),
+		},
+		{
+			name: "",
+			args: args{
+				cmd: models.PromCommand{
+					Start:     &start,
+					End:       &end,
+					Step:      time.Minute,
+					QueryType: models.RANGE_QUERY,
+				},
+				stepModifier: transpiler.StepModifier{
+					Offset: time.Minute,
+				},
+				expr: `increase(http_requests_total[1m] offset 1m)`,
+			},
+			want: promql.Matrix{
+				{
+					Metric: labels.FromStrings("host", "a"),
+					Points: []promql.Point{{T: timestamp.FromTime(end), V: 60}},
+				},
+			},
+			wantResultType: string(parser.ValueTypeMatrix),
+		},
+		{
+			name: "",
+			args: args{
+				cmd: models.PromCommand{
+					Start:     &start,
+					End:       &end,
+					Step:      time.Minute,
+					QueryType: models.RANGE_QUERY,
+				},
+				stepModifier: transpiler.StepModifier{
+					At: &at,
+				},
+				expr: `increase(http_requests_total[1m] @ 60)`,
+			},
+			want: promql.Matrix{
+				{
+					Metric: labels.FromStrings("host", "a"),
+					Points: []promql.Point{{T: timestamp.FromTime(start), V: 60}, {T: timestamp.FromTime(end), V: 60}},
+				},
+			},
+			wantResultType: string(parser.ValueTypeMatrix),
+		},
+		{
+			// Every step has a window as wide as the range rather than the step
+			name: "",
+			args: args{
+				cmd: models.PromCommand{
+					Start:     &start,
+					End:       &end,
+					Step:      30 * time.Second,
+					QueryType: models.RANGE_QUERY,
+				},
+				expr: `sum_over_time(http_requests_total[1m])`,
+			},
+			want: promql.Matrix{
+				{
+					Metric: labels.FromStrings("host", "a"),
+					Points: []promql.Point{{T: 60000, V: 210}, {T: 90000, V: 420}, {T: 120000, V: 630}},
+				},
+				{
+					Metric: labels.FromStrings("host", "b"),
+					Points: []promql.Point{{T: 60000, V: 1}},
+				},
+			},
+			wantResultType: string(parser.ValueTypeMatrix),
+		},
+		{
+			name: "",
+			args: args{
+				cmd: models.PromCommand{
+					Start:     &start,
+					End:       &end,
+					Step:      30 * time.Second,
+					QueryType: models.RANGE_QUERY,
+				},
+				expr: `sum(count_over_time(http_requests_total[1m])) * 2 > 10`,
+			},
+			want: promql.Matrix{
+				{
+					Metric: labels.EmptyLabels(),
+					Points: []promql.Point{{T: 60000, V: 16}, {T: 90000, V: 14}, {T: 120000, V: 14}},
+				},
+			},
+			wantResultType: string(parser.ValueTypeMatrix),
+		},
+		{
+			name: "",
+			args: args{
+				cmd: models.PromCommand{
+					End:       &end,
+					QueryType: models.INSTANT_QUERY,
+				},
+				expr: `max by (host) (quantile_over_time(0.5, http_requests_total[1m])) < bool 100`,
+			},
+			want: promql.Vector{
+				{
+					Metric: labels.FromStrings("host", "a"),
+					Point:  promql.Point{T: timestamp.FromTime(end), V: 1},
+				},
+			},
+			wantResultType: string(parser.ValueTypeVector),
+		},
+		{
+			name: "",
+			args: args{
+				cmd: models.PromCommand{
+					End:       &end,
+					QueryType: models.INSTANT_QUERY,
+				},
+				expr: `deriv(http_requests_total[1m])`,
+			},
+			want: promql.Vector{
+				{
+					Metric: labels.FromStrings("host", "a"),
+					Point:  promql.Point{T: timestamp.FromTime(end), V: 1},
+				},
+			},
+			wantResultType: string(parser.ValueTypeVector),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &QueryCommandRunner{}
+			series := make(promql.Matrix, len(matrix))
+			copy(series, matrix)
+			got, gotResultType, err := receiver.evalRangeFunc(series, tt.args.cmd, tt.args.stepModifier, newRangeFunc(t, tt.args.expr))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evalRangeFunc() got = %v, want %v", got, tt.want)
+			}
+			if gotResultType != tt.wantResultType {
+				t.Errorf("evalRangeFunc() gotResultType = %v, want %v", gotResultType, tt.wantResultType)
+			}
+		})
+	}
+}
+
+func TestQueryCommandRunner_Run_RangeFunc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := "prometheus"
+	response := mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"http_requests_total","tags":{"host":"a"},"columns":["time","value"],"values":[["1970-01-01T00:00:00Z",0],["1970-01-01T00:00:10Z",10],["1970-01-01T00:00:20Z",20],["1970-01-01T00:00:30Z",30],["1970-01-01T00:00:40Z",40],["1970-01-01T00:00:50Z",50],["1970-01-01T00:01:00Z",60],["1970-01-01T00:01:10Z",70],["1970-01-01T00:01:20Z",80],["1970-01-01T00:01:30Z",90],["1970-01-01T00:01:40Z",100],["1970-01-01T00:01:50Z",110],["1970-01-01T00:02:00Z",120]]}]}]}`)
+	mockClient := mock.NewMockClient(ctrl)
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SELECT *::tag, value FROM http_requests_total WHERE time <= '1970-01-01T00:02:00Z' AND time >= '1970-01-01T00:00:00Z' GROUP BY *`, database, "")).
+		Return(response, nil).
+		AnyTimes()
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SELECT *::tag, value FROM http_requests_total WHERE time <= '1970-01-01T00:02:00Z' AND time >= '1970-01-01T00:01:00Z' GROUP BY *`, database, "")).
+		Return(response, nil).
+		AnyTimes()
+	// rate in approximate mode is evaluated by InfluxDB over time buckets as wide as the step
+	mockClient.
+		EXPECT().Query(client.NewQuery(`SELECT *::tag, non_negative_derivative(value) FROM http_request_duration_seconds_bucket WHERE time <= '1970-01-01T00:02:00Z' AND time >= '1970-01-01T00:00:45Z' GROUP BY *, time(15s) fill(none)`, database, "")).
+		Return(mustUnmarshalResponse(t, `{"Results":[{"statement_id":0,"Series":[{"name":"http_request_duration_seconds_bucket","tags":{"le":"0.5"},"columns":["time","non_negative_derivative"],"values":[["1970-01-01T00:01:00Z",1],["1970-01-01T00:01:15Z",2]]},{"name":"http_request_duration_seconds_bucket","tags":{"le":"+Inf"},"columns":["time","non_negative_derivative"],"values":[["1970-01-01T00:01:00Z",2],["1970-01-01T00:01:15Z",4]]}]}]}`), nil).
+		AnyTimes()
+
+	start := time.Unix(60, 0)
+	end := time.Unix(120, 0)
+	tests := []struct {
+		name         string
+		cmd          models.PromCommand
+		expectedJson string
+		wantErr      bool
+	}{
+		{
+			// The step is shorter than the range, every step has its own window of one minute
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:       `sum(rate(http_requests_total[1m]))`,
+				Database:  database,
+				Start:     &start,
+				End:       &end,
+				Step:      15 * time.Second,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.GRAPH_DATA,
+				RateMode:  models.RATE_EXTRAPOLATED,
+			},
+			expectedJson: `{"Result":[{"metric":{},"values":[[60,"1"],[75,"1"],[90,"1"],[105,"1"],[120,"1"]]}],"ResultType":"matrix","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:       `max_over_time(http_requests_total[1m])`,
+				Database:  database,
+				Start:     &start,
+				End:       &end,
+				Step:      15 * time.Second,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.GRAPH_DATA,
+				RateMode:  models.RATE_EXTRAPOLATED,
+			},
+			expectedJson: `{"Result":[{"metric":{"host":"a"},"values":[[60,"60"],[75,"70"],[90,"90"],[105,"100"],[120,"120"]]}],"ResultType":"matrix","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:       `histogram_quantile(0.5, rate(http_request_duration_seconds_bucket[5m]))`,
+				Database:  database,
+				Start:     &start,
+				End:       &end,
+				Step:      15 * time.Second,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.GRAPH_DATA,
+			},
+			expectedJson: `{"Result":[{"metric":{},"values":[[75,"0.5"],[90,"0.5"]]}],"ResultType":"matrix","Error":null}`,
+			wantErr:      false,
+		},
+		{
+			// rate and increase wrapped by aggregations are evaluated locally before the aggregations in extrapolated mode
+			name: "",
+			cmd: models.PromCommand{
+				Cmd:      `sum by (host) (increase(http_requests_total[1m]))`,
+				Database: database,
+				End:      &end,
+				RateMode: models.RATE_EXTRAPOLATED,
+			},
+			expectedJson: `{"Result":[{"metric":{"host":"a"},"value":[120,"60"]}],"ResultType":"vector","Error":null}`,
+			wantErr:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := SingletonQueryCommandRunnerFactory.Build(mockClient, QueryCommandRunnerConfig{
+				Timeout: MustParseDuration("1m", t),
+			})
+			defer receiver.Recycle()
+			got, err := receiver.Run(context.Background(), tt.cmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var expected map[string]interface{}
+			if err = json.Unmarshal([]byte(tt.expectedJson), &expected); err != nil {
+				t.Fatal(err)
+			}
+			var gotCopy map[string]interface{}
+			copier.DeepCopy(got, &gotCopy)
+			if !reflect.DeepEqual(gotCopy, expected) {
+				gotJ, _ := json.Marshal(got)
+				t.Errorf("Run() got = %s, want %v", gotJ, tt.expectedJson)
+			}
+		})
+	}
+}
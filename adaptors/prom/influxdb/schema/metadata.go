@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"github.com/prometheus/prometheus/model/textparse"
+	"strings"
+)
+
+// MetricTypes infers metric families and their types from metric names by Prometheus naming conventions,
+// as InfluxDB doesn't store metric types:
+//   - xxx_bucket makes family xxx a histogram, whose members are xxx_bucket, xxx_sum and xxx_count
+//   - xxx_sum and xxx_count make family xxx a summary if there is no xxx_bucket, whose members are xxx, xxx_sum and xxx_count
+//   - xxx_total is a counter
+//   - others are gauges
+func MetricTypes(metricNames []string) map[string]textparse.MetricType {
+	nameMap := make(map[string]struct{}, len(metricNames))
+	for _, name := range metricNames {
+		nameMap[name] = struct{}{}
+	}
+	exists := func(name string) bool {
+		_, ok := nameMap[name]
+		return ok
+	}
+	families := make(map[string]textparse.MetricType)
+	for _, name := range metricNames {
+		if base := strings.TrimSuffix(name, bucketSuffix); base != name {
+			families[base] = textparse.MetricTypeHistogram
+		}
+	}
+	for _, name := range metricNames {
+		base := strings.TrimSuffix(strings.TrimSuffix(name, sumSuffix), countSuffix)
+		if base == name {
+			continue
+		}
+		if _, ok := families[base]; ok {
+			continue
+		}
+		if exists(base+sumSuffix) && exists(base+countSuffix) {
+			families[base] = textparse.MetricTypeSummary
+		}
+	}
+	isMember := func(name string) bool {
+		for _, suffix := range []string{bucketSuffix, sumSuffix, countSuffix} {
+			if base := strings.TrimSuffix(name, suffix); base != name {
+				if _, ok := families[base]; ok {
+					return true
+				}
+			}
+		}
+		// Quantiles of a summary are stored as the family name itself
+		return families[name] == textparse.MetricTypeSummary
+	}
+	metricTypes := make(map[string]textparse.MetricType, len(families))
+	for name, metricType := range families {
+		metricTypes[name] = metricType
+	}
+	for _, name := range metricNames {
+		if isMember(name) {
+			continue
+		}
+		if _, ok := metricTypes[name]; ok {
+			continue
+		}
+		if strings.HasSuffix(name, totalSuffix) {
+			metricTypes[name] = textparse.MetricTypeCounter
+		} else {
+			metricTypes[name] = textparse.MetricTypeGauge
+		}
+	}
+	return metricTypes
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"github.com/prometheus/prometheus/model/textparse"
+	"reflect"
+	"testing"
+)
+
+func TestMetricTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		metricNames []string
+		want        map[string]textparse.MetricType
+	}{
+		{
+			name:        "",
+			metricNames: []string{"go_goroutines", "http_requests_total"},
+			want: map[string]textparse.MetricType{
+				"go_goroutines":       textparse.MetricTypeGauge,
+				"http_requests_total": textparse.MetricTypeCounter,
+			},
+		},
+		{
+			name:        "",
+			metricNames: []string{"http_request_duration_seconds_bucket", "http_request_duration_seconds_count", "http_request_duration_seconds_sum"},
+			want: map[string]textparse.MetricType{
+				"http_request_duration_seconds": textparse.MetricTypeHistogram,
+			},
+		},
+		{
+			name:        "",
+			metricNames: []string{"go_gc_duration_seconds", "go_gc_duration_seconds_count", "go_gc_duration_seconds_sum"},
+			want: map[string]textparse.MetricType{
+				"go_gc_duration_seconds": textparse.MetricTypeSummary,
+			},
+		},
+		{
+			name:        "",
+			metricNames: []string{"rpc_calls_sum", "jobs_count"},
+			want: map[string]textparse.MetricType{
+				"rpc_calls_sum": textparse.MetricTypeGauge,
+				"jobs_count":    textparse.MetricTypeGauge,
+			},
+		},
+		{
+			name:        "",
+			metricNames: nil,
+			want:        map[string]textparse.MetricType{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MetricTypes(tt.metricNames); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MetricTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// Package schema maps Prometheus metrics to the layouts in which different writers store them in InfluxDB.
+//
+// Supported layouts:
+//   - PROM_WRITE: InfluxDB 1.x /api/v1/prom/write endpoint, measurement is metric name and field is value
+//   - TELEGRAF_V1: Telegraf prometheus input plugin with metric_version = 1, measurement is metric family
+//     and fields are counter, gauge, value, sum, count, le buckets or quantiles
+//   - TELEGRAF_V2: Telegraf prometheus input plugin with metric_version = 2, a single measurement (prometheus by default)
+//     and field is metric name
+package schema
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"strconv"
+	"strings"
+)
+
+const (
+	PROM_WRITE  = "prom_write"
+	TELEGRAF_V1 = "telegraf_v1"
+	TELEGRAF_V2 = "telegraf_v2"
+
+	defaultValueFieldKey       = "value"
+	defaultTelegrafMeasurement = "prometheus"
+)
+
+// Source is where samples of a Prometheus metric are stored in InfluxDB
+type Source struct {
+	// Measurement is InfluxDB measurement name
+	Measurement string
+	// FieldKey is InfluxDB field key of sample values
+	FieldKey string
+	// Condition is an optional tag condition expression in addition to the ones transpiled from label matchers
+	Condition influxql.Expr
+	// ConsumedLabels are label names whose matchers have been used to find Measurement or FieldKey,
+	// so they should not be transpiled to tag conditions
+	ConsumedLabels []string
+	// FieldLabel is the label name whose values are field keys, like le of histogram buckets and quantile of summaries
+	// written by Telegraf with metric_version = 1. Empty means FieldKey doesn't come from a label. If the label isn't selected
+	// by an equality matcher, FieldKey is empty and the caller should resolve the matchers to numeric field keys of Measurement.
+	FieldLabel string
+	// Alternative is where samples are stored instead if Measurement doesn't store them, because metric names can't tell
+	// metric types apart, like gauge disk_count and count of summary disk. Callers knowing field keys of Measurement
+	// check them by Stores, the others take this Source.
+	Alternative *Source
+}
+
+// Stores checks samples of the source are stored in a measurement with fieldKeys, i.e. FieldKey is one of them,
+// or there is any numeric field key to be resolved from FieldLabel matchers if FieldKey is empty
+func (receiver Source) Stores(fieldKeys []string) bool {
+	for _, fieldKey := range fieldKeys {
+		if stringutils.IsNotEmpty(receiver.FieldKey) {
+			if fieldKey == receiver.FieldKey {
+				return true
+			}
+			continue
+		}
+		if stringutils.IsNotEmpty(receiver.FieldLabel) {
+			if _, err := strconv.ParseFloat(fieldKey, 64); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Mapper maps Prometheus metrics to their Source in InfluxDB and back
+type Mapper interface {
+	// Source returns Source of metric with metricName selected by matchers.
+	// valueFieldKey is the field key set by user, it may be empty.
+	Source(metricName string, matchers []*labels.Matcher, valueFieldKey string) (Source, error)
+	// Metric rebuilds Prometheus metric name and extra labels from measurement and field key of query results.
+	// fieldLabel is FieldLabel of the queried Source, it is empty if unknown like listing metric names from field keys.
+	Metric(measurement string, fieldKey string, fieldLabel string) (metricName string, extraLabels labels.Labels)
+}
+
+// NewMapper returns Mapper for schema name. For TELEGRAF_V2, measurement is the single measurement name written by Telegraf,
+// default is prometheus.
+func NewMapper(name string, measurement string) (Mapper, error) {
+	switch name {
+	case "", PROM_WRITE:
+		return PromWriteMapper{}, nil
+	case TELEGRAF_V1:
+		return TelegrafV1Mapper{}, nil
+	case TELEGRAF_V2:
+		return TelegrafV2Mapper{Measurement: measurement}, nil
+	default:
+		return nil, errors.Errorf("unknown schema %q", name)
+	}
+}
+
+var _ Mapper = (*PromWriteMapper)(nil)
+
+// PromWriteMapper is the Mapper for data written through InfluxDB 1.x /api/v1/prom/write endpoint
+type PromWriteMapper struct {
+}
+
+// Source implements Mapper's Source method
+func (receiver PromWriteMapper) Source(metricName string, matchers []*labels.Matcher, valueFieldKey string) (Source, error) {
+	fieldKey := defaultValueFieldKey
+	if stringutils.IsNotEmpty(valueFieldKey) {
+		fieldKey = valueFieldKey
+	}
+	return Source{
+		Measurement: metricName,
+		FieldKey:    fieldKey,
+	}, nil
+}
+
+// Metric implements Mapper's Metric method
+func (receiver PromWriteMapper) Metric(measurement string, fieldKey string, fieldLabel string) (string, labels.Labels) {
+	return measurement, nil
+}
+
+var _ Mapper = (*TelegrafV2Mapper)(nil)
+
+// TelegrafV2Mapper is the Mapper for data written by Telegraf prometheus input plugin with metric_version = 2
+type TelegrafV2Mapper struct {
+	// Measurement is the single measurement name, default is prometheus
+	Measurement string
+}
+
+func (receiver TelegrafV2Mapper) measurement() string {
+	if stringutils.IsNotEmpty(receiver.Measurement) {
+		return receiver.Measurement
+	}
+	return defaultTelegrafMeasurement
+}
+
+// Source implements Mapper's Source method
+func (receiver TelegrafV2Mapper) Source(metricName string, matchers []*labels.Matcher, valueFieldKey string) (Source, error) {
+	return Source{
+		Measurement: receiver.measurement(),
+		FieldKey:    metricName,
+	}, nil
+}
+
+// Metric implements Mapper's Metric method
+func (receiver TelegrafV2Mapper) Metric(measurement string, fieldKey string, fieldLabel string) (string, labels.Labels) {
+	return fieldKey, nil
+}
+
+var _ Mapper = (*TelegrafV1Mapper)(nil)
+
+// TelegrafV1Mapper is the Mapper for data written by Telegraf prometheus input plugin with metric_version = 1.
+// As the metric type is not stored, it is inferred from Prometheus naming conventions:
+//   - xxx_bucket{le="0.5"} is field 0.5 of measurement xxx, without le equality matcher the bucket fields are left to the caller
+//     to be resolved
+//   - xxx_sum and xxx_count are field sum and count of measurement xxx
+//   - xxx{quantile="0.99"} is field 0.99 of measurement xxx
+//   - xxx_total is field counter of measurement xxx_total
+//   - others are field gauge, or valueFieldKey set by user like value for untyped metrics
+//
+// Names with the suffixes above may be gauges or counters as well, and summaries may be selected without quantile equality
+// matcher, so the other layout is set as Source's Alternative.
+type TelegrafV1Mapper struct {
+}
+
+const (
+	bucketSuffix  = "_bucket"
+	sumSuffix     = "_sum"
+	countSuffix   = "_count"
+	totalSuffix   = "_total"
+	bucketLabel   = "le"
+	quantileLabel = "quantile"
+
+	counterFieldKey = "counter"
+	gaugeFieldKey   = "gauge"
+	sumFieldKey     = "sum"
+	countFieldKey   = "count"
+)
+
+func equalMatcherValue(matchers []*labels.Matcher, name string) (string, bool) {
+	for _, matcher := range matchers {
+		if matcher.Name == name && matcher.Type == labels.MatchEqual {
+			return matcher.Value, true
+		}
+	}
+	return "", false
+}
+
+// Source implements Mapper's Source method
+func (receiver TelegrafV1Mapper) Source(metricName string, matchers []*labels.Matcher, valueFieldKey string) (Source, error) {
+	switch {
+	case strings.HasSuffix(metricName, bucketSuffix):
+		le, _ := equalMatcherValue(matchers, bucketLabel)
+		alternative := receiver.source(metricName, matchers, valueFieldKey)
+		return Source{
+			Measurement:    strings.TrimSuffix(metricName, bucketSuffix),
+			FieldKey:       le,
+			ConsumedLabels: []string{bucketLabel},
+			FieldLabel:     bucketLabel,
+			Alternative:    &alternative,
+		}, nil
+	case strings.HasSuffix(metricName, sumSuffix):
+		alternative := receiver.source(metricName, matchers, valueFieldKey)
+		return Source{
+			Measurement: strings.TrimSuffix(metricName, sumSuffix),
+			FieldKey:    sumFieldKey,
+			Alternative: &alternative,
+		}, nil
+	case strings.HasSuffix(metricName, countSuffix):
+		alternative := receiver.source(metricName, matchers, valueFieldKey)
+		return Source{
+			Measurement: strings.TrimSuffix(metricName, countSuffix),
+			FieldKey:    countFieldKey,
+			Alternative: &alternative,
+		}, nil
+	}
+	return receiver.source(metricName, matchers, valueFieldKey), nil
+}
+
+// source returns Source of metricName stored in the measurement of the same name, i.e. quantiles of summaries,
+// counters, gauges and untyped metrics. Summaries selected without quantile equality matcher are the Alternative of the others.
+func (receiver TelegrafV1Mapper) source(metricName string, matchers []*labels.Matcher, valueFieldKey string) Source {
+	if quantile, ok := equalMatcherValue(matchers, quantileLabel); ok {
+		return Source{
+			Measurement:    metricName,
+			FieldKey:       quantile,
+			ConsumedLabels: []string{quantileLabel},
+			FieldLabel:     quantileLabel,
+		}
+	}
+	fieldKey := gaugeFieldKey
+	if strings.HasSuffix(metricName, totalSuffix) {
+		fieldKey = counterFieldKey
+	}
+	if stringutils.IsNotEmpty(valueFieldKey) {
+		fieldKey = valueFieldKey
+	}
+	return Source{
+		Measurement: metricName,
+		FieldKey:    fieldKey,
+		Alternative: &Source{
+			Measurement:    metricName,
+			ConsumedLabels: []string{quantileLabel},
+			FieldLabel:     quantileLabel,
+		},
+	}
+}
+
+// Metric implements Mapper's Metric method
+func (receiver TelegrafV1Mapper) Metric(measurement string, fieldKey string, fieldLabel string) (string, labels.Labels) {
+	switch fieldLabel {
+	case bucketLabel:
+		return measurement + bucketSuffix, labels.FromStrings(bucketLabel, fieldKey)
+	case quantileLabel:
+		return measurement, labels.FromStrings(quantileLabel, fieldKey)
+	}
+	switch fieldKey {
+	case counterFieldKey, gaugeFieldKey, defaultValueFieldKey, "":
+		return measurement, nil
+	case sumFieldKey:
+		return measurement + sumSuffix, nil
+	case countFieldKey:
+		return measurement + countSuffix, nil
+	}
+	// Numeric field keys are le buckets of histograms or quantiles of summaries. Without fieldLabel we can't tell them apart
+	// from the field key itself, so they are taken as buckets.
+	return measurement + bucketSuffix, labels.FromStrings(bucketLabel, fieldKey)
+}
@@ -0,0 +1,371 @@
+package schema
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+	"reflect"
+	"testing"
+)
+
+func TestNewMapper(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      string
+		measurement string
+		want        Mapper
+		wantErr     bool
+	}{
+		{
+			name:   "",
+			schema: "",
+			want:   PromWriteMapper{},
+		},
+		{
+			name:   "",
+			schema: TELEGRAF_V1,
+			want:   TelegrafV1Mapper{},
+		},
+		{
+			name:        "",
+			schema:      TELEGRAF_V2,
+			measurement: "metrics",
+			want:        TelegrafV2Mapper{Measurement: "metrics"},
+		},
+		{
+			name:    "",
+			schema:  "graphite",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMapper(tt.schema, tt.measurement)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMapper() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewMapper() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_Source(t *testing.T) {
+	type args struct {
+		metricName    string
+		matchers      []*labels.Matcher
+		valueFieldKey string
+	}
+	tests := []struct {
+		name    string
+		mapper  Mapper
+		args    args
+		want    Source
+		wantErr bool
+	}{
+		{
+			name:   "",
+			mapper: PromWriteMapper{},
+			args: args{
+				metricName: "go_goroutines",
+			},
+			want: Source{
+				Measurement: "go_goroutines",
+				FieldKey:    "value",
+			},
+		},
+		{
+			name:   "",
+			mapper: PromWriteMapper{},
+			args: args{
+				metricName:    "cpu",
+				valueFieldKey: "usage_idle",
+			},
+			want: Source{
+				Measurement: "cpu",
+				FieldKey:    "usage_idle",
+			},
+		},
+		{
+			name:   "",
+			mapper: TelegrafV2Mapper{},
+			args: args{
+				metricName: "go_goroutines",
+			},
+			want: Source{
+				Measurement: "prometheus",
+				FieldKey:    "go_goroutines",
+			},
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				metricName: "http_request_duration_seconds_bucket",
+				matchers: []*labels.Matcher{
+					labels.MustNewMatcher(labels.MatchEqual, "le", "0.5"),
+				},
+			},
+			want: Source{
+				Measurement:    "http_request_duration_seconds",
+				FieldKey:       "0.5",
+				ConsumedLabels: []string{"le"},
+				FieldLabel:     "le",
+				Alternative: &Source{
+					Measurement: "http_request_duration_seconds_bucket",
+					FieldKey:    "gauge",
+					Alternative: &Source{
+						Measurement:    "http_request_duration_seconds_bucket",
+						ConsumedLabels: []string{"quantile"},
+						FieldLabel:     "quantile",
+					},
+				},
+			},
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				metricName: "http_request_duration_seconds_bucket",
+				matchers: []*labels.Matcher{
+					labels.MustNewMatcher(labels.MatchRegexp, "le", "0.5|1"),
+				},
+			},
+			want: Source{
+				Measurement:    "http_request_duration_seconds",
+				ConsumedLabels: []string{"le"},
+				FieldLabel:     "le",
+				Alternative: &Source{
+					Measurement: "http_request_duration_seconds_bucket",
+					FieldKey:    "gauge",
+					Alternative: &Source{
+						Measurement:    "http_request_duration_seconds_bucket",
+						ConsumedLabels: []string{"quantile"},
+						FieldLabel:     "quantile",
+					},
+				},
+			},
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				metricName: "http_request_duration_seconds_count",
+			},
+			want: Source{
+				Measurement: "http_request_duration_seconds",
+				FieldKey:    "count",
+				Alternative: &Source{
+					Measurement: "http_request_duration_seconds_count",
+					FieldKey:    "gauge",
+					Alternative: &Source{
+						Measurement:    "http_request_duration_seconds_count",
+						ConsumedLabels: []string{"quantile"},
+						FieldLabel:     "quantile",
+					},
+				},
+			},
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				metricName: "rpc_duration_seconds",
+				matchers: []*labels.Matcher{
+					labels.MustNewMatcher(labels.MatchEqual, "quantile", "0.99"),
+				},
+			},
+			want: Source{
+				Measurement:    "rpc_duration_seconds",
+				FieldKey:       "0.99",
+				ConsumedLabels: []string{"quantile"},
+				FieldLabel:     "quantile",
+			},
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				metricName: "http_requests_total",
+			},
+			want: Source{
+				Measurement: "http_requests_total",
+				FieldKey:    "counter",
+				Alternative: &Source{
+					Measurement:    "http_requests_total",
+					ConsumedLabels: []string{"quantile"},
+					FieldLabel:     "quantile",
+				},
+			},
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				metricName: "go_goroutines",
+			},
+			want: Source{
+				Measurement: "go_goroutines",
+				FieldKey:    "gauge",
+				Alternative: &Source{
+					Measurement:    "go_goroutines",
+					ConsumedLabels: []string{"quantile"},
+					FieldLabel:     "quantile",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.mapper.Source(tt.args.metricName, tt.args.matchers, tt.args.valueFieldKey)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Source() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Source() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSource_Stores(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    Source
+		fieldKeys []string
+		want      bool
+	}{
+		{
+			name:      "",
+			source:    Source{Measurement: "disk", FieldKey: "count"},
+			fieldKeys: []string{"free", "used"},
+			want:      false,
+		},
+		{
+			name:      "",
+			source:    Source{Measurement: "disk", FieldKey: "count"},
+			fieldKeys: []string{"count", "sum"},
+			want:      true,
+		},
+		{
+			name:      "",
+			source:    Source{Measurement: "rpc_duration_seconds", FieldLabel: "quantile"},
+			fieldKeys: []string{"0.5", "count", "sum"},
+			want:      true,
+		},
+		{
+			name:      "",
+			source:    Source{Measurement: "rpc_duration_seconds", FieldLabel: "quantile"},
+			fieldKeys: []string{"count", "sum"},
+			want:      false,
+		},
+		{
+			name:   "",
+			source: Source{Measurement: "rpc_duration_seconds", FieldLabel: "quantile"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.source.Stores(tt.fieldKeys); got != tt.want {
+				t.Errorf("Stores() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_Metric(t *testing.T) {
+	type args struct {
+		measurement string
+		fieldKey    string
+		fieldLabel  string
+	}
+	tests := []struct {
+		name            string
+		mapper          Mapper
+		args            args
+		wantMetricName  string
+		wantExtraLabels labels.Labels
+	}{
+		{
+			name:   "",
+			mapper: PromWriteMapper{},
+			args: args{
+				measurement: "go_goroutines",
+				fieldKey:    "value",
+			},
+			wantMetricName: "go_goroutines",
+		},
+		{
+			name:   "",
+			mapper: TelegrafV2Mapper{},
+			args: args{
+				measurement: "prometheus",
+				fieldKey:    "go_goroutines",
+			},
+			wantMetricName: "go_goroutines",
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				measurement: "http_requests_total",
+				fieldKey:    "counter",
+			},
+			wantMetricName: "http_requests_total",
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				measurement: "http_request_duration_seconds",
+				fieldKey:    "sum",
+			},
+			wantMetricName: "http_request_duration_seconds_sum",
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				measurement: "http_request_duration_seconds",
+				fieldKey:    "0.5",
+			},
+			wantMetricName:  "http_request_duration_seconds_bucket",
+			wantExtraLabels: labels.FromStrings("le", "0.5"),
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				measurement: "http_request_duration_seconds",
+				fieldKey:    "+Inf",
+				fieldLabel:  "le",
+			},
+			wantMetricName:  "http_request_duration_seconds_bucket",
+			wantExtraLabels: labels.FromStrings("le", "+Inf"),
+		},
+		{
+			name:   "",
+			mapper: TelegrafV1Mapper{},
+			args: args{
+				measurement: "rpc_duration_seconds",
+				fieldKey:    "0.99",
+				fieldLabel:  "quantile",
+			},
+			wantMetricName:  "rpc_duration_seconds",
+			wantExtraLabels: labels.FromStrings("quantile", "0.99"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMetricName, gotExtraLabels := tt.mapper.Metric(tt.args.measurement, tt.args.fieldKey, tt.args.fieldLabel)
+			if gotMetricName != tt.wantMetricName {
+				t.Errorf("Metric() gotMetricName = %v, want %v", gotMetricName, tt.wantMetricName)
+			}
+			if !reflect.DeepEqual(gotExtraLabels, tt.wantExtraLabels) {
+				t.Errorf("Metric() gotExtraLabels = %v, want %v", gotExtraLabels, tt.wantExtraLabels)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package influxdb
+
+import (
+	"context"
+	influxdb "github.com/influxdata/influxdb1-client/v2"
+	"github.com/influxdata/influxql"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/transpiler"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"io"
+)
+
+// Stream executes range query cmd and calls send with series decoded from every chunk of InfluxDB chunked response
+// as soon as the chunk arrives, so the whole matrix is never held in memory. A series may be split across consecutive
+// chunks, points of which are in time order. The next chunk is not read until send returns, so a slow consumer slows
+// down reading from InfluxDB as well. Reading is aborted once ctx is done. Cfg.Timeout is not applied because the duration
+// of a stream depends on the consumer, callers should bound ctx instead.
+//
+// Expressions which have to be evaluated over the whole result, i.e. multi-field queries, locally evaluated range functions,
+// steps pinned by @ modifier and scalar expressions, fall back to Run and the whole matrix is sent at once.
+func (receiver *QueryCommandRunner) Stream(ctx context.Context, cmd models.PromCommand, send func(matrix promql.Matrix) error) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+
+	}
+	if cmd.QueryType != models.RANGE_QUERY {
+		return errors.New("only range queries can be streamed")
+	}
+	if stringutils.IsEmpty(cmd.Cmd) {
+		return errors.New("command is empty")
+	}
+	expr, err := parser.ParseExpr(cmd.Cmd)
+	if err != nil {
+		return errors.Wrap(err, "command parse fail")
+	}
+	if _, ok := histogramQuantileCall(expr); ok {
+		return receiver.streamRunResult(ctx, cmd, send)
+	}
+	if selectors, _ := receiver.fieldSelectors(cmd, expr); len(selectors) > 0 {
+		return receiver.streamRunResult(ctx, cmd, send)
+	}
+	t := &transpiler.Transpiler{
+		PromCommand:  cmd,
+		SchemaMapper: receiver.schemaMapper(),
+		FieldKeys:    receiver.fieldKeys(cmd),
+	}
+	node, err := t.Transpile(expr)
+	if err != nil {
+		return errors.Wrap(err, "command execute fail")
+	}
+	statement, ok := node.(influxql.Statement)
+	if !ok || t.RangeFunc() != nil || t.StepModifier().Pinned() {
+		return receiver.streamRunResult(ctx, cmd, send)
+	}
+	cmd.ValueFieldKey, cmd.ValueFieldLabel = t.FieldKey(), t.FieldLabel()
+	influxCmd := statement.String()
+	if receiver.Cfg.Verbose {
+		zlogger.Info().Msgf("PromQL: %s => InfluxQL: %s", cmd.Cmd, influxCmd)
+	}
+	query := influxdb.NewQuery(influxCmd, cmd.Database, "")
+	query.ChunkSize = receiver.Cfg.ChunkSize
+	resp, err := receiver.Client.QueryAsChunk(query)
+	if err != nil {
+		return errors.Wrap(err, "error from influxdb api")
+	}
+	defer resp.Close()
+	// influxdb.Client doesn't accept context.Context, so we close the response body to interrupt the blocking read
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Close()
+		case <-done:
+		}
+	}()
+	stepModifier := t.StepModifier()
+	for {
+		chunk, err := resp.NextResponse()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "error from influxdb api")
+		}
+		if err = chunk.Error(); err != nil {
+			return errors.Wrap(err, "error from influxdb api")
+		}
+		value, _, err := receiver.InfluxResultToPromQLValue(chunk.Results, expr, cmd)
+		if err != nil {
+			return errors.Wrap(err, "fail to convert result from influxdb format to native prometheus format")
+		}
+		matrix, _ := value.(promql.Matrix)
+		if matrix = receiver.restampMatrix(matrix, cmd, stepModifier); len(matrix) == 0 {
+			continue
+		}
+		if err = send(matrix); err != nil {
+			return err
+		}
+	}
+}
+
+// streamRunResult runs cmd by Run and sends the whole matrix at once
+func (receiver *QueryCommandRunner) streamRunResult(ctx context.Context, cmd models.PromCommand, send func(matrix promql.Matrix) error) error {
+	runResult, err := receiver.Run(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	matrix, ok := runResult.Result.(promql.Matrix)
+	if !ok {
+		return errors.Errorf("unsupported result type %T for streaming", runResult.Result)
+	}
+	if len(matrix) == 0 {
+		return nil
+	}
+	return send(matrix)
+}
@@ -0,0 +1,145 @@
+package influxdb
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/mock"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chunkedResponse returns InfluxDB chunked response of the series of host a and b split into two chunks
+func chunkedResponse() *client.ChunkedResponse {
+	return client.NewChunkedResponse(strings.NewReader(
+		`{"results":[{"statement_id":0,"series":[{"name":"cpu","tags":{"host":"a"},"columns":["time","last"],"values":[["1970-01-01T00:00:00Z",1],["1970-01-01T00:01:00Z",2]],"partial":true}],"partial":true}]}
+{"results":[{"statement_id":0,"series":[{"name":"cpu","tags":{"host":"a"},"columns":["time","last"],"values":[["1970-01-01T00:02:00Z",3]]},{"name":"cpu","tags":{"host":"b"},"columns":["time","last"],"values":[["1970-01-01T00:00:00Z",4]]}]}]}
+`))
+}
+
+func TestQueryCommandRunner_Stream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	start := time.Unix(0, 0)
+	end := time.Unix(180, 0)
+	cmd := models.PromCommand{
+		Cmd:       `cpu{host=~"a|b"}`,
+		Database:  "prometheus",
+		Start:     &start,
+		End:       &end,
+		Timezone:  time.UTC,
+		Step:      time.Minute,
+		QueryType: models.RANGE_QUERY,
+		DataType:  models.GRAPH_DATA,
+	}
+	metric := func(host string) labels.Labels {
+		return promLabels(schema.PromWriteMapper{}, "cpu", "value", "", map[string]string{"host": host})
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type args struct {
+		ctx  context.Context
+		cmd  models.PromCommand
+		send func(chunks *[]promql.Matrix) func(matrix promql.Matrix) error
+	}
+	collect := func(chunks *[]promql.Matrix) func(matrix promql.Matrix) error {
+		return func(matrix promql.Matrix) error {
+			*chunks = append(*chunks, matrix)
+			return nil
+		}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []promql.Matrix
+		wantErr bool
+	}{
+		{
+			name: "",
+			args: args{
+				ctx:  context.Background(),
+				cmd:  cmd,
+				send: collect,
+			},
+			want: []promql.Matrix{
+				{
+					{
+						Metric: metric("a"),
+						Points: []promql.Point{{T: 60000, V: 1}, {T: 120000, V: 2}},
+					},
+				},
+				{
+					{
+						Metric: metric("a"),
+						Points: []promql.Point{{T: 180000, V: 3}},
+					},
+					{
+						Metric: metric("b"),
+						Points: []promql.Point{{T: 60000, V: 4}},
+					},
+				},
+			},
+		},
+		{
+			name: "",
+			args: args{
+				ctx: context.Background(),
+				cmd: cmd,
+				send: func(chunks *[]promql.Matrix) func(matrix promql.Matrix) error {
+					return func(matrix promql.Matrix) error {
+						*chunks = append(*chunks, matrix)
+						return errors.New("client gone")
+					}
+				},
+			},
+			want: []promql.Matrix{
+				{
+					{
+						Metric: metric("a"),
+						Points: []promql.Point{{T: 60000, V: 1}, {T: 120000, V: 2}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "",
+			args: args{
+				ctx:  canceled,
+				cmd:  cmd,
+				send: collect,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := mock.NewMockClient(ctrl)
+			mockClient.
+				EXPECT().QueryAsChunk(gomock.Any()).
+				Return(chunkedResponse(), nil).
+				AnyTimes()
+			receiver := &QueryCommandRunner{
+				Client: mockClient,
+			}
+			var got []promql.Matrix
+			err := receiver.Stream(tt.args.ctx, tt.args.cmd, tt.args.send(&got))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Stream() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Stream() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
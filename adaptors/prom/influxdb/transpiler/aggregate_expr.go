@@ -4,6 +4,7 @@ import (
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
 	influx "github.com/wubin1989/promql2influxql/adaptors/storages/influxdb"
 )
 
@@ -30,7 +31,7 @@ var aggregateFns = map[parser.ItemType]aggregateFn{
 func columnList(dimensions *[]*influxql.Dimension, strs ...string) {
 	for _, str := range strs {
 		*dimensions = append(*dimensions, &influxql.Dimension{
-			Expr: &influxql.VarRef{Val: str},
+			Expr: &influxql.VarRef{Val: escaping.UnescapeName(str)},
 		})
 	}
 }
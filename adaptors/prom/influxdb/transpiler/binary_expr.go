@@ -4,6 +4,7 @@ import (
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/promql/parser"
+	"reflect"
 )
 
 const (
@@ -268,6 +269,54 @@ func (t *Transpiler) transpileBinaryExpr(b *parser.BinaryExpr) (influxql.Node, e
 
 		return nil, errors.Errorf("invalid scalar-vector binary op %q (this should never happen)", b.Op)
 	default:
+		if op, ok := arithBinOps[b.Op]; ok {
+			if node, ok := t.transpileFieldsArithBin(b, op, lhs, rhs); ok {
+				return node, nil
+			}
+		}
 		return nil, errors.Errorf("not suppport both sides have VectorSelector expression: %s", b)
 	}
 }
+
+// transpileFieldsArithBin transpiles math operator PromQL BinaryExpr between two fields of the same series,
+// e.g. cpu{__field__="usage_user"} / cpu{__field__="usage_system"}. Both sides must be VectorSelector evaluated at the same time
+// and transpiled to the same statement except the selected field, so that the operator is applied to the fields of each row.
+// It returns false if b is not the case.
+func (t *Transpiler) transpileFieldsArithBin(b *parser.BinaryExpr, op influxql.Token, lhs, rhs influxql.Node) (influxql.Node, bool) {
+	left, ok := b.LHS.(*parser.VectorSelector)
+	if !ok {
+		return nil, false
+	}
+	right, ok := b.RHS.(*parser.VectorSelector)
+	if !ok {
+		return nil, false
+	}
+	if left.OriginalOffset != right.OriginalOffset || left.StartOrEnd != right.StartOrEnd ||
+		!reflect.DeepEqual(left.Timestamp, right.Timestamp) {
+		return nil, false
+	}
+	if b.VectorMatching != nil && (b.VectorMatching.Card != parser.CardOneToOne || b.VectorMatching.On ||
+		len(b.VectorMatching.MatchingLabels) > 0) {
+		return nil, false
+	}
+	lhsStatement, ok := lhs.(*influxql.SelectStatement)
+	if !ok {
+		return nil, false
+	}
+	rhsStatement, ok := rhs.(*influxql.SelectStatement)
+	if !ok {
+		return nil, false
+	}
+	lhsField := lhsStatement.Fields[len(lhsStatement.Fields)-1]
+	rhsField := rhsStatement.Fields[len(rhsStatement.Fields)-1]
+	lhsStatement.Fields[len(lhsStatement.Fields)-1] = rhsField
+	same := lhsStatement.String() == rhsStatement.String()
+	lhsStatement.Fields[len(lhsStatement.Fields)-1] = lhsField
+	if !same {
+		return nil, false
+	}
+	lhsStatement.Fields[len(lhsStatement.Fields)-1] = &influxql.Field{
+		Expr: t.NewBinaryExpr(op, lhsField.Expr, rhsField.Expr),
+	}
+	return lhsStatement, true
+}
@@ -159,6 +159,39 @@ func TestTranspiler_transpileBinaryExpr(t1 *testing.T) {
 			want:    influxql.MustParseStatement(`SELECT *::tag, last FROM (SELECT *::tag, last FROM (SELECT *::tag, last(value) FROM go_gc_duration_seconds_count GROUP BY *) WHERE last >= 3.000) WHERE last < 4.000`),
 			wantErr: false,
 		},
+		{
+			name: "12",
+			fields: fields{
+				Evaluation: &endTime2,
+			},
+			args: args{
+				b: testinghelper.BinaryExpr(`cpu{__field__="usage_user", host="telegraf"} / cpu{__field__="usage_system", host="telegraf"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(usage_user) / last(usage_system) FROM cpu WHERE host = 'telegraf' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "13",
+			fields: fields{
+				Evaluation: &endTime2,
+			},
+			args: args{
+				b: testinghelper.BinaryExpr(`cpu{__field__="usage_user", host="telegraf"} / cpu{__field__="usage_system"}`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "14",
+			fields: fields{
+				Evaluation: &endTime2,
+			},
+			args: args{
+				b: testinghelper.BinaryExpr(`cpu{__field__="usage_user"} / cpu{__field__="usage_system"} offset 5m`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
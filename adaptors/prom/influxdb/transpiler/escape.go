@@ -0,0 +1,96 @@
+package transpiler
+
+import (
+	"fmt"
+	"github.com/influxdata/influxql"
+	"github.com/pkg/errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// checkQueryText checks that s can be written into InfluxQL query text unchanged, as the InfluxQL scanner stops at NUL,
+// converts carriage returns to line feeds and replaces invalid UTF-8, none of which can be escaped.
+func checkQueryText(s string) error {
+	if !utf8.ValidString(s) {
+		return errors.Errorf("%q is not valid UTF-8", s)
+	}
+	if strings.ContainsAny(s, "\x00\r") {
+		return errors.Errorf("%q contains NUL or carriage return character", s)
+	}
+	return nil
+}
+
+// newRegexLiteral builds an anchored influxql.RegexLiteral from PromQL regex matcher value
+func newRegexLiteral(value string) (*influxql.RegexLiteral, error) {
+	re, err := regexp.Compile("^(?:" + escapeRegex(value) + ")$")
+	if err != nil {
+		return nil, errors.Wrap(err, "regular expression syntax error")
+	}
+	return &influxql.RegexLiteral{
+		Val: re,
+	}, nil
+}
+
+// escapeRegex replaces non-printable characters like line breaks in regular expression source with
+// equivalent \x{...} escape sequences, so that the regex can be safely written into InfluxQL query text
+// with the same meaning. Characters inside \Q...\E literal text are handled as well.
+func escapeRegex(value string) string {
+	if strings.IndexFunc(value, isNotPrintable) < 0 {
+		return value
+	}
+	var (
+		sb     strings.Builder
+		quoted bool
+	)
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quoted {
+			switch {
+			case r == '\\' && i+1 < len(runes) && runes[i+1] == 'E':
+				quoted = false
+				sb.WriteString(`\E`)
+				i++
+			case isNotPrintable(r):
+				// close literal text, write the escape sequence, then reopen literal text
+				sb.WriteString(`\E`)
+				sb.WriteString(hexEscape(r))
+				sb.WriteString(`\Q`)
+			default:
+				sb.WriteRune(r)
+			}
+			continue
+		}
+		if r == '\\' && i+1 < len(runes) {
+			next := runes[i+1]
+			i++
+			if isNotPrintable(next) {
+				// escaped non-word character always matches itself
+				sb.WriteString(hexEscape(next))
+				continue
+			}
+			if next == 'Q' {
+				quoted = true
+			}
+			sb.WriteRune(r)
+			sb.WriteRune(next)
+			continue
+		}
+		if isNotPrintable(r) {
+			sb.WriteString(hexEscape(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func isNotPrintable(r rune) bool {
+	return !unicode.IsPrint(r)
+}
+
+func hexEscape(r rune) string {
+	return fmt.Sprintf(`\x{%x}`, r)
+}
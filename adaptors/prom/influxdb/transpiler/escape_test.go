@@ -0,0 +1,163 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"testing"
+	"unicode/utf8"
+)
+
+func Test_escapeRegex(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "",
+			value: `tele.*`,
+			want:  `tele.*`,
+		},
+		{
+			name:  "",
+			value: "a\nb",
+			want:  `a\x{a}b`,
+		},
+		{
+			name:  "",
+			value: "[\r\n]+",
+			want:  `[\x{d}\x{a}]+`,
+		},
+		{
+			name:  "",
+			value: "\\Qa\nb\\E\n",
+			want:  `\Qa\E\x{a}\Qb\E\x{a}`,
+		},
+		{
+			name:  "",
+			value: `a\/b`,
+			want:  `a\/b`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeRegex(tt.value); got != tt.want {
+				t.Errorf("escapeRegex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// FuzzTranspiler_Transpile checks that matcher values and metric names can't change the structure of generated InfluxQL.
+// The generated statement must be parsed back by influxql.ParseStatement to the same statement, the tag condition must
+// still compare the same tag with the same value, and the regex must match the same strings as the PromQL matcher does.
+func FuzzTranspiler_Transpile(f *testing.F) {
+	seeds := []struct {
+		name   string
+		value  string
+		sample string
+	}{
+		{"cpu", "telegraf", "telegraf"},
+		{"cpu", "tele.*", "telegraf"},
+		{"cpu", "a/b", "a/b"},
+		{"cpu", `a\/b`, "a/b"},
+		{"cpu", `a\\/b`, `a\/b`},
+		{"cpu", `it's`, `it's`},
+		{"cpu", `say "hi"`, `say "hi"`},
+		{"cpu", "line\nbreak", "line\nbreak"},
+		{"cpu", "\\Qa\nb\\E", "a\nb"},
+		{"cpu", `back\\slash`, `back\slash`},
+		{"cpu", "escaped\\\x00nul", "escaped\x00nul"},
+		{"cpu", `x') OR ('1' = '1`, "x"},
+		{"cpu", `x/ OR host =~ /.*`, "x"},
+		{"cpu", `a)|(b`, "b"},
+		{"cpu", "", ""},
+		{"http-requests", "v", "v"},
+		{"cpu.usage", "v", "v"},
+		{`we"ird`, "v", "v"},
+		{"select", "v", "v"},
+		{"\r", "v", "v"},
+		{"U__cpu_2e_usage", "v", "v"},
+		{"cpu", "carriage\rreturn", "carriage\rreturn"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.name, seed.value, seed.sample)
+	}
+	f.Fuzz(func(t *testing.T, name, value, sample string) {
+		if name == "" || !utf8.ValidString(sample) {
+			t.Skip()
+		}
+		measurement := escaping.UnescapeName(name)
+		representable := checkQueryText(measurement) == nil && checkQueryText(value) == nil
+		for _, matchType := range []labels.MatchType{labels.MatchEqual, labels.MatchNotEqual, labels.MatchRegexp, labels.MatchNotRegexp} {
+			matcher, err := labels.NewMatcher(matchType, "host", value)
+			if err != nil {
+				continue
+			}
+			tr := &Transpiler{
+				PromCommand: models.PromCommand{
+					Evaluation: &endTime,
+				},
+			}
+			node, err := tr.Transpile(&parser.VectorSelector{
+				Name:          name,
+				LabelMatchers: []*labels.Matcher{matcher},
+			})
+			if err != nil {
+				if !representable {
+					continue
+				}
+				t.Fatalf("Transpile() error = %v", err)
+			}
+			got := node.String()
+			statement, err := influxql.ParseStatement(got)
+			if err != nil {
+				t.Fatalf("ParseStatement(%q) error = %v", got, err)
+			}
+			if statement.String() != got {
+				t.Fatalf("round-trip mismatch: got %q, want %q", statement.String(), got)
+			}
+			selectStatement, ok := statement.(*influxql.SelectStatement)
+			if !ok {
+				t.Fatalf("unexpected statement type %T", statement)
+			}
+			if len(selectStatement.Sources) != 1 || selectStatement.Sources[0].(*influxql.Measurement).Name != measurement {
+				t.Fatalf("unexpected sources %v, want measurement %q", selectStatement.Sources, measurement)
+			}
+			var hostConds []*influxql.BinaryExpr
+			influxql.WalkFunc(selectStatement.Condition, func(n influxql.Node) {
+				if expr, ok := n.(*influxql.BinaryExpr); ok {
+					if ref, ok := expr.LHS.(*influxql.VarRef); ok && ref.Val == "host" {
+						hostConds = append(hostConds, expr)
+					}
+				}
+			})
+			if len(hostConds) == 0 {
+				t.Fatalf("no condition on host tag in %q", got)
+			}
+			cond := hostConds[0]
+			switch matchType {
+			case labels.MatchEqual, labels.MatchNotEqual:
+				lit, ok := cond.RHS.(*influxql.StringLiteral)
+				if !ok || lit.Val != value {
+					t.Fatalf("condition %q doesn't compare host with %q", cond, value)
+				}
+			default:
+				lit, ok := cond.RHS.(*influxql.RegexLiteral)
+				if !ok {
+					t.Fatalf("condition %q doesn't compare host with regex", cond)
+				}
+				matched := lit.Val.MatchString(sample)
+				if cond.Op == influxql.NEQREGEX {
+					matched = !matched
+				}
+				if sample != "" && matched != matcher.Matches(sample) {
+					t.Fatalf("regex %s matches %q: %v, PromQL matcher %s: %v", lit, sample, matched, matcher, matcher.Matches(sample))
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+)
+
+// TranspileLabelNames transpiles label names query without series selector to SHOW TAG KEYS statement over all measurements
+// within the time range of t. Note that InfluxDB 1.x ignores time conditions of SHOW TAG KEYS on TSI indexes.
+func (t *Transpiler) TranspileLabelNames() (influxql.Statement, error) {
+	statement := &influxql.ShowTagKeysStatement{
+		Database: t.Database,
+	}
+	var err error
+	if t.timeCondition, _, err = t.transpileVectorSelector2ConditionExpr(&parser.VectorSelector{}, schema.Source{}); err != nil {
+		return nil, err
+	}
+	t.setTimeCondition(statement)
+	return statement, nil
+}
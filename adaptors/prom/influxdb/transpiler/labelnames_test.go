@@ -0,0 +1,52 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"testing"
+)
+
+func TestTranspiler_TranspileLabelNames(t1 *testing.T) {
+	tests := []struct {
+		name string
+		cmd  models.PromCommand
+		want influxql.Statement
+	}{
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Database:  "prometheus",
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.LABEL_NAMES_DATA,
+			},
+			want: influxql.MustParseStatement(`SHOW TAG KEYS ON prometheus WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z'`),
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Database:  "prometheus",
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.LABEL_NAMES_DATA,
+			},
+			want: influxql.MustParseStatement(`SHOW TAG KEYS ON prometheus WHERE time <= '2023-01-06T07:00:00Z'`),
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := &Transpiler{
+				PromCommand: tt.cmd,
+			}
+			got, err := t.TranspileLabelNames()
+			if err != nil {
+				t1.Errorf("TranspileLabelNames() error = %v", err)
+				return
+			}
+			if got.String() != tt.want.String() {
+				t1.Errorf("TranspileLabelNames() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"regexp"
+)
+
+// TranspileLabelValues transpiles label values query of t.LabelName without series selector to InfluxQL statement over all measurements
+func (t *Transpiler) TranspileLabelValues() (influxql.Statement, error) {
+	statement := t.labelValuesStatement(&influxql.Measurement{Regex: &influxql.RegexLiteral{Val: regexp.MustCompile(".*")}}, "", nil)
+	if t.LabelValuesMode == models.LABEL_VALUES_ACCURATE {
+		var err error
+		if t.timeCondition, _, err = t.transpileVectorSelector2ConditionExpr(&parser.VectorSelector{}, schema.Source{}); err != nil {
+			return nil, err
+		}
+		t.setTimeCondition(statement)
+	}
+	return statement, nil
+}
+
+// labelValuesStatement returns InfluxQL statement listing values of t.LabelName tag of the series in source selected by tagCondition.
+// By default it is a SHOW TAG VALUES statement which is answered from the index fast, but InfluxDB 1.x ignores time conditions of it
+// on TSI indexes. If t.LabelValuesMode is models.LABEL_VALUES_ACCURATE, it is a SELECT statement grouping by the tag instead,
+// which returns one series for every tag value having points of field fieldKey in the time range. Empty fieldKey means any field.
+func (t *Transpiler) labelValuesStatement(source *influxql.Measurement, fieldKey string, tagCondition influxql.Expr) influxql.Statement {
+	tagKey := escaping.UnescapeName(t.LabelName)
+	if t.LabelValuesMode != models.LABEL_VALUES_ACCURATE {
+		statement := &influxql.ShowTagValuesStatement{
+			Database:   t.Database,
+			Op:         influxql.EQ,
+			TagKeyExpr: &influxql.StringLiteral{Val: tagKey},
+			Condition:  tagCondition,
+		}
+		if source.Regex == nil {
+			statement.Sources = []influxql.Source{source}
+		}
+		return statement
+	}
+	var arg influxql.Expr = &influxql.Wildcard{}
+	if fieldKey != "" {
+		arg = &influxql.VarRef{Val: fieldKey}
+	}
+	return &influxql.SelectStatement{
+		Fields: []*influxql.Field{
+			{
+				Expr: &influxql.Call{
+					Name: "last",
+					Args: []influxql.Expr{arg},
+				},
+			},
+		},
+		Sources:    []influxql.Source{source},
+		Condition:  tagCondition,
+		Dimensions: []*influxql.Dimension{{Expr: &influxql.VarRef{Val: tagKey}}},
+	}
+}
@@ -0,0 +1,82 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"testing"
+)
+
+func TestTranspiler_TranspileLabelValues(t1 *testing.T) {
+	tests := []struct {
+		name string
+		cmd  models.PromCommand
+		want influxql.Statement
+	}{
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Database:  "prometheus",
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.LABEL_VALUES_DATA,
+				LabelName: "job",
+			},
+			want: influxql.MustParseStatement(`SHOW TAG VALUES ON prometheus WITH KEY = job`),
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Database:        "prometheus",
+				Start:           &startTime2,
+				End:             &endTime2,
+				QueryType:       models.RANGE_QUERY,
+				DataType:        models.LABEL_VALUES_DATA,
+				LabelName:       "job",
+				LabelValuesMode: models.LABEL_VALUES_ACCURATE,
+			},
+			want: influxql.MustParseStatement(`SELECT last(*) FROM /.*/ WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' GROUP BY job`),
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Database:        "prometheus",
+				End:             &endTime2,
+				QueryType:       models.RANGE_QUERY,
+				DataType:        models.LABEL_VALUES_DATA,
+				LabelName:       "job",
+				LabelValuesMode: models.LABEL_VALUES_ACCURATE,
+			},
+			want: influxql.MustParseStatement(`SELECT last(*) FROM /.*/ WHERE time <= '2023-01-06T07:00:00Z' GROUP BY job`),
+		},
+		{
+			name: "",
+			cmd: models.PromCommand{
+				Database:        "prometheus",
+				Start:           &startTime2,
+				End:             &endTime2,
+				Timezone:        newYork,
+				QueryType:       models.RANGE_QUERY,
+				DataType:        models.LABEL_VALUES_DATA,
+				LabelName:       "job",
+				LabelValuesMode: models.LABEL_VALUES_ACCURATE,
+			},
+			want: influxql.MustParseStatement(`SELECT last(*) FROM /.*/ WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' GROUP BY job TZ('America/New_York')`),
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := &Transpiler{
+				PromCommand: tt.cmd,
+			}
+			got, err := t.TranspileLabelValues()
+			if err != nil {
+				t1.Errorf("TranspileLabelValues() error = %v", err)
+				return
+			}
+			if got.String() != tt.want.String() {
+				t1.Errorf("TranspileLabelValues() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/pkg/errors"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"regexp"
+)
+
+// TranspileMetadata transpiles metric metadata query to SHOW FIELD KEYS statement, from whose results metric names are rebuilt
+// and metric types are inferred by schema.MetricTypes. t.Cmd is an optional metric family name to narrow the statement down:
+//   - for schema.PromWriteMapper, to the measurements of the family members, i.e. xxx, xxx_bucket, xxx_sum and xxx_count
+//   - for schema.TelegrafV1Mapper, to the measurement of the family
+//   - for schema.TelegrafV2Mapper, it is always the single measurement
+//
+// Results still have to be filtered by t.Cmd, as other metric families may be listed as well.
+func (t *Transpiler) TranspileMetadata() (influxql.Statement, error) {
+	statement := &influxql.ShowFieldKeysStatement{
+		Database: t.Database,
+	}
+	mapper := t.schemaMapper()
+	if _, ok := mapper.(schema.TelegrafV2Mapper); ok {
+		source, err := mapper.Source("", nil, t.ValueFieldKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "transpile metadata fail")
+		}
+		statement.Sources = []influxql.Source{&influxql.Measurement{Name: source.Measurement}}
+		return statement, nil
+	}
+	if stringutils.IsEmpty(t.Cmd) {
+		return statement, nil
+	}
+	measurement := escaping.UnescapeName(t.Cmd)
+	if err := checkQueryText(measurement); err != nil {
+		return nil, errors.Wrap(err, "invalid metric name")
+	}
+	switch mapper.(type) {
+	case schema.PromWriteMapper:
+		re, err := regexp.Compile("^" + regexp.QuoteMeta(measurement) + "(?:_bucket|_sum|_count)?$")
+		if err != nil {
+			return nil, errors.Wrap(err, "transpile metadata fail")
+		}
+		statement.Sources = []influxql.Source{&influxql.Measurement{Regex: &influxql.RegexLiteral{Val: re}}}
+	case schema.TelegrafV1Mapper:
+		statement.Sources = []influxql.Source{&influxql.Measurement{Name: measurement}}
+	}
+	return statement, nil
+}
@@ -0,0 +1,73 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"reflect"
+	"testing"
+)
+
+func TestTranspiler_TranspileMetadata(t1 *testing.T) {
+	tests := []struct {
+		name         string
+		schemaMapper schema.Mapper
+		metric       string
+		want         influxql.Statement
+		wantErr      bool
+	}{
+		{
+			name:    "",
+			metric:  "",
+			want:    influxql.MustParseStatement(`SHOW FIELD KEYS`),
+			wantErr: false,
+		},
+		{
+			name:    "",
+			metric:  "http_request_duration_seconds",
+			want:    influxql.MustParseStatement(`SHOW FIELD KEYS FROM /^http_request_duration_seconds(?:_bucket|_sum|_count)?$/`),
+			wantErr: false,
+		},
+		{
+			name:         "",
+			schemaMapper: schema.TelegrafV1Mapper{},
+			metric:       "go_gc_duration_seconds",
+			want:         influxql.MustParseStatement(`SHOW FIELD KEYS FROM go_gc_duration_seconds`),
+			wantErr:      false,
+		},
+		{
+			name:         "",
+			schemaMapper: schema.TelegrafV2Mapper{},
+			metric:       "go_goroutines",
+			want:         influxql.MustParseStatement(`SHOW FIELD KEYS FROM prometheus`),
+			wantErr:      false,
+		},
+		{
+			name:    "",
+			metric:  "go\x00goroutines",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := &Transpiler{
+				PromCommand: models.PromCommand{
+					Cmd:      tt.metric,
+					DataType: models.METADATA_DATA,
+				},
+				SchemaMapper: tt.schemaMapper,
+			}
+			got, err := t.TranspileMetadata()
+			if (err != nil) != tt.wantErr {
+				t1.Errorf("TranspileMetadata() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.String(), tt.want.String()) {
+				t1.Errorf("TranspileMetadata() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
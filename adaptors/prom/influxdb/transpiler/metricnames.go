@@ -0,0 +1,71 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+)
+
+// TranspileMetricNames transpiles label matchers of a series selector to InfluxQL statement listing metric names,
+// i.e. values of __name__ label, which are measurement names rather than tag values in InfluxDB.
+//
+// For schema.TelegrafV2Mapper metric names are field keys of the single measurement, so it is a SHOW FIELD KEYS statement
+// and matchers other than __name__ are ignored as InfluxDB doesn't support WHERE clause for it.
+// Otherwise it is a SHOW MEASUREMENTS statement. Matchers other than __name__ are transpiled to WHERE clause,
+// and the first equality or regular expression __name__ matcher is transpiled to WITH MEASUREMENT clause
+// if measurement names are metric names as they are for schema.PromWriteMapper.
+//
+// Results still have to be filtered by all __name__ matchers, as not every matcher can be expressed in InfluxQL.
+// InfluxDB doesn't bound SHOW MEASUREMENTS by time, so start and end time are not applied.
+func (t *Transpiler) TranspileMetricNames(matchers []*labels.Matcher) (influxql.Statement, error) {
+	mapper := t.schemaMapper()
+	if _, ok := mapper.(schema.TelegrafV2Mapper); ok {
+		source, err := mapper.Source("", nil, t.ValueFieldKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "transpile metric names fail")
+		}
+		return &influxql.ShowFieldKeysStatement{
+			Database: t.Database,
+			Sources:  []influxql.Source{&influxql.Measurement{Name: source.Measurement}},
+		}, nil
+	}
+	_, tagCondition, err := t.transpileVectorSelector2ConditionExpr(&parser.VectorSelector{
+		LabelMatchers: matchers,
+	}, schema.Source{})
+	if err != nil {
+		return nil, errors.Wrap(err, "transpile metric names fail")
+	}
+	statement := &influxql.ShowMeasurementsStatement{
+		Database:  t.Database,
+		Condition: tagCondition,
+	}
+	if _, ok := mapper.(schema.PromWriteMapper); !ok {
+		return statement, nil
+	}
+	for _, item := range matchers {
+		if item.Name != labels.MetricName {
+			continue
+		}
+		switch item.Type {
+		case labels.MatchEqual:
+			measurement := escaping.UnescapeName(item.Value)
+			if err = checkQueryText(measurement); err != nil {
+				return nil, errors.Wrap(err, "invalid metric name")
+			}
+			statement.Source = &influxql.Measurement{Name: measurement}
+		case labels.MatchRegexp:
+			re, err := newRegexLiteral(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			statement.Source = &influxql.Measurement{Regex: re}
+		default:
+			continue
+		}
+		break
+	}
+	return statement, nil
+}
@@ -0,0 +1,88 @@
+package transpiler
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"reflect"
+	"testing"
+)
+
+func TestTranspiler_TranspileMetricNames(t1 *testing.T) {
+	matchers := func(input string) []*labels.Matcher {
+		result, err := parser.ParseMetricSelector(input)
+		if err != nil {
+			panic(err)
+		}
+		return result
+	}
+	tests := []struct {
+		name         string
+		schemaMapper schema.Mapper
+		matchers     []*labels.Matcher
+		want         influxql.Statement
+		wantErr      bool
+	}{
+		{
+			name:     "",
+			matchers: nil,
+			want:     influxql.MustParseStatement(`SHOW MEASUREMENTS`),
+			wantErr:  false,
+		},
+		{
+			name:     "",
+			matchers: matchers(`go_goroutines{job="prometheus"}`),
+			want:     influxql.MustParseStatement(`SHOW MEASUREMENTS WITH MEASUREMENT = go_goroutines WHERE job = 'prometheus'`),
+			wantErr:  false,
+		},
+		{
+			name:     "",
+			matchers: matchers(`{__name__!="go_threads", __name__=~"go_.*"}`),
+			want:     influxql.MustParseStatement(`SHOW MEASUREMENTS WITH MEASUREMENT =~ /^(?:go_.*)$/`),
+			wantErr:  false,
+		},
+		{
+			name:         "",
+			schemaMapper: schema.TelegrafV1Mapper{},
+			matchers:     matchers(`go_gc_duration_seconds_count{job="prometheus"}`),
+			want:         influxql.MustParseStatement(`SHOW MEASUREMENTS WHERE job = 'prometheus'`),
+			wantErr:      false,
+		},
+		{
+			name:         "",
+			schemaMapper: schema.TelegrafV2Mapper{},
+			matchers:     matchers(`{__name__=~"go_.*", job="prometheus"}`),
+			want:         influxql.MustParseStatement(`SHOW FIELD KEYS FROM prometheus`),
+			wantErr:      false,
+		},
+		{
+			name:     "",
+			matchers: []*labels.Matcher{{Type: labels.MatchRegexp, Name: labels.MetricName, Value: "go_(.*"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := &Transpiler{
+				PromCommand: models.PromCommand{
+					DataType:  models.LABEL_VALUES_DATA,
+					LabelName: labels.MetricName,
+				},
+				SchemaMapper: tt.schemaMapper,
+			}
+			got, err := t.TranspileMetricNames(tt.matchers)
+			if (err != nil) != tt.wantErr {
+				t1.Errorf("TranspileMetricNames() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.String(), tt.want.String()) {
+				t1.Errorf("TranspileMetricNames() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
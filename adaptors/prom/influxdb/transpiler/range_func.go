@@ -0,0 +1,191 @@
+package transpiler
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"time"
+)
+
+// localRangeFns are PromQL functions over range vectors which can be evaluated locally from raw samples
+var localRangeFns = map[string]bool{
+	"rate":               true,
+	"increase":           true,
+	"deriv":              true,
+	"sum_over_time":      true,
+	"avg_over_time":      true,
+	"max_over_time":      true,
+	"min_over_time":      true,
+	"count_over_time":    true,
+	"stddev_over_time":   true,
+	"quantile_over_time": true,
+}
+
+// extrapolatedRateFns are PromQL functions evaluated locally by Prometheus's extrapolatedRate logic in models.RATE_EXTRAPOLATED mode
+var extrapolatedRateFns = map[string]bool{
+	"rate":     true,
+	"increase": true,
+}
+
+// localAggregateOps are PromQL aggregation operators which can be applied to results of locally evaluated range functions
+var localAggregateOps = map[parser.ItemType]bool{
+	parser.SUM:    true,
+	parser.AVG:    true,
+	parser.MAX:    true,
+	parser.MIN:    true,
+	parser.COUNT:  true,
+	parser.STDDEV: true,
+}
+
+// RangeFunc describes the PromQL function call over a range vector which should be evaluated locally from raw samples
+// of its MatrixSelector argument, together with the whole expression enclosing it
+type RangeFunc struct {
+	// Call is the PromQL function call, one of localRangeFns
+	Call *parser.Call
+	// Range is time range of the PromQL MatrixSelector argument
+	Range time.Duration
+	// Arg is the PromQL MatrixSelector argument
+	Arg *parser.MatrixSelector
+	// Expr is the whole PromQL expression. It is Call itself, or Call wrapped by parentheses, aggregations and arithmetic
+	// or comparison operations with numbers, which are evaluated locally from results of Call as well.
+	Expr parser.Expr
+}
+
+// RangeFunc returns the range function call to be evaluated locally, nil if there is not.
+func (t *Transpiler) RangeFunc() *RangeFunc {
+	return t.rangeFunc
+}
+
+// findRangeFunc returns RangeFunc if the range function call of expr should be evaluated locally, which callers opt in
+// by models.RATE_EXTRAPOLATED mode. By default range functions are evaluated by InfluxDB, over time buckets of the GROUP BY
+// time() clause as wide as the step for graph data range queries. In models.RATE_EXTRAPOLATED mode, rate or increase is
+// evaluated by Prometheus's extrapolatedRate logic, and other range functions of graph data range queries are evaluated
+// over windows as wide as their ranges at every step unless t.groupedByStep. Expressions containing rate or increase
+// in shapes which can't be evaluated locally get an error instead of silently approximated results.
+func (t *Transpiler) findRangeFunc(expr parser.Expr) (*RangeFunc, error) {
+	if t.RateMode != models.RATE_EXTRAPOLATED {
+		return nil, nil
+	}
+	call, ok := localRangeCall(expr)
+	if hasExtrapolatedRateCall(expr) {
+		if !ok || !extrapolatedRateFns[call.Func.Name] {
+			return nil, errors.Errorf("rate or increase of %s can't be evaluated in extrapolated mode, only a rate or increase "+
+				"function optionally wrapped by aggregations and operations with numbers is supported, use approximate rate mode "+
+				"to evaluate it by InfluxQL non_negative_derivative function instead", expr)
+		}
+		return newRangeFunc(call, expr), nil
+	}
+	if ok && t.DataType == models.GRAPH_DATA && t.isRangeQuery() && !t.groupedByStep(expr) {
+		return newRangeFunc(call, expr), nil
+	}
+	return nil, nil
+}
+
+// hasExtrapolatedRateCall checks expr contains a call of extrapolatedRateFns or not
+func hasExtrapolatedRateCall(expr parser.Expr) bool {
+	var found bool
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		if call, ok := node.(*parser.Call); ok && extrapolatedRateFns[call.Func.Name] {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// groupedByStep checks range functions of expr can be evaluated by InfluxDB over time buckets of the GROUP BY time() clause,
+// i.e. all of them are aggregations over time whose range equals the step, or the step is not set
+func (t *Transpiler) groupedByStep(expr parser.Expr) bool {
+	grouped := true
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		call, ok := node.(*parser.Call)
+		if !ok {
+			return nil
+		}
+		for _, arg := range call.Args {
+			ms, ok := arg.(*parser.MatrixSelector)
+			if !ok {
+				continue
+			}
+			if _, ok = aggregateOverTimeFns[call.Func.Name]; !ok || (t.Step > 0 && ms.Range != t.Step) {
+				grouped = false
+			}
+		}
+		return nil
+	})
+	return grouped
+}
+
+func newRangeFunc(call *parser.Call, expr parser.Expr) *RangeFunc {
+	ms := call.Args[len(call.Args)-1].(*parser.MatrixSelector)
+	return &RangeFunc{
+		Call:  call,
+		Range: ms.Range,
+		Arg:   ms,
+		Expr:  expr,
+	}
+}
+
+// localRangeCall returns the range function call of expr if expr can be evaluated locally, i.e. it is a call of
+// localRangeFns over a MatrixSelector, optionally wrapped by parentheses, aggregations of localAggregateOps and
+// arithmetic or comparison operations with numbers
+func localRangeCall(expr parser.Expr) (*parser.Call, bool) {
+	switch e := expr.(type) {
+	case *parser.ParenExpr:
+		return localRangeCall(e.Expr)
+	case *parser.Call:
+		if !localRangeFns[e.Func.Name] {
+			return nil, false
+		}
+		if _, ok := e.Args[len(e.Args)-1].(*parser.MatrixSelector); !ok {
+			return nil, false
+		}
+		for _, arg := range e.Args[:len(e.Args)-1] {
+			if !isNumberExpr(arg) {
+				return nil, false
+			}
+		}
+		return e, true
+	case *parser.AggregateExpr:
+		if !localAggregateOps[e.Op] {
+			return nil, false
+		}
+		return localRangeCall(e.Expr)
+	case *parser.BinaryExpr:
+		if !e.Op.IsComparisonOperator() && !isArithmeticOperator(e.Op) {
+			return nil, false
+		}
+		switch {
+		case isNumberExpr(e.LHS):
+			return localRangeCall(e.RHS)
+		case isNumberExpr(e.RHS):
+			return localRangeCall(e.LHS)
+		}
+	}
+	return nil, false
+}
+
+// isArithmeticOperator checks op is one of PromQL arithmetic binary operators which are evaluated locally
+func isArithmeticOperator(op parser.ItemType) bool {
+	switch op {
+	case parser.ADD, parser.SUB, parser.MUL, parser.DIV, parser.MOD, parser.POW:
+		return true
+	}
+	return false
+}
+
+// isNumberExpr checks expr is a number literal or an arithmetic expression of number literals, which
+// evaluator.Evaluator evaluates locally
+func isNumberExpr(expr parser.Expr) bool {
+	switch e := expr.(type) {
+	case *parser.NumberLiteral:
+		return true
+	case *parser.ParenExpr:
+		return isNumberExpr(e.Expr)
+	case *parser.UnaryExpr:
+		return isNumberExpr(e.Expr)
+	case *parser.BinaryExpr:
+		return isArithmeticOperator(e.Op) && isNumberExpr(e.LHS) && isNumberExpr(e.RHS)
+	}
+	return false
+}
@@ -4,16 +4,23 @@ import (
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
-	"github.com/prometheus/prometheus/model/timestamp"
 	"github.com/prometheus/prometheus/promql/parser"
-	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/sliceutils"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
-	"regexp"
 	"time"
 )
 
+const (
+	// FieldLabelName is a pseudo label for selecting one or more fields of multi-field measurements,
+	// e.g. cpu{__field__=~"usage_.*"}. Each selected field comes back as its own series carrying the label.
+	FieldLabelName = "__field__"
+)
+
 var reservedTags = map[string]struct{}{
-	"__name__": {},
+	"__name__":     {},
+	FieldLabelName: {},
 }
 
 var labelMatchOps = map[labels.MatchType]influxql.Token{
@@ -25,50 +32,104 @@ var labelMatchOps = map[labels.MatchType]influxql.Token{
 
 // findStartEndTime return start and end time.
 // End time is calculated as below priority order from highest to lowest:
-// 	- ```Timestamp``` attribute of PromQL VectorSelector v
+// 	- ```@``` modifier of PromQL VectorSelector v, ```@ start()``` and ```@ end()``` are resolved to the query's start and end time
 //  - ```End``` attribute of Transpiler t
 //  - ```Evaluation``` attribute of Transpiler t
 //  - time.Now()
-//  yielded result from above calculation will be calculated with v's OriginalOffset attribute at last.
+//  yielded result from above calculation will be shifted by v's OriginalOffset attribute at last.
 //
 // Start time is calculated as below priority order from highest to lowest:
-//  - ```Start``` attribute of Transpiler t
+//  - ```Start``` attribute of Transpiler t shifted by v's OriginalOffset attribute if it is a range query without ```@``` modifier,
+//    and one more GROUP BY time() interval earlier for graph data queries, or one more time range of PromQL MatrixSelector earlier
+//    for locally evaluated rate or increase
 //  - End time subtracts time range of PromQL MatrixSelector
+//  - End time subtracts ```LookbackDelta``` attribute of Transpiler t if it is set
+//
+// As Prometheus does, offset shifts the window of every step of range queries, and ```@``` modifier pins every step
+// to a single timestamp. Both are recorded in t's StepModifier for re-stamping the results onto the requested step grid.
+// v itself is left untouched.
 func (t *Transpiler) findStartEndTime(v *parser.VectorSelector) (start, end *time.Time) {
 	now := time.Now()
-	end = &now
+	evalEnd := &now
 	if t.Evaluation != nil {
-		end = t.Evaluation
+		evalEnd = t.Evaluation
 	}
 	if t.End != nil {
-		end = t.End
+		evalEnd = t.End
 	}
-	if t.Start != nil {
-		start = t.Start
+	var evalStart *time.Time
+	if t.isRangeQuery() {
+		evalStart = t.Start
 	}
-	if start != nil && v.StartOrEnd == parser.START {
-		v.Timestamp = makeInt64Pointer(timestamp.FromTime(*start))
+	var at *time.Time
+	switch {
+	case v.Timestamp != nil:
+		ts := time.UnixMilli(*v.Timestamp)
+		at = &ts
+	case v.StartOrEnd == parser.START:
+		// start() equals to end() for instant queries
+		at = evalEnd
+		if evalStart != nil {
+			at = evalStart
+		}
+	case v.StartOrEnd == parser.END:
+		at = evalEnd
 	}
-	if end != nil && v.StartOrEnd == parser.END {
-		v.Timestamp = makeInt64Pointer(timestamp.FromTime(*end))
+	if at != nil {
+		evalStart, evalEnd = nil, at
 	}
-	if v.Timestamp != nil {
-		ts := time.UnixMilli(*v.Timestamp)
-		end = &ts
+	t.stepModifier = StepModifier{
+		Offset: v.OriginalOffset,
+		At:     at,
 	}
-	endTs := end.Add(-v.OriginalOffset)
+	endTs := evalEnd.Add(-v.OriginalOffset)
 	end = &endTs
-	if t.timeRange > 0 && t.Start == nil {
+	if evalStart != nil {
+		startTs := evalStart.Add(-v.OriginalOffset)
+		// The first step's window of locally evaluated range functions begins one time range before start,
+		// and the first step's time bucket of graph data queries begins one interval before start
+		switch {
+		case t.rangeFunc != nil:
+			startTs = startTs.Add(-t.rangeFunc.Range)
+		case t.DataType == models.GRAPH_DATA:
+			startTs = startTs.Add(-t.groupByInterval())
+		}
+		start = &startTs
+	}
+	if t.timeRange > 0 && start == nil {
 		startTs := end.Add(-t.timeRange)
 		start = &startTs
 	}
+	if t.LookbackDelta > 0 && start == nil {
+		startTs := end.Add(-t.LookbackDelta)
+		start = &startTs
+	}
 	return
 }
 
+// absentLabelAlternative returns expression like (env =~ /^(?:prod|)$/ OR env = '') for selecting series
+// either matching regexCond or not having the label at all
+func absentLabelAlternative(labelName string, regexCond *influxql.BinaryExpr) influxql.Expr {
+	return &influxql.ParenExpr{
+		Expr: &influxql.BinaryExpr{
+			Op:  influxql.OR,
+			LHS: regexCond,
+			RHS: &influxql.BinaryExpr{
+				Op: influxql.EQ,
+				LHS: &influxql.VarRef{
+					Val: labelName,
+				},
+				RHS: &influxql.StringLiteral{},
+			},
+		},
+	}
+}
+
 // transpileVectorSelector2ConditionExpr transpiles PromQL VectorSelector to time condition and tag condition separately.
 // The time condition will be applied at the most outer expression for improving performance.
 // Refer to https://docs.influxdata.com/influxdb/v1.8/query_language/explore-data/#improve-performance-of-time-bound-subqueries
-func (t *Transpiler) transpileVectorSelector2ConditionExpr(v *parser.VectorSelector) (timeCondition influxql.Expr, tagCondition influxql.Expr, err error) {
+// Labels consumed by schema.Source are skipped, and schema.Source's extra Condition is prepended to the tag condition.
+func (t *Transpiler) transpileVectorSelector2ConditionExpr(v *parser.VectorSelector, source schema.Source) (timeCondition influxql.Expr, tagCondition influxql.Expr, err error) {
 	start, end := t.findStartEndTime(v)
 
 	timeBinExpr := &influxql.BinaryExpr{
@@ -96,69 +157,152 @@ func (t *Transpiler) transpileVectorSelector2ConditionExpr(v *parser.VectorSelec
 		timeCondition = timeBinExpr
 	}
 
-	var tagCond *Condition
+	tagCondition = source.Condition
 	for _, item := range v.LabelMatchers {
 		if _, ok := reservedTags[item.Name]; ok {
 			continue
 		}
-		if stringutils.IsEmpty(item.Value) {
+		if sliceutils.StringContains(source.ConsumedLabels, item.Name) {
 			continue
 		}
-		var cond *influxql.BinaryExpr
+		// Label names escaped by escaping.EscapeLabelName in query results are converted back to InfluxDB tag keys
+		tagKey := escaping.UnescapeName(item.Name)
+		if err = checkQueryText(tagKey); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid label name")
+		}
+		if item.Type == labels.MatchEqual || item.Type == labels.MatchNotEqual {
+			if err = checkQueryText(item.Value); err != nil {
+				return nil, nil, errors.Wrap(err, "invalid label value")
+			}
+		}
+		var cond influxql.Expr
 		switch item.Type {
 		case labels.MatchEqual:
+			// In PromQL, {env=""} selects series without env label.
+			// InfluxDB treats absent tag as empty string, so env = '' is the equivalent.
 			cond = &influxql.BinaryExpr{
 				Op: influxql.EQ,
 				LHS: &influxql.VarRef{
-					Val: item.Name,
+					Val: tagKey,
 				},
 				RHS: &influxql.StringLiteral{
 					Val: item.Value,
 				},
 			}
 		case labels.MatchNotEqual:
+			// In PromQL, {env!=""} selects series with env label, the equivalent is env != ''
 			cond = &influxql.BinaryExpr{
 				Op: influxql.NEQ,
 				LHS: &influxql.VarRef{
-					Val: item.Name,
+					Val: tagKey,
 				},
 				RHS: &influxql.StringLiteral{
 					Val: item.Value,
 				},
 			}
 		case labels.MatchRegexp, labels.MatchNotRegexp:
-			promRegexStr := "^(?:" + item.Value + ")$"
-			re, err := regexp.Compile(promRegexStr)
+			re, err := newRegexLiteral(item.Value)
 			if err != nil {
-				return nil, nil, errors.Wrap(err, "regular expression syntax error")
+				return nil, nil, err
 			}
-			cond = &influxql.BinaryExpr{
+			regexCond := &influxql.BinaryExpr{
 				Op: influxql.EQREGEX,
 				LHS: &influxql.VarRef{
-					Val: item.Name,
-				},
-				RHS: &influxql.RegexLiteral{
-					Val: re,
+					Val: tagKey,
 				},
+				RHS: re,
 			}
 			if item.Type == labels.MatchNotRegexp {
-				cond.Op = influxql.NEQREGEX
+				regexCond.Op = influxql.NEQREGEX
+			}
+			cond = regexCond
+			// If the matcher matches empty string, series without the label should be selected as well.
+			if item.Matches("") {
+				cond = absentLabelAlternative(tagKey, regexCond)
 			}
 		default:
 			return nil, nil, errors.Errorf("not support PromQL match type %s", item.Type)
 		}
-		if tagCond != nil {
-			tagCond = tagCond.And(cond)
+		if tagCondition != nil {
+			tagCondition = &influxql.BinaryExpr{
+				Op:  influxql.AND,
+				LHS: tagCondition,
+				RHS: cond,
+			}
 		} else {
-			tagCond = (*Condition)(cond)
+			tagCondition = cond
+		}
+	}
+
+	return
+}
+
+// vectorSelectorMetricName returns metric name of v, which is set either before the braces or by __name__ equality matcher.
+// Selectors without metric name or selecting metric names by regex return empty string, as they can't be mapped to a measurement.
+func vectorSelectorMetricName(v *parser.VectorSelector) string {
+	if v.Name != "" {
+		return v.Name
+	}
+	for _, item := range v.LabelMatchers {
+		if item.Name == labels.MetricName && item.Type == labels.MatchEqual {
+			return item.Value
 		}
 	}
+	return ""
+}
 
-	if tagCond != nil {
-		tagCondition = (*influxql.BinaryExpr)(tagCond)
+// SelectorSource returns schema.Source of VectorSelector v mapped by the schema mapper. An equality FieldLabelName matcher
+// overrides the field key of the source, the other FieldLabelName matchers are left to the caller to be resolved to field keys,
+// so are the matchers of schema.Source's FieldLabel without equality matcher. If the source has an Alternative, it is chosen
+// by field keys of the measurement, see storingSource.
+func (t *Transpiler) SelectorSource(v *parser.VectorSelector) (schema.Source, error) {
+	metricName := vectorSelectorMetricName(v)
+	if metricName == "" {
+		return schema.Source{}, errors.Errorf("vector selector %s must select a single metric name", v)
+	}
+	// Metric names escaped by escaping.EscapeMetricName in query results are converted back to InfluxDB measurement names
+	// before being mapped to the source measurement and field
+	source, err := t.schemaMapper().Source(escaping.UnescapeName(metricName), v.LabelMatchers, t.ValueFieldKey)
+	if err != nil {
+		return schema.Source{}, err
+	}
+	for _, item := range v.LabelMatchers {
+		if item.Name == FieldLabelName && item.Type == labels.MatchEqual {
+			source.FieldKey, source.FieldLabel, source.Alternative = item.Value, "", nil
+		}
 	}
+	return t.storingSource(v, source)
+}
 
-	return
+// storingSource returns the first one of source and its Alternatives which stores samples of v according to t.FieldKeys.
+// If none does, the source of an existing measurement is an error rather than a query of nonexistent field, while v selects
+// nothing if no measurement exists.
+func (t *Transpiler) storingSource(v *parser.VectorSelector, source schema.Source) (schema.Source, error) {
+	if source.Alternative == nil || t.FieldKeys == nil {
+		return source, nil
+	}
+	first := source
+	var existing *schema.Source
+	for {
+		fieldKeys, err := t.FieldKeys(source.Measurement)
+		if err != nil {
+			return schema.Source{}, errors.Wrap(err, "fail to find field keys")
+		}
+		if source.Stores(fieldKeys) {
+			return source, nil
+		}
+		if len(fieldKeys) > 0 && existing == nil {
+			existing = &source
+		}
+		if source.Alternative == nil {
+			break
+		}
+		source = *source.Alternative
+	}
+	if existing != nil {
+		return schema.Source{}, errors.Errorf("%s is not stored in measurement %s", v, existing.Measurement)
+	}
+	return first, nil
 }
 
 // transpileInstantVectorSelector transpiles PromQL VectorSelector to InfluxQL statement
@@ -167,22 +311,37 @@ func (t *Transpiler) transpileInstantVectorSelector(v *parser.VectorSelector) (i
 		err          error
 		tagCondition influxql.Expr
 	)
-	t.timeCondition, tagCondition, err = t.transpileVectorSelector2ConditionExpr(v)
+	source, err := t.SelectorSource(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "transpile instant vector selector fail")
+	}
+	if err = checkQueryText(source.Measurement); err != nil {
+		return nil, errors.Wrap(err, "invalid metric name")
+	}
+	if source.FieldKey == "" {
+		return nil, errors.Errorf("%s must select a single field by an equality matcher on label %s", v, source.FieldLabel)
+	}
+	if err = checkQueryText(source.FieldKey); err != nil {
+		return nil, errors.Wrap(err, "invalid field key")
+	}
+	t.timeCondition, tagCondition, err = t.transpileVectorSelector2ConditionExpr(v, source)
+	if err != nil {
+		return nil, errors.Wrap(err, "transpile instant vector selector fail")
+	}
+	t.fieldKey, t.fieldLabel = source.FieldKey, source.FieldLabel
+	measurement := source.Measurement
 	switch t.DataType {
 	case models.LABEL_VALUES_DATA:
-		showTagValuesStatement := influxql.ShowTagValuesStatement{
-			Database:   t.Database,
-			Sources:    []influxql.Source{&influxql.Measurement{Name: v.Name}},
-			Op:         influxql.EQ,
-			TagKeyExpr: &influxql.StringLiteral{Val: t.LabelName},
-			Condition:  tagCondition,
+		return t.labelValuesStatement(&influxql.Measurement{Name: measurement}, source.FieldKey, tagCondition), nil
+	case models.LABEL_NAMES_DATA:
+		showTagKeysStatement := influxql.ShowTagKeysStatement{
+			Database:  t.Database,
+			Sources:   []influxql.Source{&influxql.Measurement{Name: measurement}},
+			Condition: tagCondition,
 		}
-		return &showTagValuesStatement, nil
+		return &showTagKeysStatement, nil
 	default:
 
-	}
-	if err != nil {
-		return nil, errors.Wrap(err, "transpile instant vector selector fail")
 	}
 	selectStatement := influxql.SelectStatement{
 		Fields: []*influxql.Field{
@@ -193,14 +352,11 @@ func (t *Transpiler) transpileInstantVectorSelector(v *parser.VectorSelector) (i
 			},
 		},
 		Condition:  tagCondition,
-		Sources:    []influxql.Source{&influxql.Measurement{Name: v.Name}},
+		Sources:    []influxql.Source{&influxql.Measurement{Name: measurement}},
 		Dimensions: []*influxql.Dimension{{Expr: &influxql.Wildcard{}}},
 	}
-	valueFieldKey := defaultValueFieldKey
-	if stringutils.IsNotEmpty(t.ValueFieldKey) {
-		valueFieldKey = t.ValueFieldKey
-	}
-	if t.timeRange > 0 || (t.Start != nil && (t.DataType == models.TABLE_DATA || t.DataType == 0)) {
+	valueFieldKey := source.FieldKey
+	if t.timeRange > 0 || (t.isRangeQuery() && !t.stepModifier.Pinned() && (t.DataType == models.TABLE_DATA || t.DataType == 0)) {
 		selectStatement.Fields = append(selectStatement.Fields, &influxql.Field{
 			Expr: &influxql.VarRef{
 				Val: valueFieldKey,
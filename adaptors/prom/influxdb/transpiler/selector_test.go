@@ -3,6 +3,7 @@ package transpiler
 import (
 	"github.com/influxdata/influxql"
 	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/testinghelper"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
 	"reflect"
@@ -16,7 +17,8 @@ func TestTranspiler_TranspileVectorSelector2ConditionExpr(t1 *testing.T) {
 		End   *time.Time
 	}
 	type args struct {
-		v *parser.VectorSelector
+		v      *parser.VectorSelector
+		source schema.Source
 	}
 	tests := []struct {
 		name    string
@@ -49,6 +51,108 @@ func TestTranspiler_TranspileVectorSelector2ConditionExpr(t1 *testing.T) {
 			want:    influxql.MustParseExpr("host =~ /^(?:tele.*)$/ AND cpu = 'cpu0'"),
 			wantErr: false,
 		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`cpu{__field__=~"usage_.*", host="telegraf"}`),
+			},
+			want:    influxql.MustParseExpr("host = 'telegraf'"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`cpu{env=""}`),
+			},
+			want:    influxql.MustParseExpr("env = ''"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`cpu{env!=""}`),
+			},
+			want:    influxql.MustParseExpr("env != ''"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`cpu{host=~"tele.*", env=~"prod|"}`),
+			},
+			want:    influxql.MustParseExpr("host =~ /^(?:tele.*)$/ AND (env =~ /^(?:prod|)$/ OR env = '')"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`cpu{env!~"prod"}`),
+			},
+			want:    influxql.MustParseExpr("(env !~ /^(?:prod)$/ OR env = '')"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`cpu{env!~"prod|"}`),
+			},
+			want:    influxql.MustParseExpr("env !~ /^(?:prod|)$/"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`cpu{env=~".*", host="telegraf"}`),
+			},
+			want:    influxql.MustParseExpr("(env =~ /^(?:.*)$/ OR env = '') AND host = 'telegraf'"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start: nil,
+				End:   &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`http_request_duration_seconds_bucket{le="0.5", host="telegraf"}`),
+				source: schema.Source{
+					Measurement:    "http_request_duration_seconds",
+					FieldKey:       "0.5",
+					Condition:      influxql.MustParseExpr("job = 'api'"),
+					ConsumedLabels: []string{"le"},
+				},
+			},
+			want:    influxql.MustParseExpr("job = 'api' AND host = 'telegraf'"),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
@@ -58,7 +162,7 @@ func TestTranspiler_TranspileVectorSelector2ConditionExpr(t1 *testing.T) {
 					End:   tt.fields.End,
 				},
 			}
-			_, got, err := t.transpileVectorSelector2ConditionExpr(tt.args.v)
+			_, got, err := t.transpileVectorSelector2ConditionExpr(tt.args.v, tt.args.source)
 			if (err != nil) != tt.wantErr {
 				t1.Errorf("transpileVectorSelector2ConditionExpr() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -72,13 +176,14 @@ func TestTranspiler_TranspileVectorSelector2ConditionExpr(t1 *testing.T) {
 
 func TestTranspiler_transpileInstantVectorSelector(t1 *testing.T) {
 	type fields struct {
-		Start      *time.Time
-		End        *time.Time
-		Timezone   *time.Location
-		Evaluation *time.Time
-		DataType   models.DataType
-		Database   string
-		LabelName  string
+		Start        *time.Time
+		End          *time.Time
+		Timezone     *time.Location
+		Evaluation   *time.Time
+		DataType     models.DataType
+		Database     string
+		LabelName    string
+		SchemaMapper schema.Mapper
 	}
 	type args struct {
 		v *parser.VectorSelector
@@ -101,6 +206,30 @@ func TestTranspiler_transpileInstantVectorSelector(t1 *testing.T) {
 			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM cpu WHERE host =~ /^(?:tele.*)$/ GROUP BY *`),
 			wantErr: false,
 		},
+		{
+			name: "",
+			fields: fields{
+				End: &endTime,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`U__cpu_2e_usage{U__http_2d_method="GET"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM "cpu.usage" WHERE "http-method" = 'GET' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime,
+				DataType:   models.LABEL_VALUES_DATA,
+				LabelName:  "U__http_2d_method",
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`U__cpu_2e_usage`),
+			},
+			want:    influxql.MustParseStatement(`SHOW TAG VALUES FROM "cpu.usage" WITH KEY = "http-method"`),
+			wantErr: false,
+		},
 		{
 			name: "",
 			fields: fields{
@@ -151,6 +280,90 @@ func TestTranspiler_transpileInstantVectorSelector(t1 *testing.T) {
 			want:    influxql.MustParseStatement(`SHOW TAG VALUES FROM go_goroutines WITH KEY = "" WHERE instance =~ /^(?:192.168.*)$/`),
 			wantErr: false,
 		},
+		{
+			name: "",
+			fields: fields{
+				End:          &endTime,
+				SchemaMapper: schema.TelegrafV2Mapper{},
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`go_goroutines{instance="localhost:9090"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(go_goroutines) FROM prometheus WHERE instance = 'localhost:9090' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				End:          &endTime,
+				SchemaMapper: schema.TelegrafV1Mapper{},
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`http_request_duration_seconds_bucket{le="0.5", handler="/api"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last("0.5") FROM http_request_duration_seconds WHERE handler = '/api' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				End:          &endTime,
+				SchemaMapper: schema.TelegrafV1Mapper{},
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`http_requests_total{handler="/api"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(counter) FROM http_requests_total WHERE handler = '/api' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				End:          &endTime,
+				SchemaMapper: schema.TelegrafV1Mapper{},
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`http_request_duration_seconds_bucket{le=~"0.5|1"}`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "",
+			fields: fields{
+				End:      &endTime,
+				DataType: models.SERIES_DATA,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`{__name__="up", job="prometheus"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM up WHERE job = 'prometheus' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				End:      &endTime,
+				DataType: models.SERIES_DATA,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`{job="prometheus"}`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "",
+			fields: fields{
+				End:      &endTime,
+				DataType: models.LABEL_NAMES_DATA,
+			},
+			args: args{
+				v: testinghelper.VectorSelector(`{__name__=~"http_.*"}`),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
@@ -164,12 +377,16 @@ func TestTranspiler_transpileInstantVectorSelector(t1 *testing.T) {
 					Database:   tt.fields.Database,
 					LabelName:  tt.fields.LabelName,
 				},
+				SchemaMapper: tt.fields.SchemaMapper,
 			}
 			got, err := t.transpileInstantVectorSelector(tt.args.v)
 			if (err != nil) != tt.wantErr {
 				t1.Errorf("transpileInstantVectorSelector() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr {
+				return
+			}
 			if !reflect.DeepEqual(got.String(), tt.want.String()) {
 				t1.Errorf("transpileInstantVectorSelector() got = %v, want %v", got, tt.want)
 			}
@@ -4,23 +4,45 @@ import (
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
 	"reflect"
 	"time"
 )
 
-const (
-	defaultValueFieldKey = "value"
-)
-
 // Transpiler is responsible for transpiling a single PromQL expression to InfluxQL expression.
 // It will be gc-ed after its work done.
 type Transpiler struct {
 	models.PromCommand
+	// SchemaMapper maps PromQL metrics to InfluxDB measurements and fields, default is schema.PromWriteMapper
+	SchemaMapper schema.Mapper
+	// FieldKeys returns field keys of an InfluxDB measurement to choose between schema.Source and its Alternative.
+	// If it is nil, the Alternative is never chosen.
+	FieldKeys      func(measurement string) ([]string, error)
 	timeRange      time.Duration
 	parenExprCount int
 	timeCondition  influxql.Expr
 	tagDropped     bool
+	fieldKey       string
+	fieldLabel     string
+	stepModifier   StepModifier
+	rangeFunc      *RangeFunc
+}
+
+// StepModifier describes how results of range queries map onto the requested step grid
+type StepModifier struct {
+	// Offset shifts the window of every step back in time, negative offset shifts it forward
+	Offset time.Duration
+	// At pins every step to a single timestamp
+	At *time.Time
+	// Interval is the width of GROUP BY time() buckets. InfluxDB stamps each bucket with its start time,
+	// while Prometheus stamps each step with the end time of its window.
+	Interval time.Duration
+}
+
+// Pinned checks whether every step is pinned to a single timestamp by @ modifier or not
+func (receiver StepModifier) Pinned() bool {
+	return receiver.At != nil
 }
 
 // Transpile converts a PromQL expression with the time ranges set in the transpiler
@@ -39,6 +61,76 @@ func (t *Transpiler) Transpile(expr parser.Expr) (influxql.Node, error) {
 	return influxNode, nil
 }
 
+// isRangeQuery checks the PromQL expression is evaluated as a range query or an instant query
+func (t *Transpiler) isRangeQuery() bool {
+	return t.QueryType == models.RANGE_QUERY
+}
+
+// FieldKey returns InfluxDB field key of the last transpiled PromQL VectorSelector
+func (t *Transpiler) FieldKey() string {
+	return t.fieldKey
+}
+
+// FieldLabel returns schema.Source's FieldLabel of the last transpiled PromQL VectorSelector
+func (t *Transpiler) FieldLabel() string {
+	return t.fieldLabel
+}
+
+// StepModifier returns StepModifier of the last transpiled PromQL VectorSelector
+func (t *Transpiler) StepModifier() StepModifier {
+	return t.stepModifier
+}
+
+// groupByInterval returns interval of GROUP BY time() clause for graph data queries, which is the query step.
+// Range vectors of graph data range queries are evaluated locally, so the time range of PromQL MatrixSelector
+// is only used if the step is not set.
+func (t *Transpiler) groupByInterval() time.Duration {
+	if t.Step <= 0 && t.timeRange > 0 {
+		return t.timeRange
+	}
+	return t.Step
+}
+
+// groupByOffset returns offset of GROUP BY time() clause which makes time buckets end at start + k*step
+// of range queries, taking offset modifier into account. InfluxDB aligns time buckets in wall clock time of
+// the tz() clause's timezone, so the zone offset of Timezone at start is added as well.
+func (t *Transpiler) groupByOffset(interval time.Duration) time.Duration {
+	if interval <= 0 || !t.isRangeQuery() || t.Start == nil {
+		return 0
+	}
+	origin := t.Start.Add(-t.stepModifier.Offset)
+	var zoneOffset int
+	if t.Timezone != nil {
+		_, zoneOffset = origin.In(t.Timezone).Zone()
+	}
+	offset := time.Duration((origin.UnixNano() + int64(zoneOffset)*int64(time.Second)) % int64(interval))
+	if offset < 0 {
+		offset += interval
+	}
+	return offset
+}
+
+// influxFillOption converts models.FillType to influxql.FillOption, default is influxql.NoFill
+func influxFillOption(fill models.FillType) influxql.FillOption {
+	switch fill {
+	case models.FILL_NULL:
+		return influxql.NullFill
+	case models.FILL_PREVIOUS:
+		return influxql.PreviousFill
+	case models.FILL_LINEAR:
+		return influxql.LinearFill
+	default:
+		return influxql.NoFill
+	}
+}
+
+func (t *Transpiler) schemaMapper() schema.Mapper {
+	if t.SchemaMapper != nil {
+		return t.SchemaMapper
+	}
+	return schema.PromWriteMapper{}
+}
+
 func handleNodeNotSupported(expr parser.Expr) error {
 	return errors.Errorf("PromQL node type %T is not supported yet", expr)
 }
@@ -46,7 +138,7 @@ func handleNodeNotSupported(expr parser.Expr) error {
 // setTimeCondition sets time range and timezone condition in InfluxQL WHERE clause
 func (t *Transpiler) setTimeCondition(node influxql.Statement) {
 	switch statement := node.(type) {
-	case *influxql.SelectStatement, *influxql.ShowTagValuesStatement:
+	case *influxql.SelectStatement, *influxql.ShowTagValuesStatement, *influxql.ShowTagKeysStatement:
 		conditionValue := reflect.ValueOf(statement).Elem().FieldByName("Condition")
 		if conditionValue.IsValid() {
 			if !conditionValue.IsNil() {
@@ -67,12 +159,24 @@ func (t *Transpiler) setTimeCondition(node influxql.Statement) {
 }
 
 func (t *Transpiler) transpile(expr parser.Expr) (influxql.Node, error) {
-	if t.Start != nil {
+	if t.isRangeQuery() {
 		if expr.Type() != parser.ValueTypeVector && expr.Type() != parser.ValueTypeScalar {
 			return nil, errors.Errorf("invalid expression type %q for range query, must be Scalar or instant Vector", parser.DocumentedType(expr.Type()))
 		}
 	}
-	node, err := t.transpileExpr(expr)
+	var (
+		node influxql.Node
+		err  error
+	)
+	if t.rangeFunc, err = t.findRangeFunc(expr); err != nil {
+		return nil, errors.Errorf("error transpiling expression: %s", err)
+	}
+	if t.rangeFunc != nil {
+		// Only select raw samples, the range function and its enclosing expression are evaluated from them locally
+		node, err = t.transpileExpr(t.rangeFunc.Arg)
+	} else {
+		node, err = t.transpileExpr(expr)
+	}
 	if err != nil {
 		return nil, errors.Errorf("error transpiling expression: %s", err)
 	}
@@ -80,21 +184,25 @@ func (t *Transpiler) transpile(expr parser.Expr) (influxql.Node, error) {
 	case influxql.Statement:
 		switch statement := n.(type) {
 		case *influxql.SelectStatement:
-			if t.DataType == models.GRAPH_DATA {
-				var timeRange time.Duration
-				if t.timeRange > 0 {
-					timeRange = t.timeRange
-				} else {
-					timeRange = t.Step
+			// Steps pinned by @ modifier share the same single value, and raw samples of locally evaluated range functions
+			// are not grouped either, so no time grouping needed
+			if t.DataType == models.GRAPH_DATA && !t.stepModifier.Pinned() && t.rangeFunc == nil {
+				interval := t.groupByInterval()
+				args := []influxql.Expr{
+					&influxql.DurationLiteral{Val: interval},
+				}
+				// Align time buckets to the start of the query instead of the epoch
+				if offset := t.groupByOffset(interval); offset > 0 {
+					args = append(args, &influxql.DurationLiteral{Val: offset})
 				}
 				statement.Dimensions = append(statement.Dimensions, &influxql.Dimension{
 					Expr: &influxql.Call{
 						Name: "time",
-						Args: []influxql.Expr{
-							&influxql.DurationLiteral{Val: timeRange},
-						},
+						Args: args,
 					},
 				})
+				statement.Fill = influxFillOption(t.Fill)
+				t.stepModifier.Interval = interval
 			}
 		}
 		t.setTimeCondition(n)
@@ -159,11 +267,3 @@ func (receiver *Condition) And(expr *influxql.BinaryExpr) *Condition {
 		RHS: expr,
 	}
 }
-
-func (receiver *Condition) Or(expr *influxql.BinaryExpr) *Condition {
-	return &Condition{
-		Op:  influxql.OR,
-		LHS: (*influxql.BinaryExpr)(receiver),
-		RHS: expr,
-	}
-}
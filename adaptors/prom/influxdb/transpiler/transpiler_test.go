@@ -3,6 +3,7 @@ package transpiler
 import (
 	"github.com/influxdata/influxql"
 	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/testinghelper"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
 	"reflect"
@@ -10,8 +11,8 @@ import (
 	"time"
 )
 
-var endTime, endTime2, startTime2 time.Time
-var timezone *time.Location
+var endTime, endTime2, startTime2, startTime3, dayStart, dayEnd time.Time
+var timezone, newYork *time.Location
 
 func TestMain(m *testing.M) {
 	timezone, _ = time.LoadLocation("Asia/Shanghai")
@@ -19,6 +20,10 @@ func TestMain(m *testing.M) {
 	endTime = time.Date(2023, 1, 8, 10, 0, 0, 0, time.Local)
 	endTime2 = time.Date(2023, 1, 6, 15, 0, 0, 0, time.Local)
 	startTime2 = time.Date(2023, 1, 6, 12, 0, 0, 0, time.Local)
+	startTime3 = time.Date(2023, 1, 6, 12, 0, 30, 0, time.Local)
+	dayStart = time.Date(2023, 1, 6, 0, 0, 0, 0, time.Local)
+	dayEnd = time.Date(2023, 1, 8, 0, 0, 0, 0, time.Local)
+	newYork, _ = time.LoadLocation("America/New_York")
 	m.Run()
 }
 
@@ -36,17 +41,23 @@ func numberLiteralExpr(input string) *parser.NumberLiteral {
 
 func TestTranspiler_transpile(t1 *testing.T) {
 	type fields struct {
-		Start          *time.Time
-		End            *time.Time
-		Timezone       *time.Location
-		Evaluation     *time.Time
-		Step           time.Duration
-		DataType       models.DataType
-		timeRange      time.Duration
-		parenExprCount int
-		condition      influxql.Expr
-		Database       string
-		LabelName      string
+		Start           *time.Time
+		End             *time.Time
+		Timezone        *time.Location
+		Evaluation      *time.Time
+		QueryType       models.QueryType
+		LookbackDelta   time.Duration
+		Step            time.Duration
+		Fill            models.FillType
+		RateMode        models.RateMode
+		DataType        models.DataType
+		timeRange       time.Duration
+		parenExprCount  int
+		condition       influxql.Expr
+		Database        string
+		LabelName       string
+		LabelValuesMode models.LabelValuesMode
+		SchemaMapper    schema.Mapper
 	}
 	type args struct {
 		expr parser.Expr
@@ -69,6 +80,18 @@ func TestTranspiler_transpile(t1 *testing.T) {
 			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM cpu WHERE time <='2023-01-08T02:00:00Z' AND host =~ /^(?:tele.*)$/ GROUP BY *`),
 			wantErr: false,
 		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation:    &endTime,
+				LookbackDelta: 5 * time.Minute,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`cpu{host=~"tele.*"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM cpu WHERE time <= '2023-01-08T02:00:00Z' AND time >= '2023-01-08T01:55:00Z' AND host =~ /^(?:tele.*)$/ GROUP BY *`),
+			wantErr: false,
+		},
 		{
 			name: "",
 			fields: fields{
@@ -105,8 +128,9 @@ func TestTranspiler_transpile(t1 *testing.T) {
 		{
 			name: `invalid expression type "range vector" for range query, must be Scalar or instant Vector`,
 			fields: fields{
-				Start: &startTime2,
-				End:   &endTime2,
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
 			},
 			args: args{
 				expr: testinghelper.MatrixSelector(`cpu{host=~"tele.*"}[5m]`),
@@ -114,6 +138,19 @@ func TestTranspiler_transpile(t1 *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &endTime2,
+				End:       &endTime2,
+				QueryType: models.INSTANT_QUERY,
+			},
+			args: args{
+				expr: testinghelper.MatrixSelector(`cpu{host=~"tele.*"}[5m]`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, value FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T06:55:00Z' AND host =~ /^(?:tele.*)$/ GROUP BY *`),
+			wantErr: false,
+		},
 		{
 			name: "",
 			fields: fields{
@@ -284,12 +321,171 @@ func TestTranspiler_transpile(t1 *testing.T) {
 			fields: fields{
 				Evaluation: &endTime2,
 				Start:      &startTime2,
+				QueryType:  models.RANGE_QUERY,
 				DataType:   models.GRAPH_DATA,
 			},
 			args: args{
 				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m])`),
 			},
-			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' GROUP BY *, time(5m)`),
+			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:55:00Z' GROUP BY *, time(5m) fill(none)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				Start:      &startTime3,
+				QueryType:  models.RANGE_QUERY,
+				DataType:   models.GRAPH_DATA,
+				Step:       time.Minute,
+				Fill:       models.FILL_LINEAR,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`go_goroutines`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM go_goroutines WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:30Z' GROUP BY *, time(1m, 30s) fill(linear)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.SERIES_DATA,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`cpu{host=~"tele.*"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' AND host =~ /^(?:tele.*)$/ GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.LABEL_NAMES_DATA,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`cpu{host=~"tele.*"}`),
+			},
+			want:    influxql.MustParseStatement(`SHOW TAG KEYS FROM cpu WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' AND host =~ /^(?:tele.*)$/`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.SERIES_DATA,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`cpu`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM cpu WHERE time <= '2023-01-06T07:00:00Z' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &dayStart,
+				End:       &dayEnd,
+				Timezone:  timezone,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.GRAPH_DATA,
+				Step:      24 * time.Hour,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`go_goroutines`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM go_goroutines WHERE time <= '2023-01-07T16:00:00Z' AND time >= '2023-01-04T16:00:00Z' GROUP BY *, time(1d) fill(none) TZ('Asia/Shanghai')`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &dayStart,
+				End:       &dayEnd,
+				Timezone:  newYork,
+				QueryType: models.RANGE_QUERY,
+				DataType:  models.GRAPH_DATA,
+				Step:      24 * time.Hour,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`go_goroutines`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM go_goroutines WHERE time <= '2023-01-07T16:00:00Z' AND time >= '2023-01-04T16:00:00Z' GROUP BY *, time(1d, 11h) fill(none) TZ('America/New_York')`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				Start:      &startTime2,
+				QueryType:  models.RANGE_QUERY,
+				DataType:   models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m] offset 1h)`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T06:00:00Z' AND time >= '2023-01-06T02:55:00Z' GROUP BY *, time(5m) fill(none)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				Start:      &startTime2,
+				QueryType:  models.RANGE_QUERY,
+				DataType:   models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m] offset -1h)`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T08:00:00Z' AND time >= '2023-01-06T04:55:00Z' GROUP BY *, time(5m) fill(none)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				Start:      &startTime2,
+				QueryType:  models.RANGE_QUERY,
+				DataType:   models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m] @ start())`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T04:00:00Z' AND time >= '2023-01-06T03:55:00Z' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				Start:      &startTime2,
+				QueryType:  models.RANGE_QUERY,
+				DataType:   models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m] @ 1672977600 offset 10m)`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T03:50:00Z' AND time >= '2023-01-06T03:45:00Z' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				Start:      &startTime2,
+				QueryType:  models.RANGE_QUERY,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`go_goroutines @ end()`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, last(value) FROM go_goroutines WHERE time <= '2023-01-06T07:00:00Z' GROUP BY *`),
 			wantErr: false,
 		},
 		{
@@ -328,9 +524,10 @@ func TestTranspiler_transpile(t1 *testing.T) {
 		{
 			name: "",
 			fields: fields{
-				Start:    &startTime2,
-				End:      &endTime2,
-				Timezone: timezone,
+				Start:     &startTime2,
+				End:       &endTime2,
+				Timezone:  timezone,
+				QueryType: models.RANGE_QUERY,
 			},
 			args: args{
 				expr: testinghelper.VectorSelector(`cpu{host="telegraf"}`),
@@ -366,20 +563,275 @@ func TestTranspiler_transpile(t1 *testing.T) {
 			want:    influxql.MustParseStatement(`SHOW TAG VALUES ON prometheus FROM go_goroutines WITH KEY = job WHERE time <= '2023-01-08T02:00:00Z' AND instance =~ /^(?:192.168.*)$/`),
 			wantErr: false,
 		},
+		{
+			name: "",
+			fields: fields{
+				Start:           &startTime2,
+				End:             &endTime2,
+				QueryType:       models.RANGE_QUERY,
+				DataType:        models.LABEL_VALUES_DATA,
+				Database:        "prometheus",
+				LabelName:       "job",
+				LabelValuesMode: models.LABEL_VALUES_ACCURATE,
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`go_goroutines{instance=~"192.168.*"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT last(value) FROM go_goroutines WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' AND instance =~ /^(?:192.168.*)$/ GROUP BY job`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				RateMode:   models.RATE_EXTRAPOLATED,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`rate(go_gc_duration_seconds_count[5m])`),
+			},
+			want:    influxql.MustParseStatement("SELECT *::tag, value FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T06:55:00Z' GROUP BY *"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				Timezone:  timezone,
+				QueryType: models.RANGE_QUERY,
+				Step:      1 * time.Minute,
+				RateMode:  models.RATE_EXTRAPOLATED,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`increase(go_gc_duration_seconds_count[5m] offset 1m)`),
+			},
+			want:    influxql.MustParseStatement("SELECT *::tag, value FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T06:59:00Z' AND time >= '2023-01-06T03:54:00Z' GROUP BY * TZ('Asia/Shanghai')"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:           &startTime2,
+				End:             &endTime2,
+				QueryType:       models.RANGE_QUERY,
+				DataType:        models.LABEL_VALUES_DATA,
+				Database:        "prometheus",
+				LabelName:       "job",
+				LabelValuesMode: models.LABEL_VALUES_ACCURATE,
+				SchemaMapper:    schema.TelegrafV2Mapper{},
+			},
+			args: args{
+				expr: testinghelper.VectorSelector(`go_goroutines{instance=~"192.168.*"}`),
+			},
+			want:    influxql.MustParseStatement(`SELECT last(go_goroutines) FROM prometheus WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T04:00:00Z' AND instance =~ /^(?:192.168.*)$/ GROUP BY job`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				RateMode:   models.RATE_EXTRAPOLATED,
+			},
+			args: args{
+				expr: testinghelper.AggregateExpr(`sum(rate(go_gc_duration_seconds_count[5m]))`),
+			},
+			want:    influxql.MustParseStatement("SELECT *::tag, value FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T06:55:00Z' GROUP BY *"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				RateMode:   models.RATE_EXTRAPOLATED,
+			},
+			args: args{
+				expr: testinghelper.BinaryExpr(`2 * sum by (job) (increase(go_gc_duration_seconds_count[5m]))`),
+			},
+			want:    influxql.MustParseStatement("SELECT *::tag, value FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T06:55:00Z' GROUP BY *"),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				RateMode:   models.RATE_EXTRAPOLATED,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`abs(increase(go_gc_duration_seconds_count[5m]))`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "",
+			fields: fields{
+				Evaluation: &endTime2,
+				RateMode:   models.RATE_EXTRAPOLATED,
+			},
+			args: args{
+				expr: testinghelper.BinaryExpr(`rate(go_gc_duration_seconds_count[5m]) / rate(go_gc_duration_seconds_sum[5m])`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      5 * time.Minute,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m])`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:55:00Z' GROUP BY *, time(5m) fill(none)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m])`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(value) FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:45Z' GROUP BY *, time(15s) fill(none)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.AggregateExpr(`sum by (job) (rate(go_gc_duration_seconds_count[5m]))`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(non_negative_derivative) FROM (SELECT *::tag, non_negative_derivative(value) FROM go_gc_duration_seconds_count GROUP BY *) WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:45Z' GROUP BY job, time(15s) fill(none)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`abs(rate(go_gc_duration_seconds_count[5m]))`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, abs(non_negative_derivative) FROM (SELECT *::tag, non_negative_derivative(value) FROM go_gc_duration_seconds_count GROUP BY *) WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:45Z' GROUP BY time(15s) fill(none)`),
+			wantErr: false,
+		},
+		{
+			// binary operations between vectors are not supported by InfluxQL transpiling as before, rather than
+			// rejected as extrapolated mode does
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.BinaryExpr(`rate(go_gc_duration_seconds_count[5m]) / rate(go_gc_duration_seconds_sum[5m])`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.AggregateExpr(`sum by (le) (rate(go_gc_duration_seconds_bucket[5m]))`),
+			},
+			want:    influxql.MustParseStatement(`SELECT sum(non_negative_derivative) FROM (SELECT *::tag, non_negative_derivative(value) FROM go_gc_duration_seconds_bucket GROUP BY *) WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:45Z' GROUP BY le, time(15s) fill(none)`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				RateMode:  models.RATE_EXTRAPOLATED,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`sum_over_time(go_gc_duration_seconds_count[5m])`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, value FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:55:00Z' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				RateMode:  models.RATE_EXTRAPOLATED,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.AggregateExpr(`sum by (job) (rate(go_gc_duration_seconds_count[5m]))`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, value FROM go_gc_duration_seconds_count WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:55:00Z' GROUP BY *`),
+			wantErr: false,
+		},
+		{
+			name: "",
+			fields: fields{
+				Start:     &startTime2,
+				End:       &endTime2,
+				QueryType: models.RANGE_QUERY,
+				Step:      15 * time.Second,
+				RateMode:  models.RATE_EXTRAPOLATED,
+				DataType:  models.GRAPH_DATA,
+			},
+			args: args{
+				expr: testinghelper.CallExpr(`abs(sum_over_time(go_gc_duration_seconds_count[5m]))`),
+			},
+			want:    influxql.MustParseStatement(`SELECT *::tag, abs(sum) FROM (SELECT sum(value) FROM go_gc_duration_seconds_count GROUP BY *) WHERE time <= '2023-01-06T07:00:00Z' AND time >= '2023-01-06T03:59:45Z' GROUP BY time(15s) fill(none)`),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
 			t := &Transpiler{
 				PromCommand: models.PromCommand{
-					Start:      tt.fields.Start,
-					End:        tt.fields.End,
-					Timezone:   tt.fields.Timezone,
-					Evaluation: tt.fields.Evaluation,
-					Step:       tt.fields.Step,
-					DataType:   tt.fields.DataType,
-					Database:   tt.fields.Database,
-					LabelName:  tt.fields.LabelName,
+					Start:           tt.fields.Start,
+					End:             tt.fields.End,
+					Timezone:        tt.fields.Timezone,
+					Evaluation:      tt.fields.Evaluation,
+					QueryType:       tt.fields.QueryType,
+					LookbackDelta:   tt.fields.LookbackDelta,
+					Step:            tt.fields.Step,
+					Fill:            tt.fields.Fill,
+					RateMode:        tt.fields.RateMode,
+					DataType:        tt.fields.DataType,
+					Database:        tt.fields.Database,
+					LabelName:       tt.fields.LabelName,
+					LabelValuesMode: tt.fields.LabelValuesMode,
 				},
+				SchemaMapper:   tt.fields.SchemaMapper,
 				timeRange:      tt.fields.timeRange,
 				parenExprCount: tt.fields.parenExprCount,
 				timeCondition:  tt.fields.condition,
@@ -398,70 +850,6 @@ func TestTranspiler_transpile(t1 *testing.T) {
 	}
 }
 
-func TestCondition_Or(t *testing.T) {
-	type args struct {
-		expr *influxql.BinaryExpr
-	}
-	tests := []struct {
-		name     string
-		receiver *Condition
-		args     args
-		want     *Condition
-	}{
-		{
-			name: "",
-			receiver: &Condition{
-				Op: influxql.EQREGEX,
-				LHS: &influxql.VarRef{
-					Val: "cpu",
-				},
-				RHS: &influxql.StringLiteral{
-					Val: "cpu.*",
-				},
-			},
-			args: args{
-				expr: &influxql.BinaryExpr{
-					Op: influxql.EQ,
-					LHS: &influxql.VarRef{
-						Val: "host",
-					},
-					RHS: &influxql.StringLiteral{
-						Val: "prometheus-server",
-					},
-				},
-			},
-			want: &Condition{
-				Op: influxql.OR,
-				LHS: &influxql.BinaryExpr{
-					Op: influxql.EQREGEX,
-					LHS: &influxql.VarRef{
-						Val: "cpu",
-					},
-					RHS: &influxql.StringLiteral{
-						Val: "cpu.*",
-					},
-				},
-				RHS: &influxql.BinaryExpr{
-					Op: influxql.EQ,
-					LHS: &influxql.VarRef{
-						Val: "host",
-					},
-					RHS: &influxql.StringLiteral{
-						Val: "prometheus-server",
-					},
-				},
-			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.receiver.Or(tt.args.expr); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Or() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func Test_makeInt64Pointer(t *testing.T) {
 	var a int64 = 1
 	type args struct {
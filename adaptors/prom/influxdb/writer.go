@@ -0,0 +1,214 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	influxdb "github.com/influxdata/influxdb1-client/v2"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/sliceutils"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/escaping"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWriteBatchSize    = 5000
+	defaultWriteRetryBackoff = 100 * time.Millisecond
+	// maxRejectionReasons limits how many reasons of rejected series and batches are reported in the error of Writer
+	maxRejectionReasons = 10
+)
+
+// ErrRejectedPoints is wrapped by errors of Writer when InfluxDB or the client rejects points as invalid,
+// so writing them again won't succeed
+var ErrRejectedPoints = errors.New("rejected points")
+
+// rejectedMessages are parts of InfluxDB error messages of write requests responded with 4xx status code
+var rejectedMessages = []string{"partial write", "unable to parse", "points beyond retention policy"}
+
+// WriterConfig configures Writer
+type WriterConfig struct {
+	// Verbose indicates whether to output more logs or not
+	Verbose bool
+	// SchemaMapper maps Prometheus metrics to InfluxDB measurements and fields, default is schema.PromWriteMapper
+	SchemaMapper schema.Mapper
+	// BatchSize sets the maximum number of points of a single write request to InfluxDB, default is 5000
+	BatchSize int
+	// MaxRetries sets how many times a failed batch is written again, 0 means no retry.
+	// Batches rejected by InfluxDB as invalid are never retried.
+	MaxRetries int
+	// RetryBackoff sets the waiting duration before the first retry, it is doubled for each following retry. Default is 100ms
+	RetryBackoff time.Duration
+}
+
+// Writer writes Prometheus samples to InfluxDB in the layout of WriterConfig.SchemaMapper,
+// so that they can be queried back by QueryCommandRunner with the same schema.Mapper.
+type Writer struct {
+	Cfg    WriterConfig
+	Client influxdb.Client
+}
+
+// NewWriter returns a pointer to Writer
+func NewWriter(client influxdb.Client, cfg WriterConfig) *Writer {
+	return &Writer{
+		Cfg:    cfg,
+		Client: client,
+	}
+}
+
+func (receiver *Writer) schemaMapper() schema.Mapper {
+	if receiver.Cfg.SchemaMapper != nil {
+		return receiver.Cfg.SchemaMapper
+	}
+	return schema.PromWriteMapper{}
+}
+
+func (receiver *Writer) batchSize() int {
+	if receiver.Cfg.BatchSize > 0 {
+		return receiver.Cfg.BatchSize
+	}
+	return defaultWriteBatchSize
+}
+
+func (receiver *Writer) retryBackoff() time.Duration {
+	if receiver.Cfg.RetryBackoff > 0 {
+		return receiver.Cfg.RetryBackoff
+	}
+	return defaultWriteRetryBackoff
+}
+
+// Write converts samples of cmd to points and writes them in batches. NaN and infinite samples like Prometheus stale markers
+// are dropped, as InfluxDB can't store them. Series which can't be converted to points, like the ones without metric name,
+// and batches rejected by InfluxDB are reported by the error, but the other series and batches are still written.
+func (receiver *Writer) Write(ctx context.Context, cmd models.PromWriteCommand) error {
+	var (
+		points  []*influxdb.Point
+		reasons []string
+		dropped int
+	)
+	for _, series := range cmd.TimeSeries {
+		seriesPoints, droppedSamples, err := receiver.seriesPoints(series)
+		if err != nil {
+			reasons = append(reasons, err.Error())
+			continue
+		}
+		points = append(points, seriesPoints...)
+		dropped += droppedSamples
+	}
+	if dropped > 0 && receiver.Cfg.Verbose {
+		zlogger.Info().Msgf("dropped %d NaN or infinite samples", dropped)
+	}
+	rejectedSeries := len(reasons)
+	batchSize := receiver.batchSize()
+	for start := 0; start < len(points); start += batchSize {
+		end := start + batchSize
+		if end > len(points) {
+			end = len(points)
+		}
+		bp, err := influxdb.NewBatchPoints(influxdb.BatchPointsConfig{
+			Database:  cmd.Database,
+			Precision: "ms",
+		})
+		if err != nil {
+			return errors.Wrap(err, "fail to create batch points")
+		}
+		bp.AddPoints(points[start:end])
+		if err = receiver.writeBatch(ctx, bp); err != nil {
+			if !errors.Is(err, ErrRejectedPoints) {
+				return err
+			}
+			reasons = append(reasons, fmt.Sprintf("batch of points %d to %d: %s", start, end-1, err))
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	if len(reasons) > maxRejectionReasons {
+		reasons = append(reasons[:maxRejectionReasons], fmt.Sprintf("and %d more", len(reasons)-maxRejectionReasons))
+	}
+	return errors.Wrapf(ErrRejectedPoints, "%d of %d series and %d batches rejected: %s", rejectedSeries, len(cmd.TimeSeries),
+		len(reasons)-rejectedSeries, strings.Join(reasons, "; "))
+}
+
+// seriesPoints returns a point for each sample of series, together with the number of dropped samples. Series is validated
+// before any point is returned, the error tells why it can't be written.
+func (receiver *Writer) seriesPoints(series models.TimeSeries) ([]*influxdb.Point, int, error) {
+	var (
+		metricName string
+		matchers   []*labels.Matcher
+	)
+	for _, item := range series.Labels {
+		if item.Name == labels.MetricName {
+			metricName = item.Value
+			continue
+		}
+		matchers = append(matchers, labels.MustNewMatcher(labels.MatchEqual, item.Name, item.Value))
+	}
+	if stringutils.IsEmpty(metricName) {
+		return nil, 0, errors.Errorf("series %v has no metric name", series.Labels)
+	}
+	source, err := receiver.schemaMapper().Source(escaping.UnescapeName(metricName), matchers, "")
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "series %v", series.Labels)
+	}
+	if stringutils.IsEmpty(source.FieldKey) {
+		return nil, 0, errors.Errorf("series %v has no %s label for the field key", series.Labels, source.FieldLabel)
+	}
+	tags := make(map[string]string, len(matchers))
+	for _, item := range matchers {
+		// Empty label values are the same as missing labels in Prometheus, and they are not allowed in line protocol
+		if stringutils.IsEmpty(item.Value) || sliceutils.StringContains(source.ConsumedLabels, item.Name) {
+			continue
+		}
+		tags[escaping.UnescapeName(item.Name)] = item.Value
+	}
+	points := make([]*influxdb.Point, 0, len(series.Samples))
+	dropped := 0
+	for _, sample := range series.Samples {
+		if math.IsNaN(sample.Value) || math.IsInf(sample.Value, 0) {
+			dropped++
+			continue
+		}
+		point, err := influxdb.NewPoint(source.Measurement, tags, map[string]interface{}{
+			source.FieldKey: sample.Value,
+		}, time.UnixMilli(sample.Timestamp))
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "series %v", series.Labels)
+		}
+		points = append(points, point)
+	}
+	return points, dropped, nil
+}
+
+// writeBatch writes bp and retries with exponential backoff unless InfluxDB rejects it or ctx is done
+func (receiver *Writer) writeBatch(ctx context.Context, bp influxdb.BatchPoints) error {
+	backoff := receiver.retryBackoff()
+	for retries := 0; ; retries++ {
+		err := receiver.Client.Write(bp)
+		if err == nil {
+			return nil
+		}
+		for _, item := range rejectedMessages {
+			if strings.Contains(err.Error(), item) {
+				return errors.Wrap(ErrRejectedPoints, err.Error())
+			}
+		}
+		if retries >= receiver.Cfg.MaxRetries {
+			return errors.Wrapf(err, "fail to write %d points after %d retries", len(bp.Points()), retries)
+		}
+		if receiver.Cfg.Verbose {
+			zlogger.Error().Err(err).Msgf("fail to write %d points, retry in %s", len(bp.Points()), backoff)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "fail to write points")
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
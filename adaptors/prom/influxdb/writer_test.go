@@ -0,0 +1,239 @@
+package influxdb
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/mock"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
+	"math"
+	"testing"
+	"time"
+)
+
+// expectWrites records line protocol of every batch written to mockClient, the nth write returns errs[n] if any
+func expectWrites(mockClient *mock.MockClient, batches *[][]string, errs ...error) {
+	call := 0
+	mockClient.EXPECT().Write(gomock.Any()).DoAndReturn(func(bp client.BatchPoints) error {
+		var lines []string
+		for _, point := range bp.Points() {
+			lines = append(lines, point.PrecisionString(bp.Precision()))
+		}
+		*batches = append(*batches, lines)
+		defer func() {
+			call++
+		}()
+		if call < len(errs) {
+			return errs[call]
+		}
+		return nil
+	}).AnyTimes()
+}
+
+func TestWriter_Write(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	var batches [][]string
+	expectWrites(mockClient, &batches)
+
+	writer := NewWriter(mockClient, WriterConfig{
+		BatchSize: 2,
+	})
+	err := writer.Write(context.Background(), models.PromWriteCommand{
+		Database: "prometheus",
+		TimeSeries: []models.TimeSeries{
+			{
+				Labels: []models.Label{{Name: "__name__", Value: "go_goroutines"}, {Name: "job", Value: "prometheus"}, {Name: "instance", Value: ""}},
+				Samples: []models.Sample{
+					{Timestamp: 1000, Value: 1},
+					{Timestamp: 2000, Value: math.NaN()},
+					{Timestamp: 3000, Value: 3},
+				},
+			},
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "U__cpu_2e_usage"}, {Name: "U__http_2d_method", Value: "GET"}},
+				Samples: []models.Sample{{Timestamp: 4000, Value: 0.5}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{
+			"go_goroutines,job=prometheus value=1 1000",
+			"go_goroutines,job=prometheus value=3 3000",
+		},
+		{
+			"cpu.usage,http-method=GET value=0.5 4000",
+		},
+	}, batches)
+}
+
+func TestWriter_Write_TelegrafV1(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	var batches [][]string
+	expectWrites(mockClient, &batches)
+
+	writer := NewWriter(mockClient, WriterConfig{
+		SchemaMapper: schema.TelegrafV1Mapper{},
+	})
+	err := writer.Write(context.Background(), models.PromWriteCommand{
+		Database: "prometheus",
+		TimeSeries: []models.TimeSeries{
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "http_request_duration_seconds_bucket"}, {Name: "le", Value: "0.5"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 10}},
+			},
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "http_requests_total"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 20}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{
+			"http_request_duration_seconds 0.5=10 1000",
+			"http_requests_total counter=20 1000",
+		},
+	}, batches)
+}
+
+func TestWriter_Write_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	var batches [][]string
+	expectWrites(mockClient, &batches, errors.New("timeout"), errors.New("timeout"))
+
+	writer := NewWriter(mockClient, WriterConfig{
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+	err := writer.Write(context.Background(), models.PromWriteCommand{
+		Database: "prometheus",
+		TimeSeries: []models.TimeSeries{
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "go_goroutines"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 1}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, batches, 3)
+}
+
+func TestWriter_Write_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	var batches [][]string
+	expectWrites(mockClient, &batches, errors.New(`{"error":"partial write: field type conflict"}`))
+
+	writer := NewWriter(mockClient, WriterConfig{
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+	err := writer.Write(context.Background(), models.PromWriteCommand{
+		Database: "prometheus",
+		TimeSeries: []models.TimeSeries{
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "go_goroutines"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 1}},
+			},
+		},
+	})
+	require.ErrorIs(t, err, ErrRejectedPoints)
+	assert.Len(t, batches, 1)
+}
+
+func TestWriter_Write_NoMetricName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	var batches [][]string
+	expectWrites(mockClient, &batches)
+
+	writer := NewWriter(mockClient, WriterConfig{})
+	err := writer.Write(context.Background(), models.PromWriteCommand{
+		Database: "prometheus",
+		TimeSeries: []models.TimeSeries{
+			{
+				Labels:  []models.Label{{Name: "job", Value: "prometheus"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 1}},
+			},
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "go_goroutines"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 1}},
+			},
+		},
+	})
+	require.ErrorIs(t, err, ErrRejectedPoints)
+	assert.Equal(t, [][]string{{"go_goroutines value=1 1000"}}, batches)
+}
+
+func TestWriter_Write_NoFieldKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	var batches [][]string
+	expectWrites(mockClient, &batches)
+
+	writer := NewWriter(mockClient, WriterConfig{
+		SchemaMapper: schema.TelegrafV1Mapper{},
+	})
+	err := writer.Write(context.Background(), models.PromWriteCommand{
+		Database: "prometheus",
+		TimeSeries: []models.TimeSeries{
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "http_request_duration_seconds_bucket"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 10}},
+			},
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "http_requests_total"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 20}},
+			},
+		},
+	})
+	require.ErrorIs(t, err, ErrRejectedPoints)
+	assert.EqualError(t, err, "1 of 2 series and 0 batches rejected: series [{__name__ http_request_duration_seconds_bucket}] has no le label for the field key: rejected points")
+	assert.Equal(t, [][]string{{"http_requests_total counter=20 1000"}}, batches)
+}
+
+func TestWriter_Write_PartiallyRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock.NewMockClient(ctrl)
+	var batches [][]string
+	expectWrites(mockClient, &batches, errors.New(`{"error":"partial write: field type conflict dropped=1"}`))
+
+	writer := NewWriter(mockClient, WriterConfig{
+		BatchSize: 1,
+	})
+	err := writer.Write(context.Background(), models.PromWriteCommand{
+		Database: "prometheus",
+		TimeSeries: []models.TimeSeries{
+			{
+				Labels:  []models.Label{{Name: "__name__", Value: "go_goroutines"}},
+				Samples: []models.Sample{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+			},
+		},
+	})
+	require.ErrorIs(t, err, ErrRejectedPoints)
+	assert.Contains(t, err.Error(), "0 of 1 series and 1 batches rejected: batch of points 0 to 0")
+	// the batches after the rejected one are still written
+	assert.Equal(t, [][]string{{"go_goroutines value=1 1000"}, {"go_goroutines value=2 2000"}}, batches)
+}
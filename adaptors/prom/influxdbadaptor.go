@@ -5,8 +5,11 @@ import (
 	_ "github.com/influxdata/influxdb1-client" // this is important because of the bug in go mod
 	influxdb "github.com/influxdata/influxdb1-client/v2"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
 	influx "github.com/wubin1989/promql2influxql/adaptors/prom/influxdb"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
 	"github.com/wubin1989/promql2influxql/adaptors/prom/models"
 	"github.com/wubin1989/promql2influxql/applications"
 	"time"
@@ -18,9 +21,20 @@ type InfluxDBAdaptorConfig struct {
 	Timeout time.Duration
 	// Verbose indicates whether to output more logs or not
 	Verbose bool
+	// SchemaMapper maps PromQL metrics to InfluxDB measurements and fields, default is schema.PromWriteMapper
+	SchemaMapper schema.Mapper
+	// ChunkSize sets the maximum number of points of every chunk InfluxDB responds to QueryStream, default is InfluxDB's 10000
+	ChunkSize int
+	// WriteBatchSize sets the maximum number of points of a single write request to InfluxDB, default is 5000
+	WriteBatchSize int
+	// WriteMaxRetries sets how many times a failed write request is sent again, 0 means no retry
+	WriteMaxRetries int
+	// WriteRetryBackoff sets the waiting duration before the first retry of a write request, default is 100ms
+	WriteRetryBackoff time.Duration
 }
 
 var _ applications.IPromAdaptor = (*InfluxDBAdaptor)(nil)
+var _ applications.IPromWriter = (*InfluxDBAdaptor)(nil)
 
 // InfluxDBAdaptor is a concrete struct that implementing applications.IPromAdaptor.
 // It depends on influxdb.Client to issue http requests to InfluxDB storage to fetch matrix data under the hood.
@@ -32,24 +46,9 @@ type InfluxDBAdaptor struct {
 
 // Query implements applications.IPromAdaptor's Query method
 func (receiver *InfluxDBAdaptor) Query(ctx context.Context, cmd applications.PromCommand) (applications.RunResult, error) {
-	runner := influx.SingletonQueryCommandRunnerFactory.Build(receiver.Client, influx.QueryCommandRunnerConfig{
-		Timeout: receiver.Cfg.Timeout,
-		Verbose: receiver.Cfg.Verbose,
-	})
+	runner := receiver.runner()
 	defer runner.Recycle()
-	promCommand := models.PromCommand{
-		Cmd:           cmd.Cmd,
-		Database:      cmd.Database,
-		Start:         cmd.Start,
-		End:           cmd.End,
-		Timezone:      cmd.Timezone,
-		Evaluation:    cmd.Evaluation,
-		Step:          cmd.Step,
-		DataType:      models.DataType(cmd.DataType),
-		ValueFieldKey: cmd.ValueFieldKey,
-		LabelName:     cmd.LabelName,
-	}
-	runResult, err := runner.Run(ctx, promCommand)
+	runResult, err := runner.Run(ctx, promCommand(cmd))
 	if err != nil {
 		return applications.RunResult{}, errors.Wrap(err, caller.NewCaller().String())
 	}
@@ -60,6 +59,92 @@ func (receiver *InfluxDBAdaptor) Query(ctx context.Context, cmd applications.Pro
 	}, nil
 }
 
+// QueryStream implements applications.IPromAdaptor's QueryStream method
+func (receiver *InfluxDBAdaptor) QueryStream(ctx context.Context, cmd applications.PromCommand, send func(result applications.RunResult) error) error {
+	runner := receiver.runner()
+	defer runner.Recycle()
+	err := runner.Stream(ctx, promCommand(cmd), func(matrix promql.Matrix) error {
+		return send(applications.RunResult{
+			Result:     matrix,
+			ResultType: string(parser.ValueTypeMatrix),
+		})
+	})
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
+}
+
+// Write implements applications.IPromWriter's Write method
+func (receiver *InfluxDBAdaptor) Write(ctx context.Context, cmd applications.PromWriteCommand) error {
+	writer := influx.NewWriter(receiver.Client, influx.WriterConfig{
+		Verbose:      receiver.Cfg.Verbose,
+		SchemaMapper: receiver.Cfg.SchemaMapper,
+		BatchSize:    receiver.Cfg.WriteBatchSize,
+		MaxRetries:   receiver.Cfg.WriteMaxRetries,
+		RetryBackoff: receiver.Cfg.WriteRetryBackoff,
+	})
+	if err := writer.Write(ctx, promWriteCommand(cmd)); err != nil {
+		if errors.Is(err, influx.ErrRejectedPoints) {
+			return errors.Wrap(applications.ErrRejectedSamples, err.Error())
+		}
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
+}
+
+func (receiver *InfluxDBAdaptor) runner() *influx.QueryCommandRunner {
+	return influx.SingletonQueryCommandRunnerFactory.Build(receiver.Client, influx.QueryCommandRunnerConfig{
+		Timeout:      receiver.Cfg.Timeout,
+		Verbose:      receiver.Cfg.Verbose,
+		SchemaMapper: receiver.Cfg.SchemaMapper,
+		ChunkSize:    receiver.Cfg.ChunkSize,
+	})
+}
+
+// promCommand converts applications.PromCommand to models.PromCommand
+func promCommand(cmd applications.PromCommand) models.PromCommand {
+	return models.PromCommand{
+		Cmd:             cmd.Cmd,
+		Database:        cmd.Database,
+		Start:           cmd.Start,
+		End:             cmd.End,
+		Timezone:        cmd.Timezone,
+		Evaluation:      cmd.Evaluation,
+		QueryType:       models.QueryType(cmd.QueryType),
+		LookbackDelta:   cmd.LookbackDelta,
+		Step:            cmd.Step,
+		Fill:            models.FillType(cmd.Fill),
+		RateMode:        models.RateMode(cmd.RateMode),
+		DataType:        models.DataType(cmd.DataType),
+		ValueFieldKey:   cmd.ValueFieldKey,
+		LabelName:       cmd.LabelName,
+		LabelValuesMode: models.LabelValuesMode(cmd.LabelValuesMode),
+	}
+}
+
+// promWriteCommand converts applications.PromWriteCommand to models.PromWriteCommand
+func promWriteCommand(cmd applications.PromWriteCommand) models.PromWriteCommand {
+	timeSeries := make([]models.TimeSeries, 0, len(cmd.TimeSeries))
+	for _, series := range cmd.TimeSeries {
+		item := models.TimeSeries{
+			Labels:  make([]models.Label, 0, len(series.Labels)),
+			Samples: make([]models.Sample, 0, len(series.Samples)),
+		}
+		for _, label := range series.Labels {
+			item.Labels = append(item.Labels, models.Label(label))
+		}
+		for _, sample := range series.Samples {
+			item.Samples = append(item.Samples, models.Sample(sample))
+		}
+		timeSeries = append(timeSeries, item)
+	}
+	return models.PromWriteCommand{
+		Database:   cmd.Database,
+		TimeSeries: timeSeries,
+	}
+}
+
 // NewInfluxDBAdaptor is a package-level factory method to return a pointer to InfluxDBAdaptor.
 // It is usually called by application service on the upper layer.
 func NewInfluxDBAdaptor(cfg InfluxDBAdaptorConfig, client influxdb.Client) *InfluxDBAdaptor {
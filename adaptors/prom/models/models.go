@@ -7,12 +7,71 @@ import "time"
 //  - TABLE_DATA is for raw table data query
 //  - GRAPH_DATA is for time-bounding graph data query
 // 	- LABEL_VALUES_DATA is for label values data query like fetching data for label selector options at the top of Grafana dashboard
+//  - SERIES_DATA is for series data query like Prometheus /api/v1/series which returns label sets of the series selected by match[]
+//  - LABEL_NAMES_DATA is for label names data query like Prometheus /api/v1/labels
+//  - METADATA_DATA is for metric metadata query like Prometheus /api/v1/metadata, Cmd is an optional metric name to filter metadata for
 type DataType int
 
 const (
 	TABLE_DATA DataType = iota + 1
 	GRAPH_DATA
 	LABEL_VALUES_DATA
+	SERIES_DATA
+	LABEL_NAMES_DATA
+	METADATA_DATA
+)
+
+// QueryType indicates a PromCommand is an instant query or a range query like Prometheus HTTP API does
+// Basically,
+//  - INSTANT_QUERY is evaluated at a single point in time like Prometheus /api/v1/query
+//  - RANGE_QUERY is evaluated over a time range from Start to End like Prometheus /api/v1/query_range
+type QueryType int
+
+const (
+	INSTANT_QUERY QueryType = iota + 1
+	RANGE_QUERY
+)
+
+// FillType indicates how empty time buckets of graph data queries are filled
+// Basically,
+//  - FILL_NONE drops empty time buckets, the same as Prometheus does for steps without samples
+//  - FILL_NULL keeps empty time buckets as NaN points
+//  - FILL_PREVIOUS fills empty time buckets with the value of the previous time bucket
+//  - FILL_LINEAR fills empty time buckets with the results of linear interpolation
+type FillType int
+
+const (
+	FILL_NONE FillType = iota + 1
+	FILL_NULL
+	FILL_PREVIOUS
+	FILL_LINEAR
+)
+
+// RateMode indicates how PromQL rate and increase functions are evaluated
+// Basically,
+//  - RATE_APPROXIMATE transpiles rate to InfluxQL non_negative_derivative function, it is fast but the results are per-point
+//    derivatives which may differ from Prometheus's
+//  - RATE_EXTRAPOLATED fetches raw counter samples of every window and evaluates rate and increase locally
+//    by Prometheus's extrapolatedRate logic, taking counter resets into account. Only rate or increase optionally
+//    wrapped by aggregations and operations with numbers is supported, other expressions require RATE_APPROXIMATE
+type RateMode int
+
+const (
+	RATE_APPROXIMATE RateMode = iota + 1
+	RATE_EXTRAPOLATED
+)
+
+// LabelValuesMode indicates how label values queries are answered
+// Basically,
+//  - LABEL_VALUES_INDEX issues SHOW TAG VALUES statement which is answered from the index fast, but InfluxDB 1.x ignores
+//    time conditions of it on TSI indexes, so values of series which stopped reporting long ago are returned as well
+//  - LABEL_VALUES_ACCURATE issues SELECT statement bounded by start and end time grouping by the tag instead,
+//    which only returns values of the series having points in the time range
+type LabelValuesMode int
+
+const (
+	LABEL_VALUES_INDEX LabelValuesMode = iota + 1
+	LABEL_VALUES_ACCURATE
 )
 
 // PromCommand wraps a raw query expression with several related attributes
@@ -25,13 +84,27 @@ type PromCommand struct {
 	End        *time.Time
 	Timezone   *time.Location
 	Evaluation *time.Time
+	// QueryType indicates the PromCommand is an instant query or a range query.
+	// Zero value is treated as INSTANT_QUERY.
+	// Start is only used by range queries, and instant queries are evaluated at End or Evaluation.
+	QueryType QueryType
+	// LookbackDelta bounds VectorSelector of instant queries to samples within it before the evaluation time
+	// like Prometheus lookback delta does. Zero value means unbounded.
+	LookbackDelta time.Duration
 	// Step is evaluation step for PromQL.
 	// As InfluxQL doesn't have the equivalent expression or concept,
-	// we use it as interval parameter for InfluxQL GROUP BY time(interval)
-	// if the raw query doesn't contain PromQL MatrixSelector expression.
-	// If the raw query does contain PromQL parser.MatrixSelector expression,
-	// its Range attribute will be used as the interval parameter.
+	// we use it as interval parameter for InfluxQL GROUP BY time(interval) of graph data queries,
+	// so range functions over PromQL parser.MatrixSelector expression are evaluated over time buckets as wide as Step.
+	// In RATE_EXTRAPOLATED mode, they are evaluated locally from raw samples of a window as wide as the Range attribute
+	// at every step instead, unless they are aggregations over time whose Range attribute equals Step.
+	// If Step is not set, the Range attribute will be used as the interval parameter.
 	Step time.Duration
+	// Fill indicates how empty time buckets of graph data queries are filled.
+	// Zero value is treated as FILL_NONE.
+	Fill FillType
+	// RateMode indicates how PromQL rate and increase functions are evaluated.
+	// Zero value is treated as RATE_APPROXIMATE.
+	RateMode RateMode
 
 	DataType DataType
 	// ValueFieldKey indicates which field will be used.
@@ -39,9 +112,18 @@ type PromCommand struct {
 	// so we may need to set ValueFieldKey.
 	//
 	// Default is ```value``` field.
+	// It is overridden by ```__field__``` pseudo label matchers in the query expression like cpu{__field__=~"usage_.*"}
+	// which select one or more fields.
 	ValueFieldKey string
+	// ValueFieldLabel is the label name whose values are field keys, like le of histogram buckets written by Telegraf
+	// with metric_version = 1. It is resolved by the schema mapper along with ValueFieldKey for rebuilding labels
+	// from query results, so it is not meant to be set by users.
+	ValueFieldLabel string
 	// LabelName is only used for label values query.
 	LabelName string
+	// LabelValuesMode indicates how label values queries are answered.
+	// Zero value is treated as LABEL_VALUES_INDEX.
+	LabelValuesMode LabelValuesMode
 }
 
 // RunResult wraps query result and possible error
@@ -50,3 +132,27 @@ type RunResult struct {
 	ResultType string
 	Error      error
 }
+
+// PromWriteCommand wraps time series of a Prometheus remote write request to be written to Database
+type PromWriteCommand struct {
+	Database   string
+	TimeSeries []TimeSeries
+}
+
+// TimeSeries is a Prometheus time series of float samples. Labels include the metric name label __name__.
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// Label is a Prometheus label name and value pair
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a Prometheus float sample, Timestamp is in milliseconds
+type Sample struct {
+	Timestamp int64
+	Value     float64
+}
@@ -5,10 +5,69 @@ package applications
 //  - TABLE_DATA is for raw table data query
 //  - GRAPH_DATA is for time-bounding graph data query
 // 	- LABEL_VALUES_DATA is for label values data query like fetching data for label selector options at the top of Grafana dashboard
+//  - SERIES_DATA is for series data query like Prometheus /api/v1/series which returns label sets of the series selected by match[]
+//  - LABEL_NAMES_DATA is for label names data query like Prometheus /api/v1/labels
+//  - METADATA_DATA is for metric metadata query like Prometheus /api/v1/metadata, Cmd is an optional metric name to filter metadata for
 type DataType int
 
 const (
 	TABLE_DATA DataType = iota + 1
 	GRAPH_DATA
 	LABEL_VALUES_DATA
+	SERIES_DATA
+	LABEL_NAMES_DATA
+	METADATA_DATA
+)
+
+// QueryType indicates a PromCommand is an instant query or a range query like Prometheus HTTP API does
+// Basically,
+//  - INSTANT_QUERY is evaluated at a single point in time like Prometheus /api/v1/query
+//  - RANGE_QUERY is evaluated over a time range from Start to End like Prometheus /api/v1/query_range
+type QueryType int
+
+const (
+	INSTANT_QUERY QueryType = iota + 1
+	RANGE_QUERY
+)
+
+// FillType indicates how empty time buckets of graph data queries are filled
+// Basically,
+//  - FILL_NONE drops empty time buckets, the same as Prometheus does for steps without samples
+//  - FILL_NULL keeps empty time buckets as NaN points
+//  - FILL_PREVIOUS fills empty time buckets with the value of the previous time bucket
+//  - FILL_LINEAR fills empty time buckets with the results of linear interpolation
+type FillType int
+
+const (
+	FILL_NONE FillType = iota + 1
+	FILL_NULL
+	FILL_PREVIOUS
+	FILL_LINEAR
+)
+
+// RateMode indicates how PromQL rate and increase functions are evaluated
+// Basically,
+//  - RATE_APPROXIMATE transpiles rate to InfluxQL non_negative_derivative function, it is fast but the results are per-point
+//    derivatives which may differ from Prometheus's
+//  - RATE_EXTRAPOLATED fetches raw counter samples of every window and evaluates rate and increase locally
+//    by Prometheus's extrapolatedRate logic, taking counter resets into account. Only rate or increase optionally
+//    wrapped by aggregations and operations with numbers is supported, other expressions require RATE_APPROXIMATE
+type RateMode int
+
+const (
+	RATE_APPROXIMATE RateMode = iota + 1
+	RATE_EXTRAPOLATED
+)
+
+// LabelValuesMode indicates how label values queries are answered
+// Basically,
+//  - LABEL_VALUES_INDEX issues SHOW TAG VALUES statement which is answered from the index fast, but InfluxDB 1.x ignores
+//    time conditions of it on TSI indexes, so values of series which stopped reporting long ago are returned as well
+//  - LABEL_VALUES_ACCURATE issues SELECT statement bounded by start and end time grouping by the tag instead,
+//    which only returns values of the series having points in the time range
+type LabelValuesMode int
+
+const (
+	LABEL_VALUES_INDEX LabelValuesMode = iota + 1
+	LABEL_VALUES_ACCURATE
 )
@@ -2,11 +2,25 @@ package applications
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrRejectedSamples is wrapped by errors of IPromWriter when the storage rejects samples as invalid,
+// so writing them again won't succeed
+var ErrRejectedSamples = errors.New("rejected samples")
+
 type IPromAdaptor interface {
 	Query(ctx context.Context, cmd PromCommand) (RunResult, error)
+	// QueryStream runs range query cmd and calls send with every part of the matrix result as soon as it is available.
+	// A series may be split across consecutive parts. It stops and returns the error if send returns an error.
+	QueryStream(ctx context.Context, cmd PromCommand, send func(result RunResult) error) error
+}
+
+// IPromWriter is implemented by adaptors whose storage accepts writes of Prometheus samples
+type IPromWriter interface {
+	// Write writes samples of all time series in cmd. The error wraps ErrRejectedSamples if some samples are rejected as invalid.
+	Write(ctx context.Context, cmd PromWriteCommand) error
 }
 
 // PromCommand wraps a raw query expression with several related attributes
@@ -19,13 +33,27 @@ type PromCommand struct {
 	End        *time.Time
 	Timezone   *time.Location
 	Evaluation *time.Time
+	// QueryType indicates the PromCommand is an instant query or a range query.
+	// Zero value is treated as INSTANT_QUERY.
+	// Start is only used by range queries, and instant queries are evaluated at End or Evaluation.
+	QueryType QueryType
+	// LookbackDelta bounds VectorSelector of instant queries to samples within it before the evaluation time
+	// like Prometheus lookback delta does. Zero value means unbounded.
+	LookbackDelta time.Duration
 	// Step is evaluation step for PromQL.
 	// As InfluxQL doesn't have the equivalent expression or concept,
-	// we use it as interval parameter for InfluxQL GROUP BY time(interval)
-	// if the raw query doesn't contain PromQL MatrixSelector expression.
-	// If the raw query does contain PromQL parser.MatrixSelector expression,
-	// its Range attribute will be used as the interval parameter.
+	// we use it as interval parameter for InfluxQL GROUP BY time(interval) of graph data queries,
+	// so range functions over PromQL parser.MatrixSelector expression are evaluated over time buckets as wide as Step.
+	// In RATE_EXTRAPOLATED mode, they are evaluated locally from raw samples of a window as wide as the Range attribute
+	// at every step instead, unless they are aggregations over time whose Range attribute equals Step.
+	// If Step is not set, the Range attribute will be used as the interval parameter.
 	Step time.Duration
+	// Fill indicates how empty time buckets of graph data queries are filled.
+	// Zero value is treated as FILL_NONE.
+	Fill FillType
+	// RateMode indicates how PromQL rate and increase functions are evaluated.
+	// Zero value is treated as RATE_APPROXIMATE.
+	RateMode RateMode
 
 	DataType DataType
 	// ValueFieldKey indicates which field will be used.
@@ -33,9 +61,14 @@ type PromCommand struct {
 	// so we may need to set ValueFieldKey.
 	//
 	// Default is ```value``` field.
+	// It is overridden by ```__field__``` pseudo label matchers in the query expression like cpu{__field__=~"usage_.*"}
+	// which select one or more fields.
 	ValueFieldKey string
 	// LabelName is only used for label values query.
 	LabelName string
+	// LabelValuesMode indicates how label values queries are answered.
+	// Zero value is treated as LABEL_VALUES_INDEX.
+	LabelValuesMode LabelValuesMode
 }
 
 // RunResult wraps query result and possible error
@@ -44,3 +77,27 @@ type RunResult struct {
 	ResultType string
 	Error      error
 }
+
+// PromWriteCommand wraps time series of a Prometheus remote write request to be written to Database
+type PromWriteCommand struct {
+	Database   string
+	TimeSeries []TimeSeries
+}
+
+// TimeSeries is a Prometheus time series of float samples. Labels include the metric name label __name__.
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// Label is a Prometheus label name and value pair
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a Prometheus float sample, Timestamp is in milliseconds
+type Sample struct {
+	Timestamp int64
+	Value     float64
+}
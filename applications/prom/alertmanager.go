@@ -0,0 +1,71 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// PostableAlert is an alert posted to Alertmanager API v2 POST /api/v2/alerts. EndsAt of firing alerts is in the future
+// so that Alertmanager resolves them by itself if they are not sent again, and EndsAt of resolved alerts is when they are resolved.
+type PostableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// AlertNotifier sends firing and resolved alerts of alerting rules
+type AlertNotifier interface {
+	Notify(ctx context.Context, alerts []PostableAlert) error
+}
+
+// alertmanagerNotifier posts alerts to an Alertmanager compatible webhook
+type alertmanagerNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewAlertmanagerNotifier returns an AlertNotifier posting alerts to url like http://localhost:9093/api/v2/alerts,
+// or nil if url is empty which means alerts are not sent but only served by /api/v1/alerts
+func NewAlertmanagerNotifier(url string, timeout time.Duration) AlertNotifier {
+	if url == "" {
+		return nil
+	}
+	return &alertmanagerNotifier{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Notify implements AlertNotifier's Notify method. Responses other than 2xx are errors, and the alerts are sent again
+// at the next evaluation of their group.
+func (receiver *alertmanagerNotifier) Notify(ctx context.Context, alerts []PostableAlert) error {
+	content, err := json.Marshal(alerts)
+	if err != nil {
+		return errors.Wrap(err, "fail to marshal alerts")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, receiver.url, bytes.NewReader(content))
+	if err != nil {
+		return errors.Wrap(err, "fail to create request to alertmanager")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := receiver.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "fail to send alerts to alertmanager")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("alertmanager responded %s: %s", resp.Status, body)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
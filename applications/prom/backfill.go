@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/applications"
+	"golang.org/x/exp/slices"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// defaultBackfillBatchSamples is the default number of samples of a single write command of backfill
+const defaultBackfillBatchSamples = 10000
+
+// BackfillOptions configures Backfill
+type BackfillOptions struct {
+	// Start and End filter samples by timestamp, both inclusive. Nil means unbounded.
+	Start *time.Time
+	End   *time.Time
+	// BatchSize is the number of samples of a single write command, default is 10000.
+	// The checkpoint is saved after every batch.
+	BatchSize int
+	// CheckpointFile records the progress of backfill, so that an interrupted backfill resumes from the last written batch
+	// when it is run again with the same time range. Empty means no checkpoint.
+	CheckpointFile string
+	// DryRun only counts blocks, series and samples to be written. Neither samples nor the checkpoint are written.
+	DryRun bool
+}
+
+// BackfillReport counts what has been written, or would be written in dry-run mode
+type BackfillReport struct {
+	// Blocks is the number of blocks read
+	Blocks int
+	// SkippedBlocks is the number of blocks skipped as they are out of the time range or completed according to the checkpoint
+	SkippedBlocks int
+	// Series is the number of series having samples in the time range, counted once per block
+	Series int
+	// Samples is the number of float samples
+	Samples int
+	// HistogramSamples is the number of native histogram samples skipped, as the schemas of InfluxDB have no place for them
+	HistogramSamples int
+}
+
+// Backfiller writes the history of local Prometheus servers from their TSDB blocks. It is not a part of Prom interface,
+// because it reads the file system of the host rather than serving requests.
+type Backfiller interface {
+	// Backfill writes samples of the TSDB blocks found in dirs. Every item of dirs is either a block directory or
+	// a Prometheus data directory containing block directories. The report is returned even if err is not nil.
+	Backfill(ctx context.Context, dirs []string, opts BackfillOptions) (BackfillReport, error)
+}
+
+var _ Backfiller = (*PromImpl)(nil)
+
+// backfillCheckpoint is the content of BackfillOptions.CheckpointFile. Blocks are immutable and their series are read
+// in the order of the index, so the number of series written identifies the progress within a block.
+type backfillCheckpoint struct {
+	// Start and End are timestamps in milliseconds of the time range the checkpoint is created with
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	// Completed are ULIDs of blocks written completely
+	Completed []string `json:"completed"`
+	// Block is the ULID of the block being written, and Series is the number of its series written completely
+	Block  string `json:"block,omitempty"`
+	Series int    `json:"series,omitempty"`
+}
+
+// backfillBlock is a block directory with its meta.json
+type backfillBlock struct {
+	dir  string
+	meta tsdb.BlockMeta
+}
+
+// Backfill writes blocks in the order of their time ranges through the adaptor like remote write does, so series are stored
+// in the layout of the configured schema. Samples of a block are written every BatchSize samples, and Prometheus stale markers
+// are dropped, while the other NaN and infinite samples are handed over to the adaptor as they are. Samples of a series split across batches are written again on resumption, which overwrites
+// the same points in InfluxDB.
+func (receiver *PromImpl) Backfill(ctx context.Context, dirs []string, opts BackfillOptions) (BackfillReport, error) {
+	var report BackfillReport
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBackfillBatchSamples
+	}
+	blocks, err := findBackfillBlocks(dirs)
+	if err != nil {
+		return report, errors.Wrap(err, caller.NewCaller().String())
+	}
+	checkpoint := backfillCheckpoint{
+		Start: math.MinInt64,
+		End:   math.MaxInt64,
+	}
+	if opts.Start != nil {
+		checkpoint.Start = opts.Start.UnixMilli()
+	}
+	if opts.End != nil {
+		checkpoint.End = opts.End.UnixMilli()
+	}
+	if checkpoint.Start > checkpoint.End {
+		return report, errors.New("start is after end")
+	}
+	if err = loadBackfillCheckpoint(opts.CheckpointFile, &checkpoint); err != nil {
+		return report, errors.Wrap(err, caller.NewCaller().String())
+	}
+	save := func() error {
+		if opts.DryRun || stringutils.IsEmpty(opts.CheckpointFile) {
+			return nil
+		}
+		return saveBackfillCheckpoint(opts.CheckpointFile, checkpoint)
+	}
+	for _, block := range blocks {
+		ulid := block.meta.ULID.String()
+		// MaxTime of a block is exclusive
+		if slices.Contains(checkpoint.Completed, ulid) || block.meta.MinTime > checkpoint.End || block.meta.MaxTime <= checkpoint.Start {
+			report.SkippedBlocks++
+			continue
+		}
+		skipSeries := 0
+		if checkpoint.Block == ulid {
+			skipSeries = checkpoint.Series
+		}
+		if err = receiver.backfillBlock(ctx, block, skipSeries, opts, &checkpoint, &report, save); err != nil {
+			return report, errors.Wrapf(err, "fail to backfill block %s", block.dir)
+		}
+		report.Blocks++
+		checkpoint.Completed = append(checkpoint.Completed, ulid)
+		checkpoint.Block, checkpoint.Series = "", 0
+		if err = save(); err != nil {
+			return report, errors.Wrap(err, caller.NewCaller().String())
+		}
+		zlogger.Info().Msgf("backfilled block %s, %d blocks, %d series and %d samples in total", ulid, report.Blocks, report.Series, report.Samples)
+	}
+	return report, nil
+}
+
+// backfillBlock writes series of block after the first skipSeries ones, and saves checkpoint after every batch
+func (receiver *PromImpl) backfillBlock(ctx context.Context, block backfillBlock, skipSeries int, opts BackfillOptions,
+	checkpoint *backfillCheckpoint, report *BackfillReport, save func() error) error {
+	reader, err := tsdb.OpenBlock(nil, block.dir, nil)
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	defer reader.Close()
+	querier, err := tsdb.NewBlockQuerier(reader, checkpoint.Start, checkpoint.End)
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	defer querier.Close()
+	var (
+		batch   []applications.TimeSeries
+		samples int
+	)
+	// flush writes batch, and records that the first done series of the block are written
+	flush := func(done int) error {
+		if len(batch) > 0 && !opts.DryRun {
+			if err := receiver.write(ctx, batch); err != nil {
+				return errors.Wrap(err, caller.NewCaller().String())
+			}
+		}
+		batch, samples = nil, 0
+		checkpoint.Block, checkpoint.Series = block.meta.ULID.String(), done
+		return save()
+	}
+	seriesSet := querier.Select(false, nil, labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+"))
+	index := 0
+	for seriesSet.Next() {
+		if err = ctx.Err(); err != nil {
+			return errors.Wrap(err, caller.NewCaller().String())
+		}
+		index++
+		if index <= skipSeries {
+			continue
+		}
+		series := seriesSet.At()
+		item := applications.TimeSeries{
+			Labels: make([]applications.Label, 0, len(series.Labels())),
+		}
+		for _, label := range series.Labels() {
+			item.Labels = append(item.Labels, applications.Label{
+				Name:  label.Name,
+				Value: label.Value,
+			})
+		}
+		found := false
+		iterator := series.Iterator()
+		for valueType := iterator.Next(); valueType != chunkenc.ValNone; valueType = iterator.Next() {
+			if valueType != chunkenc.ValFloat {
+				report.HistogramSamples++
+				continue
+			}
+			ts, v := iterator.At()
+			if ts < checkpoint.Start || ts > checkpoint.End || value.IsStaleNaN(v) {
+				continue
+			}
+			found = true
+			item.Samples = append(item.Samples, applications.Sample{
+				Timestamp: ts,
+				Value:     v,
+			})
+			report.Samples++
+			if samples++; samples >= opts.BatchSize {
+				// The series is split across batches, so it is not counted as written until its last batch
+				batch = append(batch, item)
+				if err = flush(index - 1); err != nil {
+					return err
+				}
+				item.Samples = nil
+			}
+		}
+		if err = iterator.Err(); err != nil {
+			return errors.Wrap(err, caller.NewCaller().String())
+		}
+		if found {
+			report.Series++
+		}
+		if len(item.Samples) > 0 {
+			batch = append(batch, item)
+		}
+	}
+	if err = seriesSet.Err(); err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return flush(index)
+}
+
+// findBackfillBlocks returns blocks in dirs sorted by their time ranges. Directories without meta.json inside a data directory,
+// like wal and chunks_head, are not blocks and are ignored.
+func findBackfillBlocks(dirs []string) ([]backfillBlock, error) {
+	var blocks []backfillBlock
+	for _, dir := range dirs {
+		block, err := readBackfillBlock(dir)
+		if err == nil {
+			blocks = append(blocks, block)
+			continue
+		}
+		if !os.IsNotExist(errors.Cause(err)) {
+			return nil, err
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to read data directory")
+		}
+		found := false
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			block, err = readBackfillBlock(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				if os.IsNotExist(errors.Cause(err)) {
+					continue
+				}
+				return nil, err
+			}
+			blocks = append(blocks, block)
+			found = true
+		}
+		if !found {
+			return nil, errors.Errorf("no block found in %s", dir)
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].meta.MinTime != blocks[j].meta.MinTime {
+			return blocks[i].meta.MinTime < blocks[j].meta.MinTime
+		}
+		return blocks[i].meta.ULID.Compare(blocks[j].meta.ULID) < 0
+	})
+	return blocks, nil
+}
+
+func readBackfillBlock(dir string) (backfillBlock, error) {
+	block := backfillBlock{
+		dir: dir,
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "meta.json"))
+	if err != nil {
+		return block, errors.WithStack(err)
+	}
+	if err = json.Unmarshal(content, &block.meta); err != nil {
+		return block, errors.Wrapf(err, "fail to parse meta.json of block %s", dir)
+	}
+	return block, nil
+}
+
+// loadBackfillCheckpoint loads file into checkpoint if it exists. A checkpoint created with another time range is refused,
+// because the numbers of series written in it don't apply.
+func loadBackfillCheckpoint(file string, checkpoint *backfillCheckpoint) error {
+	if stringutils.IsEmpty(file) {
+		return nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "fail to read checkpoint file")
+	}
+	var loaded backfillCheckpoint
+	if err = json.Unmarshal(content, &loaded); err != nil {
+		return errors.Wrapf(err, "fail to parse checkpoint file %s", file)
+	}
+	if loaded.Start != checkpoint.Start || loaded.End != checkpoint.End {
+		return errors.Errorf("checkpoint file %s is created with another time range, remove it to backfill from scratch", file)
+	}
+	*checkpoint = loaded
+	return nil
+}
+
+// saveBackfillCheckpoint replaces file by renaming a temporary file, so the checkpoint is never left half written
+func saveBackfillCheckpoint(file string, checkpoint backfillCheckpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	tmp := file + ".tmp"
+	if err = os.WriteFile(tmp, content, 0644); err != nil {
+		return errors.Wrap(err, "fail to write checkpoint file")
+	}
+	if err = os.Rename(tmp, file); err != nil {
+		return errors.Wrap(err, "fail to write checkpoint file")
+	}
+	return nil
+}
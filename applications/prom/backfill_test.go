@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// backfillSample is a float sample of test blocks
+type backfillSample struct {
+	t int64
+	v float64
+}
+
+func (receiver backfillSample) T() int64                      { return receiver.t }
+func (receiver backfillSample) V() float64                    { return receiver.v }
+func (receiver backfillSample) H() *histogram.Histogram       { return nil }
+func (receiver backfillSample) FH() *histogram.FloatHistogram { return nil }
+func (receiver backfillSample) Type() chunkenc.ValueType      { return chunkenc.ValFloat }
+
+// failingWriteAdaptor fails writes after the first succeeded ones
+type failingWriteAdaptor struct {
+	remoteWriteAdaptor
+	succeeded int
+}
+
+func (receiver *failingWriteAdaptor) Write(ctx context.Context, cmd applications.PromWriteCommand) error {
+	if len(receiver.cmds) >= receiver.succeeded {
+		return errors.New("connection refused")
+	}
+	return receiver.remoteWriteAdaptor.Write(ctx, cmd)
+}
+
+// createBackfillBlocks creates a data directory of two blocks, the first one has go_goroutines and go_threads at
+// 1000, 2000 and 3000, the second one has go_goroutines at 7201000 followed by a stale marker
+func createBackfillBlocks(t *testing.T) string {
+	dir := t.TempDir()
+	series := []storage.Series{
+		storage.NewListSeries(labels.FromStrings(labels.MetricName, "go_goroutines", "job", "prometheus"), []tsdbutil.Sample{
+			backfillSample{t: 1000, v: 1}, backfillSample{t: 2000, v: 2}, backfillSample{t: 3000, v: 3},
+		}),
+		storage.NewListSeries(labels.FromStrings(labels.MetricName, "go_threads", "job", "prometheus"), []tsdbutil.Sample{
+			backfillSample{t: 1000, v: 10}, backfillSample{t: 2000, v: 20}, backfillSample{t: 3000, v: 30},
+		}),
+	}
+	if _, err := tsdb.CreateBlock(series, dir, 0, log.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+	series = []storage.Series{
+		storage.NewListSeries(labels.FromStrings(labels.MetricName, "go_goroutines", "job", "prometheus"), []tsdbutil.Sample{
+			backfillSample{t: 7201000, v: 4}, backfillSample{t: 7202000, v: math.Float64frombits(value.StaleNaN)},
+		}),
+	}
+	if _, err := tsdb.CreateBlock(series, dir, 0, log.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func backfillSamples(cmds []applications.PromWriteCommand) map[string][]applications.Sample {
+	samples := make(map[string][]applications.Sample)
+	for _, cmd := range cmds {
+		for _, series := range cmd.TimeSeries {
+			samples[series.Labels[0].Value] = append(samples[series.Labels[0].Value], series.Samples...)
+		}
+	}
+	return samples
+}
+
+func TestPromImpl_Backfill(t *testing.T) {
+	dir := createBackfillBlocks(t)
+	adaptor := &remoteWriteAdaptor{}
+	receiver := NewProm(&config.Config{
+		BizConf: config.BizConfig{
+			AdaptorInfluxDatabase: "prometheus",
+		},
+	}, adaptor)
+	report, err := receiver.Backfill(context.Background(), []string{dir}, BackfillOptions{
+		BatchSize: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReport := BackfillReport{
+		Blocks:  2,
+		Series:  3,
+		Samples: 7,
+	}
+	if report != wantReport {
+		t.Errorf("Backfill() got = %+v, want %+v", report, wantReport)
+	}
+	want := map[string][]applications.Sample{
+		"go_goroutines": {{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}, {Timestamp: 3000, Value: 3}, {Timestamp: 7201000, Value: 4}},
+		"go_threads":    {{Timestamp: 1000, Value: 10}, {Timestamp: 2000, Value: 20}, {Timestamp: 3000, Value: 30}},
+	}
+	if got := backfillSamples(adaptor.cmds); !reflect.DeepEqual(got, want) {
+		t.Errorf("Backfill() got = %v, want %v", got, want)
+	}
+	for _, cmd := range adaptor.cmds {
+		if cmd.Database != "prometheus" {
+			t.Errorf("Backfill() got database %s", cmd.Database)
+		}
+	}
+}
+
+func TestPromImpl_Backfill_NaN(t *testing.T) {
+	dir := t.TempDir()
+	series := []storage.Series{
+		storage.NewListSeries(labels.FromStrings(labels.MetricName, "go_gc_duration_seconds", "quantile", "1"), []tsdbutil.Sample{
+			backfillSample{t: 1000, v: math.NaN()}, backfillSample{t: 2000, v: math.Inf(1)}, backfillSample{t: 3000, v: math.Float64frombits(value.StaleNaN)},
+		}),
+	}
+	if _, err := tsdb.CreateBlock(series, dir, 0, log.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+	adaptor := &remoteWriteAdaptor{}
+	receiver := NewProm(&config.Config{}, adaptor)
+	report, err := receiver.Backfill(context.Background(), []string{dir}, BackfillOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Samples != 2 {
+		t.Errorf("Backfill() got = %+v, want 2 samples", report)
+	}
+	// Only the stale marker is dropped
+	samples := backfillSamples(adaptor.cmds)["go_gc_duration_seconds"]
+	if len(samples) != 2 || !math.IsNaN(samples[0].Value) || value.IsStaleNaN(samples[0].Value) || !math.IsInf(samples[1].Value, 1) {
+		t.Errorf("Backfill() got = %v", samples)
+	}
+}
+
+func TestPromImpl_Backfill_TimeRange(t *testing.T) {
+	dir := createBackfillBlocks(t)
+	adaptor := &remoteWriteAdaptor{}
+	receiver := NewProm(&config.Config{}, adaptor)
+	start, end := time.UnixMilli(2000), time.UnixMilli(3000)
+	report, err := receiver.Backfill(context.Background(), []string{dir}, BackfillOptions{
+		Start:  &start,
+		End:    &end,
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReport := BackfillReport{
+		Blocks:        1,
+		SkippedBlocks: 1,
+		Series:        2,
+		Samples:       4,
+	}
+	if report != wantReport {
+		t.Errorf("Backfill() got = %+v, want %+v", report, wantReport)
+	}
+	if len(adaptor.cmds) > 0 {
+		t.Errorf("Backfill() wrote %v in dry-run mode", adaptor.cmds)
+	}
+}
+
+func TestPromImpl_Backfill_Checkpoint(t *testing.T) {
+	dir := createBackfillBlocks(t)
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	opts := BackfillOptions{
+		BatchSize:      2,
+		CheckpointFile: checkpoint,
+	}
+	// The first block is written in three batches, go_goroutines is completed by the second one and the third one fails
+	adaptor := &failingWriteAdaptor{succeeded: 2}
+	receiver := NewProm(&config.Config{}, adaptor)
+	if _, err := receiver.Backfill(context.Background(), []string{dir}, opts); err == nil {
+		t.Fatal("Backfill() error = nil, want error")
+	}
+	resumed := &remoteWriteAdaptor{}
+	receiver = NewProm(&config.Config{}, resumed)
+	report, err := receiver.Backfill(context.Background(), []string{dir}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Blocks != 2 || report.Series != 2 || report.Samples != 4 {
+		t.Errorf("Backfill() got = %+v", report)
+	}
+	// go_threads of the first block is not completed before the failure, so it is written again as a whole
+	want := map[string][]applications.Sample{
+		"go_goroutines": {{Timestamp: 7201000, Value: 4}},
+		"go_threads":    {{Timestamp: 1000, Value: 10}, {Timestamp: 2000, Value: 20}, {Timestamp: 3000, Value: 30}},
+	}
+	if got := backfillSamples(resumed.cmds); !reflect.DeepEqual(got, want) {
+		t.Errorf("Backfill() got = %v, want %v", got, want)
+	}
+	// Every block is completed now
+	resumed.cmds = nil
+	report, err = receiver.Backfill(context.Background(), []string{dir}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SkippedBlocks != 2 || len(resumed.cmds) > 0 {
+		t.Errorf("Backfill() got = %+v, wrote %v", report, resumed.cmds)
+	}
+	// The checkpoint doesn't apply to another time range
+	end := time.UnixMilli(3000)
+	opts.End = &end
+	if _, err = receiver.Backfill(context.Background(), []string{dir}, opts); err == nil {
+		t.Error("Backfill() error = nil, want error")
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// labelValuesCache caches results of label values queries in applications.LABEL_VALUES_ACCURATE mode.
+// Start and end time of the queries are aligned to window, so the queries of the same series selector and label name
+// issued within a window share one entry. Entries expire after window as points are still being written into the last window.
+// Zero window disables the cache.
+type labelValuesCache struct {
+	window  time.Duration
+	mu      sync.Mutex
+	entries map[labelValuesCacheKey]labelValuesCacheEntry
+}
+
+type labelValuesCacheKey struct {
+	cmd       string
+	labelName string
+	start     int64
+	end       int64
+}
+
+type labelValuesCacheEntry struct {
+	values   []string
+	expireAt time.Time
+}
+
+func newLabelValuesCache(window time.Duration) *labelValuesCache {
+	return &labelValuesCache{
+		window:  window,
+		entries: make(map[labelValuesCacheKey]labelValuesCacheEntry),
+	}
+}
+
+// align truncates start down and rounds end up to window, nil start is left unbounded
+func (receiver *labelValuesCache) align(start *time.Time, end time.Time) (*time.Time, time.Time) {
+	if receiver.window <= 0 {
+		return start, end
+	}
+	if start != nil {
+		aligned := start.Truncate(receiver.window)
+		start = &aligned
+	}
+	if aligned := end.Truncate(receiver.window); !aligned.Equal(end) {
+		end = aligned.Add(receiver.window)
+	}
+	return start, end
+}
+
+func (receiver *labelValuesCache) key(cmd string, labelName string, start *time.Time, end time.Time) labelValuesCacheKey {
+	key := labelValuesCacheKey{
+		cmd:       cmd,
+		labelName: labelName,
+		start:     math.MinInt64,
+		end:       end.UnixNano(),
+	}
+	if start != nil {
+		key.start = start.UnixNano()
+	}
+	return key
+}
+
+func (receiver *labelValuesCache) get(key labelValuesCacheKey) ([]string, bool) {
+	if receiver.window <= 0 {
+		return nil, false
+	}
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	entry, ok := receiver.entries[key]
+	if !ok || time.Now().After(entry.expireAt) {
+		return nil, false
+	}
+	return entry.values, true
+}
+
+// set puts values into the cache, and removes expired entries so that the cache doesn't grow with windows passing by
+func (receiver *labelValuesCache) set(key labelValuesCacheKey, values []string) {
+	if receiver.window <= 0 {
+		return
+	}
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	now := time.Now()
+	for k, entry := range receiver.entries {
+		if now.After(entry.expireAt) {
+			delete(receiver.entries, k)
+		}
+	}
+	receiver.entries[key] = labelValuesCacheEntry{
+		values:   values,
+		expireAt: now.Add(receiver.window),
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_labelValuesCache(t *testing.T) {
+	cache := newLabelValuesCache(time.Minute)
+	start := time.Date(2023, 1, 6, 4, 0, 30, 0, time.UTC)
+	end := time.Date(2023, 1, 6, 7, 0, 30, 0, time.UTC)
+	alignedStart, alignedEnd := cache.align(&start, end)
+	if !alignedStart.Equal(time.Date(2023, 1, 6, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("align() start = %v", alignedStart)
+	}
+	if !alignedEnd.Equal(time.Date(2023, 1, 6, 7, 1, 0, 0, time.UTC)) {
+		t.Errorf("align() end = %v", alignedEnd)
+	}
+	key := cache.key(`go_goroutines{job="prometheus"}`, "instance", alignedStart, alignedEnd)
+	if _, ok := cache.get(key); ok {
+		t.Errorf("get() hit before set")
+	}
+	cache.set(key, []string{"localhost:9090"})
+	values, ok := cache.get(key)
+	if !ok || len(values) != 1 || values[0] != "localhost:9090" {
+		t.Errorf("get() = %v, %v", values, ok)
+	}
+	if _, ok = cache.get(cache.key(`go_goroutines{job="prometheus"}`, "instance", nil, alignedEnd)); ok {
+		t.Errorf("get() hit for unbounded start")
+	}
+
+	disabled := newLabelValuesCache(0)
+	disabled.set(key, []string{"localhost:9090"})
+	if _, ok = disabled.get(key); ok {
+		t.Errorf("get() hit on disabled cache")
+	}
+}
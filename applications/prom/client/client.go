@@ -37,7 +37,7 @@ func (receiver *PromClient) SetProvider(provider registry.IServiceProvider) {
 func (receiver *PromClient) SetClient(client *resty.Client) {
 	receiver.client = client
 }
-func (receiver *PromClient) Query(ctx context.Context, _headers map[string]string, query string, time *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClient) Query(ctx context.Context, _headers map[string]string, query string, time *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	var _err error
 	_urlValues := url.Values{}
 	_req := receiver.client.R()
@@ -49,6 +49,9 @@ func (receiver *PromClient) Query(ctx context.Context, _headers map[string]strin
 	if time != nil {
 		_urlValues.Set("time", fmt.Sprintf("%v", *time))
 	}
+	if timezone != nil {
+		_urlValues.Set("timezone", fmt.Sprintf("%v", *timezone))
+	}
 	if timeout != nil {
 		_urlValues.Set("timeout", fmt.Sprintf("%v", *timeout))
 	}
@@ -77,7 +80,7 @@ func (receiver *PromClient) Query(ctx context.Context, _headers map[string]strin
 	}
 	return _resp, _result.Data, _result.Status, nil
 }
-func (receiver *PromClient) GetQuery(ctx context.Context, _headers map[string]string, query string, time *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClient) GetQuery(ctx context.Context, _headers map[string]string, query string, time *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	var _err error
 	_urlValues := url.Values{}
 	_req := receiver.client.R()
@@ -89,6 +92,9 @@ func (receiver *PromClient) GetQuery(ctx context.Context, _headers map[string]st
 	if time != nil {
 		_urlValues.Set("time", fmt.Sprintf("%v", *time))
 	}
+	if timezone != nil {
+		_urlValues.Set("timezone", fmt.Sprintf("%v", *timezone))
+	}
 	if timeout != nil {
 		_urlValues.Set("timeout", fmt.Sprintf("%v", *timeout))
 	}
@@ -113,7 +119,7 @@ func (receiver *PromClient) GetQuery(ctx context.Context, _headers map[string]st
 	}
 	return _resp, _result.Data, _result.Status, nil
 }
-func (receiver *PromClient) Query_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClient) Query_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	var _err error
 	_urlValues := url.Values{}
 	_req := receiver.client.R()
@@ -131,6 +137,12 @@ func (receiver *PromClient) Query_range(ctx context.Context, _headers map[string
 	if step != nil {
 		_urlValues.Set("step", fmt.Sprintf("%v", *step))
 	}
+	if fill != nil {
+		_urlValues.Set("fill", fmt.Sprintf("%v", *fill))
+	}
+	if timezone != nil {
+		_urlValues.Set("timezone", fmt.Sprintf("%v", *timezone))
+	}
 	if timeout != nil {
 		_urlValues.Set("timeout", fmt.Sprintf("%v", *timeout))
 	}
@@ -159,7 +171,7 @@ func (receiver *PromClient) Query_range(ctx context.Context, _headers map[string
 	}
 	return _resp, _result.Data, _result.Status, nil
 }
-func (receiver *PromClient) GetQuery_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClient) GetQuery_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	var _err error
 	_urlValues := url.Values{}
 	_req := receiver.client.R()
@@ -177,6 +189,12 @@ func (receiver *PromClient) GetQuery_range(ctx context.Context, _headers map[str
 	if step != nil {
 		_urlValues.Set("step", fmt.Sprintf("%v", *step))
 	}
+	if fill != nil {
+		_urlValues.Set("fill", fmt.Sprintf("%v", *fill))
+	}
+	if timezone != nil {
+		_urlValues.Set("timezone", fmt.Sprintf("%v", *timezone))
+	}
 	if timeout != nil {
 		_urlValues.Set("timeout", fmt.Sprintf("%v", *timeout))
 	}
@@ -242,6 +260,212 @@ func (receiver *PromClient) GetLabel_Label_nameValues(ctx context.Context, _head
 	}
 	return _resp, _result.Data, _result.Status, nil
 }
+func (receiver *PromClient) Series(ctx context.Context, _headers map[string]string, match []string, start *string, end *string) (_resp *resty.Response, data []map[string]string, status string, err error) {
+	var _err error
+	_urlValues := url.Values{}
+	_req := receiver.client.R()
+	if len(_headers) > 0 {
+		_req.SetHeaders(_headers)
+	}
+	_req.SetContext(ctx)
+	for _, _item := range match {
+		_urlValues.Add("match", fmt.Sprintf("%v", _item))
+	}
+	if start != nil {
+		_urlValues.Set("start", fmt.Sprintf("%v", *start))
+	}
+	if end != nil {
+		_urlValues.Set("end", fmt.Sprintf("%v", *end))
+	}
+	_path := "/series"
+	if _req.Body != nil {
+		_req.SetQueryParamsFromValues(_urlValues)
+	} else {
+		_req.SetFormDataFromValues(_urlValues)
+	}
+	_resp, _err = _req.Post(_path)
+	if _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	if _resp.IsError() {
+		err = errors.New(_resp.String())
+		return
+	}
+	var _result struct {
+		Data   []map[string]string `json:"data"`
+		Status string              `json:"status"`
+	}
+	if _err = json.Unmarshal(_resp.Body(), &_result); _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	return _resp, _result.Data, _result.Status, nil
+}
+func (receiver *PromClient) GetSeries(ctx context.Context, _headers map[string]string, match []string, start *string, end *string) (_resp *resty.Response, data []map[string]string, status string, err error) {
+	var _err error
+	_urlValues := url.Values{}
+	_req := receiver.client.R()
+	if len(_headers) > 0 {
+		_req.SetHeaders(_headers)
+	}
+	_req.SetContext(ctx)
+	for _, _item := range match {
+		_urlValues.Add("match", fmt.Sprintf("%v", _item))
+	}
+	if start != nil {
+		_urlValues.Set("start", fmt.Sprintf("%v", *start))
+	}
+	if end != nil {
+		_urlValues.Set("end", fmt.Sprintf("%v", *end))
+	}
+	_path := "/series"
+	_req.SetQueryParamsFromValues(_urlValues)
+	_resp, _err = _req.Get(_path)
+	if _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	if _resp.IsError() {
+		err = errors.New(_resp.String())
+		return
+	}
+	var _result struct {
+		Data   []map[string]string `json:"data"`
+		Status string              `json:"status"`
+	}
+	if _err = json.Unmarshal(_resp.Body(), &_result); _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	return _resp, _result.Data, _result.Status, nil
+}
+func (receiver *PromClient) Labels(ctx context.Context, _headers map[string]string, match *[]string, start *string, end *string, limit *int) (_resp *resty.Response, data []string, status string, err error) {
+	var _err error
+	_urlValues := url.Values{}
+	_req := receiver.client.R()
+	if len(_headers) > 0 {
+		_req.SetHeaders(_headers)
+	}
+	_req.SetContext(ctx)
+	if match != nil {
+		for _, _item := range *match {
+			_urlValues.Add("match", fmt.Sprintf("%v", _item))
+		}
+	}
+	if start != nil {
+		_urlValues.Set("start", fmt.Sprintf("%v", *start))
+	}
+	if end != nil {
+		_urlValues.Set("end", fmt.Sprintf("%v", *end))
+	}
+	if limit != nil {
+		_urlValues.Set("limit", fmt.Sprintf("%v", *limit))
+	}
+	_path := "/labels"
+	if _req.Body != nil {
+		_req.SetQueryParamsFromValues(_urlValues)
+	} else {
+		_req.SetFormDataFromValues(_urlValues)
+	}
+	_resp, _err = _req.Post(_path)
+	if _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	if _resp.IsError() {
+		err = errors.New(_resp.String())
+		return
+	}
+	var _result struct {
+		Data   []string `json:"data"`
+		Status string   `json:"status"`
+	}
+	if _err = json.Unmarshal(_resp.Body(), &_result); _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	return _resp, _result.Data, _result.Status, nil
+}
+func (receiver *PromClient) GetLabels(ctx context.Context, _headers map[string]string, match *[]string, start *string, end *string, limit *int) (_resp *resty.Response, data []string, status string, err error) {
+	var _err error
+	_urlValues := url.Values{}
+	_req := receiver.client.R()
+	if len(_headers) > 0 {
+		_req.SetHeaders(_headers)
+	}
+	_req.SetContext(ctx)
+	if match != nil {
+		for _, _item := range *match {
+			_urlValues.Add("match", fmt.Sprintf("%v", _item))
+		}
+	}
+	if start != nil {
+		_urlValues.Set("start", fmt.Sprintf("%v", *start))
+	}
+	if end != nil {
+		_urlValues.Set("end", fmt.Sprintf("%v", *end))
+	}
+	if limit != nil {
+		_urlValues.Set("limit", fmt.Sprintf("%v", *limit))
+	}
+	_path := "/labels"
+	_req.SetQueryParamsFromValues(_urlValues)
+	_resp, _err = _req.Get(_path)
+	if _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	if _resp.IsError() {
+		err = errors.New(_resp.String())
+		return
+	}
+	var _result struct {
+		Data   []string `json:"data"`
+		Status string   `json:"status"`
+	}
+	if _err = json.Unmarshal(_resp.Body(), &_result); _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	return _resp, _result.Data, _result.Status, nil
+}
+
+func (receiver *PromClient) GetMetadata(ctx context.Context, _headers map[string]string, limit *int, metric *string) (_resp *resty.Response, data map[string][]dto.MetricMetadata, status string, err error) {
+	var _err error
+	_urlValues := url.Values{}
+	_req := receiver.client.R()
+	if len(_headers) > 0 {
+		_req.SetHeaders(_headers)
+	}
+	_req.SetContext(ctx)
+	if limit != nil {
+		_urlValues.Set("limit", fmt.Sprintf("%v", *limit))
+	}
+	if metric != nil {
+		_urlValues.Set("metric", fmt.Sprintf("%v", *metric))
+	}
+	_path := "/metadata"
+	_req.SetQueryParamsFromValues(_urlValues)
+	_resp, _err = _req.Get(_path)
+	if _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	if _resp.IsError() {
+		err = errors.New(_resp.String())
+		return
+	}
+	var _result struct {
+		Data   map[string][]dto.MetricMetadata `json:"data"`
+		Status string                          `json:"status"`
+	}
+	if _err = json.Unmarshal(_resp.Body(), &_result); _err != nil {
+		err = errors.Wrap(_err, "error")
+		return
+	}
+	return _resp, _result.Data, _result.Status, nil
+}
 
 func NewPromClient(opts ...restclient.RestClientOption) *PromClient {
 	defaultProvider := restclient.NewServiceProvider("PROM")
@@ -28,13 +28,14 @@ type PromClientProxy struct {
 	runner goresilience.Runner
 }
 
-func (receiver *PromClientProxy) Query(ctx context.Context, _headers map[string]string, query string, time *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClientProxy) Query(ctx context.Context, _headers map[string]string, query string, time *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
 		_resp, data, status, err = receiver.client.Query(
 			ctx,
 			_headers,
 			query,
 			time,
+			timezone,
 			timeout,
 		)
 		if err != nil {
@@ -50,13 +51,14 @@ func (receiver *PromClientProxy) Query(ctx context.Context, _headers map[string]
 	}
 	return
 }
-func (receiver *PromClientProxy) GetQuery(ctx context.Context, _headers map[string]string, query string, time *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClientProxy) GetQuery(ctx context.Context, _headers map[string]string, query string, time *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
 		_resp, data, status, err = receiver.client.GetQuery(
 			ctx,
 			_headers,
 			query,
 			time,
+			timezone,
 			timeout,
 		)
 		if err != nil {
@@ -72,7 +74,7 @@ func (receiver *PromClientProxy) GetQuery(ctx context.Context, _headers map[stri
 	}
 	return
 }
-func (receiver *PromClientProxy) Query_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClientProxy) Query_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
 		_resp, data, status, err = receiver.client.Query_range(
 			ctx,
@@ -81,6 +83,8 @@ func (receiver *PromClientProxy) Query_range(ctx context.Context, _headers map[s
 			start,
 			end,
 			step,
+			fill,
+			timezone,
 			timeout,
 		)
 		if err != nil {
@@ -96,7 +100,7 @@ func (receiver *PromClientProxy) Query_range(ctx context.Context, _headers map[s
 	}
 	return
 }
-func (receiver *PromClientProxy) GetQuery_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
+func (receiver *PromClientProxy) GetQuery_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error) {
 	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
 		_resp, data, status, err = receiver.client.GetQuery_range(
 			ctx,
@@ -105,6 +109,8 @@ func (receiver *PromClientProxy) GetQuery_range(ctx context.Context, _headers ma
 			start,
 			end,
 			step,
+			fill,
+			timezone,
 			timeout,
 		)
 		if err != nil {
@@ -143,6 +149,118 @@ func (receiver *PromClientProxy) GetLabel_Label_nameValues(ctx context.Context,
 	}
 	return
 }
+func (receiver *PromClientProxy) Series(ctx context.Context, _headers map[string]string, match []string, start *string, end *string) (_resp *resty.Response, data []map[string]string, status string, err error) {
+	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
+		_resp, data, status, err = receiver.client.Series(
+			ctx,
+			_headers,
+			match,
+			start,
+			end,
+		)
+		if err != nil {
+			return errors.Wrap(err, "call Series fail")
+		}
+		return nil
+	}); _err != nil {
+		// you can implement your fallback logic here
+		if errors.Is(_err, rerrors.ErrCircuitOpen) {
+			receiver.logger.Error().Err(_err).Msg("")
+		}
+		err = errors.Wrap(_err, "call Series fail")
+	}
+	return
+}
+func (receiver *PromClientProxy) GetSeries(ctx context.Context, _headers map[string]string, match []string, start *string, end *string) (_resp *resty.Response, data []map[string]string, status string, err error) {
+	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
+		_resp, data, status, err = receiver.client.GetSeries(
+			ctx,
+			_headers,
+			match,
+			start,
+			end,
+		)
+		if err != nil {
+			return errors.Wrap(err, "call GetSeries fail")
+		}
+		return nil
+	}); _err != nil {
+		// you can implement your fallback logic here
+		if errors.Is(_err, rerrors.ErrCircuitOpen) {
+			receiver.logger.Error().Err(_err).Msg("")
+		}
+		err = errors.Wrap(_err, "call GetSeries fail")
+	}
+	return
+}
+func (receiver *PromClientProxy) Labels(ctx context.Context, _headers map[string]string, match *[]string, start *string, end *string, limit *int) (_resp *resty.Response, data []string, status string, err error) {
+	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
+		_resp, data, status, err = receiver.client.Labels(
+			ctx,
+			_headers,
+			match,
+			start,
+			end,
+			limit,
+		)
+		if err != nil {
+			return errors.Wrap(err, "call Labels fail")
+		}
+		return nil
+	}); _err != nil {
+		// you can implement your fallback logic here
+		if errors.Is(_err, rerrors.ErrCircuitOpen) {
+			receiver.logger.Error().Err(_err).Msg("")
+		}
+		err = errors.Wrap(_err, "call Labels fail")
+	}
+	return
+}
+func (receiver *PromClientProxy) GetLabels(ctx context.Context, _headers map[string]string, match *[]string, start *string, end *string, limit *int) (_resp *resty.Response, data []string, status string, err error) {
+	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
+		_resp, data, status, err = receiver.client.GetLabels(
+			ctx,
+			_headers,
+			match,
+			start,
+			end,
+			limit,
+		)
+		if err != nil {
+			return errors.Wrap(err, "call GetLabels fail")
+		}
+		return nil
+	}); _err != nil {
+		// you can implement your fallback logic here
+		if errors.Is(_err, rerrors.ErrCircuitOpen) {
+			receiver.logger.Error().Err(_err).Msg("")
+		}
+		err = errors.Wrap(_err, "call GetLabels fail")
+	}
+	return
+}
+
+func (receiver *PromClientProxy) GetMetadata(ctx context.Context, _headers map[string]string, limit *int, metric *string) (_resp *resty.Response, data map[string][]dto.MetricMetadata, status string, err error) {
+	if _err := receiver.runner.Run(ctx, func(ctx context.Context) error {
+		_resp, data, status, err = receiver.client.GetMetadata(
+			ctx,
+			_headers,
+			limit,
+			metric,
+		)
+		if err != nil {
+			return errors.Wrap(err, "call GetMetadata fail")
+		}
+		return nil
+	}); _err != nil {
+		// you can implement your fallback logic here
+		if errors.Is(_err, rerrors.ErrCircuitOpen) {
+			receiver.logger.Error().Err(_err).Msg("")
+		}
+		err = errors.Wrap(_err, "call GetMetadata fail")
+	}
+	return
+}
 
 type ProxyOption func(*PromClientProxy)
 
@@ -12,9 +12,14 @@ import (
 )
 
 type IPromClient interface {
-	Query(ctx context.Context, _headers map[string]string, query string, time *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
-	GetQuery(ctx context.Context, _headers map[string]string, query string, time *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
-	Query_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
-	GetQuery_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
+	Query(ctx context.Context, _headers map[string]string, query string, time *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
+	GetQuery(ctx context.Context, _headers map[string]string, query string, time *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
+	Query_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
+	GetQuery_range(ctx context.Context, _headers map[string]string, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (_resp *resty.Response, data dto.QueryData, status string, err error)
 	GetLabel_Label_nameValues(ctx context.Context, _headers map[string]string, start *string, end *string, match *[]string, label_name string) (_resp *resty.Response, data []string, status string, err error)
+	Series(ctx context.Context, _headers map[string]string, match []string, start *string, end *string) (_resp *resty.Response, data []map[string]string, status string, err error)
+	GetSeries(ctx context.Context, _headers map[string]string, match []string, start *string, end *string) (_resp *resty.Response, data []map[string]string, status string, err error)
+	Labels(ctx context.Context, _headers map[string]string, match *[]string, start *string, end *string, limit *int) (_resp *resty.Response, data []string, status string, err error)
+	GetLabels(ctx context.Context, _headers map[string]string, match *[]string, start *string, end *string, limit *int) (_resp *resty.Response, data []string, status string, err error)
+	GetMetadata(ctx context.Context, _headers map[string]string, limit *int, metric *string) (_resp *resty.Response, data map[string][]dto.MetricMetadata, status string, err error)
 }
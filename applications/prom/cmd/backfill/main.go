@@ -0,0 +1,116 @@
+// Command backfill writes the history of local Prometheus servers from their TSDB blocks to InfluxDB,
+// in the layout of the schema configured by BIZ_ADAPTOR_SCHEMA like the service does. InfluxDB connection is configured
+// by the same environment variables as the service. Usage:
+//
+//	backfill [flags] <block or data directory>...
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/pkg/errors"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/adaptors/prom"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+func main() {
+	var (
+		start      = flag.String("start", "", "only backfill samples at or after start, in RFC3339 or unix seconds")
+		end        = flag.String("end", "", "only backfill samples at or before end, in RFC3339 or unix seconds")
+		batchSize  = flag.Int("batch-size", 10000, "number of samples of a single write, the checkpoint is saved after every write")
+		checkpoint = flag.String("checkpoint", "", "file to save progress to and resume from, empty means no checkpoint")
+		dryRun     = flag.Bool("dry-run", false, "only report blocks, series and samples to be written")
+		database   = flag.String("database", "", "InfluxDB database to write to, default is BIZ_ADAPTOR_INFLUX_DATABASE")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <block or data directory>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts := service.BackfillOptions{
+		BatchSize:      *batchSize,
+		CheckpointFile: *checkpoint,
+		DryRun:         *dryRun,
+	}
+	var err error
+	if opts.Start, err = parseTimeFlag(*start); err != nil {
+		zlogger.Fatal().Err(err).Msg("invalid start")
+	}
+	if opts.End, err = parseTimeFlag(*end); err != nil {
+		zlogger.Fatal().Err(err).Msg("invalid end")
+	}
+
+	conf := config.LoadFromEnv()
+	if *database != "" {
+		conf.BizConf.AdaptorInfluxDatabase = *database
+	}
+
+	influxClient, err := client.NewHTTPClient(client.HTTPConfig{
+		Addr:      conf.BizConf.AdaptorInfluxAddr,
+		Username:  conf.BizConf.AdaptorInfluxUsername,
+		Password:  conf.BizConf.AdaptorInfluxPassword,
+		UserAgent: "promql2influxql",
+		Timeout:   conf.BizConf.AdaptorInfluxClientTimeout,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer influxClient.Close()
+
+	schemaMapper, err := schema.NewMapper(conf.BizConf.AdaptorSchema, conf.BizConf.AdaptorSchemaMeasurement)
+	if err != nil {
+		panic(err)
+	}
+
+	adaptor := prom.NewInfluxDBAdaptor(prom.InfluxDBAdaptorConfig{
+		Timeout:           conf.BizConf.AdaptorTimeout,
+		Verbose:           conf.BizConf.AdaptorVerbose,
+		SchemaMapper:      schemaMapper,
+		WriteBatchSize:    conf.BizConf.AdaptorRemoteWriteBatchSize,
+		WriteMaxRetries:   conf.BizConf.AdaptorRemoteWriteMaxRetries,
+		WriteRetryBackoff: conf.BizConf.AdaptorRemoteWriteRetryBackoff,
+	}, influxClient)
+
+	svc := service.NewProm(conf, adaptor)
+
+	// Interrupted backfill resumes from the checkpoint saved after the last write
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	report, err := svc.Backfill(ctx, flag.Args(), opts)
+	fmt.Printf("blocks: %d, skipped blocks: %d, series: %d, samples: %d, skipped native histogram samples: %d\n",
+		report.Blocks, report.SkippedBlocks, report.Series, report.Samples, report.HistogramSamples)
+	if err != nil {
+		zlogger.Error().Err(err).Msg("backfill failed")
+		os.Exit(1)
+	}
+}
+
+// parseTimeFlag parses RFC3339 or unix seconds like the time parameters of the HTTP API, empty value means unbounded
+func parseTimeFlag(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		t := time.UnixMilli(int64(seconds * 1000))
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, errors.Errorf("cannot parse %q to a valid timestamp", value)
+	}
+	return &t, nil
+}
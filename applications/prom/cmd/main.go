@@ -5,11 +5,15 @@
 package main
 
 import (
+	"context"
 	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/unionj-cloud/go-doudou/v2/framework/grpcx"
 	"github.com/unionj-cloud/go-doudou/v2/framework/rest"
 	"github.com/wubin1989/promql2influxql/adaptors/prom"
+	"github.com/wubin1989/promql2influxql/adaptors/prom/influxdb/schema"
 	service "github.com/wubin1989/promql2influxql/applications/prom"
 	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	pb "github.com/wubin1989/promql2influxql/applications/prom/transport/grpc"
 	"github.com/wubin1989/promql2influxql/applications/prom/transport/httpsrv"
 )
 
@@ -32,14 +36,60 @@ func main() {
 	}
 	defer influxClient.Close()
 
+	schemaMapper, err := schema.NewMapper(conf.BizConf.AdaptorSchema, conf.BizConf.AdaptorSchemaMeasurement)
+	if err != nil {
+		panic(err)
+	}
+
 	adaptor := prom.NewInfluxDBAdaptor(prom.InfluxDBAdaptorConfig{
-		Timeout: conf.BizConf.AdaptorTimeout,
-		Verbose: conf.BizConf.AdaptorVerbose,
+		Timeout:           conf.BizConf.AdaptorTimeout,
+		Verbose:           conf.BizConf.AdaptorVerbose,
+		SchemaMapper:      schemaMapper,
+		ChunkSize:         conf.BizConf.AdaptorInfluxChunkSize,
+		WriteBatchSize:    conf.BizConf.AdaptorRemoteWriteBatchSize,
+		WriteMaxRetries:   conf.BizConf.AdaptorRemoteWriteMaxRetries,
+		WriteRetryBackoff: conf.BizConf.AdaptorRemoteWriteRetryBackoff,
 	}, influxClient)
 
 	svc := service.NewProm(conf, adaptor)
+
+	ruleGroups, err := service.LoadRuleFiles(conf.BizConf.AdaptorRuleFiles)
+	if err != nil {
+		panic(err)
+	}
+	ruleManager, err := service.NewRuleManager(svc, ruleGroups,
+		service.NewAlertmanagerNotifier(conf.BizConf.AdaptorAlertmanagerUrl, conf.BizConf.AdaptorAlertmanagerTimeout))
+	if err != nil {
+		panic(err)
+	}
+	// rules are evaluated until the http server is shut down, so that in-flight evaluations are canceled
+	ruleCtx, stopRules := context.WithCancel(context.Background())
+	rulesDone := make(chan struct{})
+	go func() {
+		defer close(rulesDone)
+		ruleManager.Run(ruleCtx)
+	}()
+
+	grpcServer := grpcx.NewGrpcServer()
+	pb.RegisterPromServiceServer(grpcServer, svc)
+	go grpcServer.Run()
+
 	handler := httpsrv.NewPromHandler(svc)
 	srv := rest.NewRestServer()
+	srv.AddMiddleware(httpsrv.Timezone)
+	srv.AddMiddleware(httpsrv.Federate(httpsrv.NewFederateHandler(svc)))
 	srv.AddRoute(httpsrv.Routes(handler)...)
+	remoteReadHandler := httpsrv.NewRemoteReadHandler(svc, conf.BizConf.AdaptorRemoteReadSampleLimit, conf.BizConf.AdaptorRemoteReadMaxBytesInFrame)
+	srv.AddRoute(httpsrv.RemoteReadRoutes(remoteReadHandler)...)
+	remoteWriteHandler := httpsrv.NewRemoteWriteHandler(svc)
+	srv.AddRoute(httpsrv.RemoteWriteRoutes(remoteWriteHandler)...)
+	otlpHandler := httpsrv.NewOTLPHandler(svc)
+	srv.AddRoute(httpsrv.OTLPRoutes(otlpHandler)...)
+	exportHandler := httpsrv.NewExportHandler(svc, conf.BizConf.AdaptorImportMaxBodyBytes)
+	srv.AddRoute(httpsrv.ExportRoutes(exportHandler)...)
+	rulesHandler := httpsrv.NewRulesHandler(ruleManager)
+	srv.AddRoute(httpsrv.RulesRoutes(rulesHandler)...)
 	srv.Run()
+	stopRules()
+	<-rulesDone
 }
@@ -6,13 +6,19 @@ package config
 
 import (
 	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"gopkg.in/yaml.v3"
+	"os"
 	"time"
 )
 
 type Config struct {
 	DbConf  DbConfig
 	BizConf BizConfig
+	// Metadata is loaded from the file set by BIZ_ADAPTOR_METADATA_FILE, keyed by metric family name
+	Metadata map[string]MetricMetadata
 }
 
 type DbConfig struct {
@@ -26,13 +32,63 @@ type DbConfig struct {
 }
 
 type BizConfig struct {
-	AdaptorTimeout             time.Duration `split_words:"true"`
-	AdaptorVerbose             bool          `split_words:"true"`
-	AdaptorInfluxAddr          string        `split_words:"true"`
-	AdaptorInfluxUsername      string        `split_words:"true"`
-	AdaptorInfluxPassword      string        `split_words:"true"`
-	AdaptorInfluxClientTimeout time.Duration `split_words:"true"`
-	AdaptorInfluxDatabase      string        `split_words:"true"`
+	AdaptorTimeout                   time.Duration `split_words:"true"`
+	AdaptorVerbose                   bool          `split_words:"true"`
+	AdaptorInfluxAddr                string        `split_words:"true"`
+	AdaptorInfluxUsername            string        `split_words:"true"`
+	AdaptorInfluxPassword            string        `split_words:"true"`
+	AdaptorInfluxClientTimeout       time.Duration `split_words:"true"`
+	AdaptorInfluxDatabase            string        `split_words:"true"`
+	AdaptorInfluxChunkSize           int           `split_words:"true"`
+	AdaptorSchema                    string        `split_words:"true" default:"prom_write"`
+	AdaptorSchemaMeasurement         string        `split_words:"true"`
+	AdaptorFill                      string        `split_words:"true" default:"none"`
+	AdaptorRateMode                  string        `split_words:"true" default:"approximate"`
+	AdaptorLabelValuesMode           string        `split_words:"true" default:"index"`
+	AdaptorLabelValuesWindow         time.Duration `split_words:"true" default:"1m"`
+	AdaptorTimezone                  string        `split_words:"true"`
+	AdaptorMetadataFile              string        `split_words:"true"`
+	AdaptorRemoteReadSampleLimit     int           `split_words:"true" default:"50000000"`
+	AdaptorRemoteReadMaxBytesInFrame int           `split_words:"true" default:"1048576"`
+	AdaptorRemoteWriteBatchSize      int           `split_words:"true" default:"5000"`
+	AdaptorRemoteWriteMaxRetries     int           `split_words:"true" default:"3"`
+	AdaptorRemoteWriteRetryBackoff   time.Duration `split_words:"true" default:"100ms"`
+	AdaptorFederateLookbackDelta     time.Duration `split_words:"true" default:"5m"`
+	AdaptorImportMaxBodyBytes        int64         `split_words:"true" default:"268435456"`
+	AdaptorRuleFiles                 []string      `split_words:"true"`
+	AdaptorRuleEvaluationInterval    time.Duration `split_words:"true" default:"1m"`
+	AdaptorRuleLookbackDelta         time.Duration `split_words:"true" default:"5m"`
+	AdaptorAlertmanagerUrl           string        `split_words:"true"`
+	AdaptorAlertmanagerTimeout       time.Duration `split_words:"true" default:"10s"`
+}
+
+// MetricMetadata is metadata of a metric family supplied by operators. Non-empty fields override the inferred ones.
+type MetricMetadata struct {
+	Type string `yaml:"type"`
+	Help string `yaml:"help"`
+	Unit string `yaml:"unit"`
+}
+
+// LoadMetadataFile loads metric metadata from a YAML file mapping metric family names to MetricMetadata, for example:
+//
+//	http_requests_total:
+//	  help: Total number of HTTP requests.
+//	  unit: requests
+//
+// Empty file name means no metadata is supplied.
+func LoadMetadataFile(file string) (map[string]MetricMetadata, error) {
+	if stringutils.IsEmpty(file) {
+		return nil, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to read metadata file")
+	}
+	var metadata map[string]MetricMetadata
+	if err = yaml.Unmarshal(content, &metadata); err != nil {
+		return nil, errors.Wrapf(err, "fail to parse metadata file %s", file)
+	}
+	return metadata, nil
 }
 
 func LoadFromEnv() *Config {
@@ -46,8 +102,13 @@ func LoadFromEnv() *Config {
 	if err != nil {
 		zlogger.Panic().Err(err).Msg("Error processing env")
 	}
+	metadata, err := LoadMetadataFile(bizconf.AdaptorMetadataFile)
+	if err != nil {
+		zlogger.Panic().Err(err).Msg("Error loading metadata file")
+	}
 	return &Config{
-		DbConf:  dbconf,
-		BizConf: bizconf,
+		DbConf:   dbconf,
+		BizConf:  bizconf,
+		Metadata: metadata,
 	}
 }
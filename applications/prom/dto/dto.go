@@ -4,6 +4,8 @@
  */
 package dto
 
+import "time"
+
 //go:generate go-doudou name --file $GOFILE
 
 type QueryData struct {
@@ -15,3 +17,67 @@ type QueryResponse struct {
 	Data   QueryData `json:"data"`
 	Status string    `json:"status"`
 }
+
+type MetricMetadata struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+// RuleDiscovery is the data of /api/v1/rules
+type RuleDiscovery struct {
+	Groups []RuleGroup `json:"groups"`
+}
+
+// RuleGroup is the state of a rule group. Rules are either AlertingRule or RecordingRule.
+type RuleGroup struct {
+	Name           string        `json:"name"`
+	File           string        `json:"file"`
+	Rules          []interface{} `json:"rules"`
+	Interval       float64       `json:"interval"`
+	Limit          int           `json:"limit"`
+	EvaluationTime float64       `json:"evaluationTime"`
+	LastEvaluation time.Time     `json:"lastEvaluation"`
+}
+
+type AlertingRule struct {
+	// State is the highest state of the alerts of the rule, one of inactive, pending and firing
+	State          string            `json:"state"`
+	Name           string            `json:"name"`
+	Query          string            `json:"query"`
+	Duration       float64           `json:"duration"`
+	KeepFiringFor  float64           `json:"keepFiringFor"`
+	Labels         map[string]string `json:"labels"`
+	Annotations    map[string]string `json:"annotations"`
+	Alerts         []Alert           `json:"alerts"`
+	Health         string            `json:"health"`
+	LastError      string            `json:"lastError,omitempty"`
+	EvaluationTime float64           `json:"evaluationTime"`
+	LastEvaluation time.Time         `json:"lastEvaluation"`
+	Type           string            `json:"type"`
+}
+
+type RecordingRule struct {
+	Name           string            `json:"name"`
+	Query          string            `json:"query"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	Health         string            `json:"health"`
+	LastError      string            `json:"lastError,omitempty"`
+	EvaluationTime float64           `json:"evaluationTime"`
+	LastEvaluation time.Time         `json:"lastEvaluation"`
+	Type           string            `json:"type"`
+}
+
+// AlertDiscovery is the data of /api/v1/alerts
+type AlertDiscovery struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+type Alert struct {
+	Labels          map[string]string `json:"labels"`
+	Annotations     map[string]string `json:"annotations"`
+	State           string            `json:"state"`
+	ActiveAt        *time.Time        `json:"activeAt,omitempty"`
+	KeepFiringSince *time.Time        `json:"keepFiringSince,omitempty"`
+	Value           string            `json:"value"`
+}
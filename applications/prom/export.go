@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
+	"github.com/wubin1989/promql2influxql/applications"
+	"io"
+	"time"
+)
+
+const (
+	EXPORT_FORMAT_JSON_LINES  = "jsonl"
+	EXPORT_FORMAT_OPENMETRICS = "openmetrics"
+	EXPORT_FORMAT_CSV         = "csv"
+
+	// importBatchSamples is the number of samples decoded before they are written in a single write command
+	importBatchSamples = 10000
+)
+
+// ErrBadRequest is wrapped by errors of Exporter caused by invalid parameters or request bodies
+var ErrBadRequest = errors.New("bad request")
+
+// Exporter exports and imports raw samples of series. It is not a part of Prom interface,
+// because request and response bodies are streamed in the formats below rather than forms and JSON:
+//   - EXPORT_FORMAT_JSON_LINES: a JSON object per line like {"metric":{"__name__":"up","job":"prometheus"},"values":[1,1],"timestamps":[1000,2000]},
+//     timestamps are in milliseconds, and a series may be split across several lines
+//   - EXPORT_FORMAT_OPENMETRICS: OpenMetrics text format like up{job="prometheus"} 1 1.5 ending with # EOF,
+//     a sample per line and timestamps are in seconds, metric types are not exported
+//   - EXPORT_FORMAT_CSV: a header line series,timestamp,value followed by a sample per line like "up{job=""prometheus""}",1500,1,
+//     timestamps are in milliseconds
+type Exporter interface {
+	// Export writes raw samples of the series selected by match between start and end to w in format, metric by metric
+	// as soon as they are read from the database. Errors wrapping ErrBadRequest are returned before anything is written.
+	Export(ctx context.Context, w io.Writer, format string, match []string, start *string, end *string) error
+	// Import reads samples in format from r and writes them, and returns the number of imported samples.
+	// Samples decoded before an error are still written.
+	Import(ctx context.Context, r io.Reader, format string) (int, error)
+}
+
+var _ Exporter = (*PromImpl)(nil)
+
+// Export transpiles every metric name selected by match to a vector selector like remote read does, and queries its raw samples
+// by QueryStream of the adaptor, so the whole result is never held in memory. Series selected by more than one match[] are exported
+// once, so only their label sets are kept in memory. Start is left unbounded if it is omitted, and end defaults to now.
+func (receiver *PromImpl) Export(ctx context.Context, w io.Writer, format string, match []string, start *string, end *string) error {
+	if len(match) == 0 {
+		return errors.Wrap(ErrBadRequest, "no match[] parameter provided")
+	}
+	matcherSets, err := parseMatchersParam(&match)
+	if err != nil {
+		return errors.Wrap(ErrBadRequest, err.Error())
+	}
+	var startTime *time.Time
+	if start != nil {
+		parsed, err := parseTimeParam("start", start, minTime)
+		if err != nil {
+			return errors.Wrapf(ErrBadRequest, "invalid start: %q", *start)
+		}
+		startTime = &parsed
+	}
+	endTime, err := parseTimeParam("end", end, time.Now())
+	if err != nil {
+		return errors.Wrapf(ErrBadRequest, "invalid end: %q", *end)
+	}
+	encoder, err := newExportEncoder(w, format)
+	if err != nil {
+		return errors.Wrap(ErrBadRequest, err.Error())
+	}
+	location, err := parseTimezoneParam(ctx, nil, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	// queried are the selectors already queried, and exported are the series exported by them. A series may be split across
+	// several results of the same query, so series of a query are only added to exported after the query is done.
+	queried := make(map[string]struct{})
+	exported := make(map[string]struct{})
+	for _, matchers := range matcherSets {
+		metricNames, err := receiver.selectedMetricNames(ctx, matchers)
+		if err != nil {
+			return errors.Wrap(err, caller.NewCaller().String())
+		}
+		var labelMatchers []*labels.Matcher
+		for _, item := range matchers {
+			if item.Name != labels.MetricName {
+				labelMatchers = append(labelMatchers, item)
+			}
+		}
+		for _, metricName := range metricNames {
+			selector := (&parser.VectorSelector{
+				Name:          metricName,
+				LabelMatchers: labelMatchers,
+			}).String()
+			if _, exists := queried[selector]; exists {
+				continue
+			}
+			queried[selector] = struct{}{}
+			current := make(map[string]struct{})
+			err = receiver.adaptor.QueryStream(ctx, applications.PromCommand{
+				Cmd:       selector,
+				Database:  receiver.conf.BizConf.AdaptorInfluxDatabase,
+				Start:     startTime,
+				End:       &endTime,
+				Timezone:  location,
+				QueryType: applications.RANGE_QUERY,
+				DataType:  applications.TABLE_DATA,
+			}, func(result applications.RunResult) error {
+				matrix, _ := result.Result.(promql.Matrix)
+				selected := make(promql.Matrix, 0, len(matrix))
+				for _, series := range matrix {
+					key := series.Metric.String()
+					if _, exists := exported[key]; exists {
+						continue
+					}
+					current[key] = struct{}{}
+					selected = append(selected, series)
+				}
+				return encoder.Encode(selected)
+			})
+			if err != nil {
+				return errors.Wrap(err, caller.NewCaller().String())
+			}
+			for key := range current {
+				exported[key] = struct{}{}
+			}
+		}
+	}
+	if err = encoder.Close(); err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
+}
+
+// Import decodes samples and writes them every importBatchSamples samples, so the whole body is never held in memory
+// except for EXPORT_FORMAT_OPENMETRICS which is read at once, so callers should limit the size of r.
+func (receiver *PromImpl) Import(ctx context.Context, r io.Reader, format string) (int, error) {
+	decoder, err := newImportDecoder(r, format)
+	if err != nil {
+		return 0, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	var (
+		batch    []applications.TimeSeries
+		samples  int
+		imported int
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := receiver.write(ctx, batch); err != nil {
+			return errors.Wrap(err, caller.NewCaller().String())
+		}
+		imported += samples
+		batch, samples = nil, 0
+		return nil
+	}
+	for {
+		series, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if flushErr := flush(); flushErr != nil {
+				return imported, flushErr
+			}
+			return imported, errors.Wrap(ErrBadRequest, err.Error())
+		}
+		batch = append(batch, series)
+		if samples += len(series.Samples); samples >= importBatchSamples {
+			if err = flush(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	if err = flush(); err != nil {
+		return imported, err
+	}
+	return imported, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// exportAdaptor streams raw samples of export and records write commands of import
+type exportAdaptor struct {
+	remoteWriteAdaptor
+	streamCmds []applications.PromCommand
+}
+
+func (receiver *exportAdaptor) QueryStream(ctx context.Context, cmd applications.PromCommand, send func(result applications.RunResult) error) error {
+	receiver.streamCmds = append(receiver.streamCmds, cmd)
+	return send(applications.RunResult{
+		Result: promql.Matrix{
+			{
+				Metric: labels.FromStrings(labels.MetricName, "go_goroutines", "job", `pro"me\theus`),
+				Points: []promql.Point{{T: 1001, V: 1}, {T: 2000, V: math.NaN()}, {T: 3000, V: 2.5}},
+			},
+		},
+	})
+}
+
+func Test_exportFormats(t *testing.T) {
+	matrix := promql.Matrix{
+		{
+			Metric: labels.FromStrings(labels.MetricName, "go_goroutines", "job", `pro"me\theus`),
+			Points: []promql.Point{{T: 1001, V: 1}, {T: 3000, V: 2.5}},
+		},
+	}
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "",
+			format: EXPORT_FORMAT_JSON_LINES,
+			want:   `{"metric":{"__name__":"go_goroutines","job":"pro\"me\\theus"},"values":[1,2.5],"timestamps":[1001,3000]}` + "\n",
+		},
+		{
+			name:   "",
+			format: EXPORT_FORMAT_OPENMETRICS,
+			want:   "go_goroutines{job=\"pro\\\"me\\\\theus\"} 1 1.001\ngo_goroutines{job=\"pro\\\"me\\\\theus\"} 2.5 3\n# EOF\n",
+		},
+		{
+			name:   "",
+			format: EXPORT_FORMAT_CSV,
+			want:   "series,timestamp,value\n\"go_goroutines{job=\"\"pro\\\"\"me\\\\theus\"\"}\",1001,1\n\"go_goroutines{job=\"\"pro\\\"\"me\\\\theus\"\"}\",3000,2.5\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder, err := newExportEncoder(&buf, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = encoder.Encode(matrix); err != nil {
+				t.Fatal(err)
+			}
+			if err = encoder.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Encode() got = %s, want %s", buf.String(), tt.want)
+			}
+			decoder, err := newImportDecoder(&buf, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var samples []applications.Sample
+			for {
+				series, err := decoder.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				wantLabels := []applications.Label{{Name: labels.MetricName, Value: "go_goroutines"}, {Name: "job", Value: `pro"me\theus`}}
+				if !reflect.DeepEqual(series.Labels, wantLabels) {
+					t.Errorf("Next() got = %v, want %v", series.Labels, wantLabels)
+				}
+				samples = append(samples, series.Samples...)
+			}
+			wantSamples := []applications.Sample{{Timestamp: 1001, Value: 1}, {Timestamp: 3000, Value: 2.5}}
+			if !reflect.DeepEqual(samples, wantSamples) {
+				t.Errorf("Next() got = %v, want %v", samples, wantSamples)
+			}
+		})
+	}
+}
+
+func TestPromImpl_Export(t *testing.T) {
+	adaptor := &exportAdaptor{}
+	receiver := NewProm(&config.Config{
+		BizConf: config.BizConfig{
+			AdaptorInfluxDatabase: "prometheus",
+		},
+	}, adaptor)
+	start, end := "0", "10"
+	var buf bytes.Buffer
+	if err := receiver.Export(context.Background(), &buf, "", []string{`go_goroutines{job!=""}`}, &start, &end); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"metric":{"__name__":"go_goroutines","job":"pro\"me\\theus"},"values":[1,2.5],"timestamps":[1001,3000]}` + "\n"
+	if buf.String() != want {
+		t.Errorf("Export() got = %s, want %s", buf.String(), want)
+	}
+	if len(adaptor.streamCmds) != 1 {
+		t.Fatalf("Export() got %d queries, want 1", len(adaptor.streamCmds))
+	}
+	cmd := adaptor.streamCmds[0]
+	if cmd.Cmd != `go_goroutines{job!=""}` || cmd.Database != "prometheus" || cmd.Start.Unix() != 0 || cmd.End.Unix() != 10 {
+		t.Errorf("Export() got command %+v", cmd)
+	}
+}
+
+func TestPromImpl_Export_Overlapping(t *testing.T) {
+	adaptor := &exportAdaptor{}
+	receiver := NewProm(&config.Config{}, adaptor)
+	var buf bytes.Buffer
+	match := []string{`go_goroutines{job!=""}`, `{__name__="go_goroutines",job!=""}`, `go_goroutines`}
+	if err := receiver.Export(context.Background(), &buf, EXPORT_FORMAT_CSV, match, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "series,timestamp,value\n\"go_goroutines{job=\"\"pro\\\"\"me\\\\theus\"\"}\",1001,1\n\"go_goroutines{job=\"\"pro\\\"\"me\\\\theus\"\"}\",2000,NaN\n\"go_goroutines{job=\"\"pro\\\"\"me\\\\theus\"\"}\",3000,2.5\n"
+	if buf.String() != want {
+		t.Errorf("Export() got = %s, want %s", buf.String(), want)
+	}
+	// The first two selectors are the same one, and the series selected by the last one has been exported
+	if len(adaptor.streamCmds) != 2 {
+		t.Errorf("Export() got %d queries, want 2", len(adaptor.streamCmds))
+	}
+}
+
+func TestPromImpl_Export_BadRequest(t *testing.T) {
+	receiver := NewProm(&config.Config{}, &exportAdaptor{})
+	start := "yesterday"
+	tests := []struct {
+		name   string
+		format string
+		match  []string
+		start  *string
+	}{
+		{
+			name:  "",
+			match: nil,
+		},
+		{
+			name:  "",
+			match: []string{"go_goroutines{"},
+		},
+		{
+			name:  "",
+			match: []string{"go_goroutines"},
+			start: &start,
+		},
+		{
+			name:   "",
+			format: "parquet",
+			match:  []string{"go_goroutines"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := receiver.Export(context.Background(), &buf, tt.format, tt.match, tt.start, nil); !errors.Is(err, ErrBadRequest) {
+				t.Errorf("Export() error = %v, want ErrBadRequest", err)
+			}
+			if buf.Len() > 0 {
+				t.Errorf("Export() wrote %s before error", buf.String())
+			}
+		})
+	}
+}
+
+func TestPromImpl_Import(t *testing.T) {
+	adaptor := &exportAdaptor{}
+	receiver := NewProm(&config.Config{
+		BizConf: config.BizConfig{
+			AdaptorInfluxDatabase: "prometheus",
+		},
+	}, adaptor)
+	body := strings.Join([]string{
+		`{"metric":{"__name__":"go_goroutines","job":"prometheus"},"values":[1,2],"timestamps":[1000,2000]}`,
+		`{"metric":{"__name__":"go_threads","job":"prometheus"},"values":[3],"timestamps":[1000]}`,
+		`{"metric":`,
+	}, "\n")
+	imported, err := receiver.Import(context.Background(), strings.NewReader(body), EXPORT_FORMAT_JSON_LINES)
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Import() error = %v, want ErrBadRequest", err)
+	}
+	if imported != 3 {
+		t.Errorf("Import() got = %d, want 3", imported)
+	}
+	want := []applications.PromWriteCommand{
+		{
+			Database: "prometheus",
+			TimeSeries: []applications.TimeSeries{
+				{
+					Labels:  []applications.Label{{Name: labels.MetricName, Value: "go_goroutines"}, {Name: "job", Value: "prometheus"}},
+					Samples: []applications.Sample{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+				},
+				{
+					Labels:  []applications.Label{{Name: labels.MetricName, Value: "go_threads"}, {Name: "job", Value: "prometheus"}},
+					Samples: []applications.Sample{{Timestamp: 1000, Value: 3}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(adaptor.cmds, want) {
+		t.Errorf("Import() got = %v, want %v", adaptor.cmds, want)
+	}
+}
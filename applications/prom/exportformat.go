@@ -0,0 +1,404 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/applications"
+	"golang.org/x/exp/slices"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader is the header line of EXPORT_FORMAT_CSV
+var csvHeader = []string{"series", "timestamp", "value"}
+
+// exportEncoder encodes series in one of the export formats
+type exportEncoder interface {
+	// Encode writes all samples of matrix
+	Encode(matrix promql.Matrix) error
+	// Close writes the trailer of the format if any and flushes buffered data
+	Close() error
+}
+
+func newExportEncoder(w io.Writer, format string) (exportEncoder, error) {
+	switch format {
+	case "", EXPORT_FORMAT_JSON_LINES:
+		return &jsonLinesEncoder{encoder: json.NewEncoder(w)}, nil
+	case EXPORT_FORMAT_OPENMETRICS:
+		return &openMetricsEncoder{writer: bufio.NewWriter(w)}, nil
+	case EXPORT_FORMAT_CSV:
+		return &csvEncoder{writer: csv.NewWriter(w)}, nil
+	default:
+		return nil, errors.Errorf("unknown format %q", format)
+	}
+}
+
+// jsonLine is a line of EXPORT_FORMAT_JSON_LINES
+type jsonLine struct {
+	Metric     map[string]string `json:"metric"`
+	Values     []float64         `json:"values"`
+	Timestamps []int64           `json:"timestamps"`
+}
+
+type jsonLinesEncoder struct {
+	encoder *json.Encoder
+}
+
+// Encode implements exportEncoder's Encode method. NaN and infinite values are skipped as JSON can't represent them.
+func (receiver *jsonLinesEncoder) Encode(matrix promql.Matrix) error {
+	for _, series := range matrix {
+		line := jsonLine{
+			Metric:     series.Metric.Map(),
+			Values:     make([]float64, 0, len(series.Points)),
+			Timestamps: make([]int64, 0, len(series.Points)),
+		}
+		for _, point := range series.Points {
+			if math.IsNaN(point.V) || math.IsInf(point.V, 0) {
+				continue
+			}
+			line.Values = append(line.Values, point.V)
+			line.Timestamps = append(line.Timestamps, point.T)
+		}
+		if err := receiver.encoder.Encode(line); err != nil {
+			return errors.Wrap(err, "fail to encode json line")
+		}
+	}
+	return nil
+}
+
+// Close implements exportEncoder's Close method
+func (receiver *jsonLinesEncoder) Close() error {
+	return nil
+}
+
+type openMetricsEncoder struct {
+	writer *bufio.Writer
+}
+
+// Encode implements exportEncoder's Encode method
+func (receiver *openMetricsEncoder) Encode(matrix promql.Matrix) error {
+	for _, series := range matrix {
+		seriesPart := openMetricsSeries(series.Metric) + " "
+		for _, point := range series.Points {
+			receiver.writer.WriteString(seriesPart)
+			receiver.writer.WriteString(formatOpenMetricsFloat(point.V))
+			receiver.writer.WriteByte(' ')
+			receiver.writer.WriteString(strconv.FormatFloat(float64(point.T)/1000, 'f', -1, 64))
+			if err := receiver.writer.WriteByte('\n'); err != nil {
+				return errors.Wrap(err, "fail to write openmetrics line")
+			}
+		}
+	}
+	return nil
+}
+
+// Close implements exportEncoder's Close method
+func (receiver *openMetricsEncoder) Close() error {
+	receiver.writer.WriteString("# EOF\n")
+	return receiver.writer.Flush()
+}
+
+// openMetricsSeries formats metric like up{job="prometheus"}, which is the series part of OpenMetrics text format lines
+func openMetricsSeries(metric labels.Labels) string {
+	var sb strings.Builder
+	sb.WriteString(metric.Get(labels.MetricName))
+	first := true
+	for _, item := range metric {
+		if item.Name == labels.MetricName {
+			continue
+		}
+		if first {
+			sb.WriteByte('{')
+			first = false
+		} else {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(item.Name)
+		sb.WriteString(`="`)
+		sb.WriteString(openMetricsEscaper.Replace(item.Value))
+		sb.WriteByte('"')
+	}
+	if !first {
+		sb.WriteByte('}')
+	}
+	return sb.String()
+}
+
+// openMetricsEscaper escapes label values of OpenMetrics text format
+var openMetricsEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatOpenMetricsFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type csvEncoder struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+// Encode implements exportEncoder's Encode method
+func (receiver *csvEncoder) Encode(matrix promql.Matrix) error {
+	if err := receiver.writeHeader(); err != nil {
+		return err
+	}
+	for _, series := range matrix {
+		seriesPart := openMetricsSeries(series.Metric)
+		for _, point := range series.Points {
+			if err := receiver.writer.Write([]string{
+				seriesPart,
+				strconv.FormatInt(point.T, 10),
+				formatOpenMetricsFloat(point.V),
+			}); err != nil {
+				return errors.Wrap(err, "fail to write csv line")
+			}
+		}
+	}
+	return nil
+}
+
+func (receiver *csvEncoder) writeHeader() error {
+	if receiver.headerWritten {
+		return nil
+	}
+	receiver.headerWritten = true
+	if err := receiver.writer.Write(csvHeader); err != nil {
+		return errors.Wrap(err, "fail to write csv header")
+	}
+	return nil
+}
+
+// Close implements exportEncoder's Close method. The header is written even if there is no sample.
+func (receiver *csvEncoder) Close() error {
+	if err := receiver.writeHeader(); err != nil {
+		return err
+	}
+	receiver.writer.Flush()
+	return receiver.writer.Error()
+}
+
+// importDecoder decodes series in one of the export formats
+type importDecoder interface {
+	// Next returns the next series, or io.EOF if there is no more series
+	Next() (applications.TimeSeries, error)
+}
+
+func newImportDecoder(r io.Reader, format string) (importDecoder, error) {
+	switch format {
+	case "", EXPORT_FORMAT_JSON_LINES:
+		return &jsonLinesDecoder{decoder: json.NewDecoder(r)}, nil
+	case EXPORT_FORMAT_OPENMETRICS:
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to read openmetrics text")
+		}
+		return &openMetricsDecoder{content: content, now: time.Now().UnixMilli()}, nil
+	case EXPORT_FORMAT_CSV:
+		return &csvDecoder{reader: csv.NewReader(r)}, nil
+	default:
+		return nil, errors.Errorf("unknown format %q", format)
+	}
+}
+
+type jsonLinesDecoder struct {
+	decoder *json.Decoder
+	line    int
+}
+
+// Next implements importDecoder's Next method
+func (receiver *jsonLinesDecoder) Next() (applications.TimeSeries, error) {
+	var line jsonLine
+	if err := receiver.decoder.Decode(&line); err != nil {
+		if err == io.EOF {
+			return applications.TimeSeries{}, err
+		}
+		return applications.TimeSeries{}, errors.Wrapf(err, "invalid json line %d", receiver.line+1)
+	}
+	receiver.line++
+	if len(line.Values) != len(line.Timestamps) {
+		return applications.TimeSeries{}, errors.Errorf("json line %d has %d values but %d timestamps",
+			receiver.line, len(line.Values), len(line.Timestamps))
+	}
+	series := applications.TimeSeries{
+		Labels:  make([]applications.Label, 0, len(line.Metric)),
+		Samples: make([]applications.Sample, 0, len(line.Values)),
+	}
+	for _, item := range labels.FromMap(line.Metric) {
+		series.Labels = append(series.Labels, applications.Label{
+			Name:  item.Name,
+			Value: item.Value,
+		})
+	}
+	for i, value := range line.Values {
+		series.Samples = append(series.Samples, applications.Sample{
+			Timestamp: line.Timestamps[i],
+			Value:     value,
+		})
+	}
+	return series, nil
+}
+
+// openMetricsDecoder decodes sample lines of OpenMetrics text format, comment lines like # TYPE are skipped.
+// Samples without timestamp are stamped with the time the body is read. Timestamps are rounded to milliseconds
+// instead of truncated, so the ones written by openMetricsEncoder are decoded exactly.
+type openMetricsDecoder struct {
+	content []byte
+	line    int
+	now     int64
+}
+
+// Next implements importDecoder's Next method
+func (receiver *openMetricsDecoder) Next() (applications.TimeSeries, error) {
+	for len(receiver.content) > 0 {
+		var text []byte
+		if i := bytes.IndexByte(receiver.content, '\n'); i >= 0 {
+			text, receiver.content = receiver.content[:i], receiver.content[i+1:]
+		} else {
+			text, receiver.content = receiver.content, nil
+		}
+		receiver.line++
+		line := strings.TrimSpace(string(text))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		series, err := receiver.parseLine(line)
+		if err != nil {
+			return applications.TimeSeries{}, errors.Wrapf(err, "invalid openmetrics line %d", receiver.line)
+		}
+		return series, nil
+	}
+	return applications.TimeSeries{}, io.EOF
+}
+
+// parseLine parses a sample line like name{label="value"} 1 1.5 # {trace_id="abc"} 1 1.5, exemplars are dropped
+func (receiver *openMetricsDecoder) parseLine(line string) (applications.TimeSeries, error) {
+	end, err := openMetricsSeriesEnd(line)
+	if err != nil {
+		return applications.TimeSeries{}, err
+	}
+	metric, err := parser.ParseMetric(line[:end])
+	if err != nil {
+		return applications.TimeSeries{}, err
+	}
+	fields := strings.Fields(line[end:])
+	if i := slices.Index(fields, "#"); i >= 0 {
+		fields = fields[:i]
+	}
+	if len(fields) == 0 || len(fields) > 2 {
+		return applications.TimeSeries{}, errors.New("expected value and optional timestamp")
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return applications.TimeSeries{}, errors.Wrap(err, "invalid value")
+	}
+	ts := receiver.now
+	if len(fields) == 2 {
+		seconds, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return applications.TimeSeries{}, errors.Wrap(err, "invalid timestamp")
+		}
+		ts = int64(math.Round(seconds * 1000))
+	}
+	return singleSampleSeries(metric, ts, value), nil
+}
+
+// openMetricsSeriesEnd returns the end index of the series part of a sample line, skipping label values which may contain } or spaces
+func openMetricsSeriesEnd(line string) (int, error) {
+	end := strings.IndexAny(line, "{ ")
+	if end < 0 {
+		return 0, errors.New("missing value")
+	}
+	if line[end] == ' ' {
+		return end, nil
+	}
+	inQuotes := false
+	for end++; end < len(line); end++ {
+		switch {
+		case line[end] == '\\' && inQuotes:
+			end++
+		case line[end] == '"':
+			inQuotes = !inQuotes
+		case line[end] == '}' && !inQuotes:
+			return end + 1, nil
+		}
+	}
+	return 0, errors.New("unclosed label set")
+}
+
+type csvDecoder struct {
+	reader     *csv.Reader
+	headerRead bool
+}
+
+// Next implements importDecoder's Next method
+func (receiver *csvDecoder) Next() (applications.TimeSeries, error) {
+	if !receiver.headerRead {
+		header, err := receiver.reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return applications.TimeSeries{}, errors.New("missing csv header")
+			}
+			return applications.TimeSeries{}, errors.Wrap(err, "invalid csv header")
+		}
+		if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+			return applications.TimeSeries{}, errors.Errorf("csv header must be %s", strings.Join(csvHeader, ","))
+		}
+		receiver.headerRead = true
+	}
+	record, err := receiver.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return applications.TimeSeries{}, err
+		}
+		return applications.TimeSeries{}, errors.Wrap(err, "invalid csv line")
+	}
+	line, _ := receiver.reader.FieldPos(0)
+	metric, err := parser.ParseMetric(record[0])
+	if err != nil {
+		return applications.TimeSeries{}, errors.Wrapf(err, "invalid series of csv line %d", line)
+	}
+	ts, err := strconv.ParseInt(record[1], 10, 64)
+	if err != nil {
+		return applications.TimeSeries{}, errors.Wrapf(err, "invalid timestamp of csv line %d", line)
+	}
+	value, err := strconv.ParseFloat(record[2], 64)
+	if err != nil {
+		return applications.TimeSeries{}, errors.Wrapf(err, "invalid value of csv line %d", line)
+	}
+	return singleSampleSeries(metric, ts, value), nil
+}
+
+func singleSampleSeries(metric labels.Labels, ts int64, value float64) applications.TimeSeries {
+	series := applications.TimeSeries{
+		Labels: make([]applications.Label, 0, len(metric)),
+		Samples: []applications.Sample{
+			{
+				Timestamp: ts,
+				Value:     value,
+			},
+		},
+	}
+	for _, item := range metric {
+		series.Labels = append(series.Labels, applications.Label{
+			Name:  item.Name,
+			Value: item.Value,
+		})
+	}
+	return series
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
+	"github.com/wubin1989/promql2influxql/applications"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Federator serves the latest samples of series to be scraped by other Prometheus servers. It is not a part of Prom interface,
+// because response bodies are in Prometheus text exposition or OpenMetrics format rather than JSON.
+type Federator interface {
+	// Federate writes the latest sample within the lookback delta of every series selected by match to w in format.
+	// Errors wrapping ErrBadRequest are returned before anything is written.
+	Federate(ctx context.Context, w io.Writer, format expfmt.Format, match []string) error
+}
+
+var _ Federator = (*PromImpl)(nil)
+
+// Federate evaluates every metric name selected by match as an instant query of a vector selector at now, bounded by
+// BIZ_ADAPTOR_FEDERATE_LOOKBACK_DELTA, so series without samples within the lookback delta are not exposed.
+// Series selected by more than one match[] are exposed once. As Prometheus federation does, every metric is exposed as untyped
+// with the timestamp of its latest sample.
+func (receiver *PromImpl) Federate(ctx context.Context, w io.Writer, format expfmt.Format, match []string) error {
+	if len(match) == 0 {
+		return errors.Wrap(ErrBadRequest, "no match[] parameter provided")
+	}
+	matcherSets, err := parseMatchersParam(&match)
+	if err != nil {
+		return errors.Wrap(ErrBadRequest, err.Error())
+	}
+	location, err := parseTimezoneParam(ctx, nil, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	now := time.Now()
+	var vector promql.Vector
+	seen := make(map[string]struct{})
+	for _, matchers := range matcherSets {
+		metricNames, err := receiver.selectedMetricNames(ctx, matchers)
+		if err != nil {
+			return errors.Wrap(err, caller.NewCaller().String())
+		}
+		var labelMatchers []*labels.Matcher
+		for _, item := range matchers {
+			if item.Name != labels.MetricName {
+				labelMatchers = append(labelMatchers, item)
+			}
+		}
+		for _, metricName := range metricNames {
+			runResult, err := receiver.adaptor.Query(ctx, applications.PromCommand{
+				Cmd: (&parser.VectorSelector{
+					Name:          metricName,
+					LabelMatchers: labelMatchers,
+				}).String(),
+				Database:      receiver.conf.BizConf.AdaptorInfluxDatabase,
+				Evaluation:    &now,
+				Timezone:      location,
+				QueryType:     applications.INSTANT_QUERY,
+				LookbackDelta: receiver.conf.BizConf.AdaptorFederateLookbackDelta,
+			})
+			if err != nil {
+				return errors.Wrap(err, caller.NewCaller().String())
+			}
+			samples, _ := runResult.Result.(promql.Vector)
+			for _, sample := range samples {
+				key := sample.Metric.String()
+				if _, exists := seen[key]; exists {
+					continue
+				}
+				seen[key] = struct{}{}
+				vector = append(vector, sample)
+			}
+		}
+	}
+	if err = encodeFederation(w, format, vector); err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
+}
+
+// encodeFederation groups samples of vector into untyped metric families by metric name and encodes them in format.
+// Labels with empty values are unset and skipped, and samples without metric name are skipped.
+func encodeFederation(w io.Writer, format expfmt.Format, vector promql.Vector) error {
+	sort.SliceStable(vector, func(i, j int) bool {
+		if c := strings.Compare(vector[i].Metric.Get(labels.MetricName), vector[j].Metric.Get(labels.MetricName)); c != 0 {
+			return c < 0
+		}
+		return labels.Compare(vector[i].Metric, vector[j].Metric) < 0
+	})
+	encoder := expfmt.NewEncoder(w, format)
+	var family *dto.MetricFamily
+	for _, sample := range vector {
+		metricName := sample.Metric.Get(labels.MetricName)
+		if metricName == "" {
+			continue
+		}
+		if family == nil || family.GetName() != metricName {
+			if family != nil {
+				if err := encoder.Encode(family); err != nil {
+					return errors.Wrap(err, "fail to encode metric family")
+				}
+			}
+			family = &dto.MetricFamily{
+				Name: proto.String(metricName),
+				Type: dto.MetricType_UNTYPED.Enum(),
+			}
+		}
+		metric := &dto.Metric{
+			Untyped: &dto.Untyped{
+				Value: proto.Float64(sample.V),
+			},
+			TimestampMs: proto.Int64(sample.T),
+		}
+		for _, item := range sample.Metric {
+			if item.Name == labels.MetricName || item.Value == "" {
+				continue
+			}
+			metric.Label = append(metric.Label, &dto.LabelPair{
+				Name:  proto.String(item.Name),
+				Value: proto.String(item.Value),
+			})
+		}
+		family.Metric = append(family.Metric, metric)
+	}
+	if family != nil {
+		if err := encoder.Encode(family); err != nil {
+			return errors.Wrap(err, "fail to encode metric family")
+		}
+	}
+	if closer, ok := encoder.(expfmt.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return errors.Wrap(err, "fail to close encoder")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	"testing"
+	"time"
+)
+
+// federateAdaptor answers instant queries of vector selectors with the latest sample of every series
+type federateAdaptor struct {
+	remoteReadAdaptor
+}
+
+func (receiver *federateAdaptor) Query(ctx context.Context, cmd applications.PromCommand) (applications.RunResult, error) {
+	if cmd.DataType == applications.LABEL_VALUES_DATA {
+		return receiver.remoteReadAdaptor.Query(ctx, cmd)
+	}
+	receiver.cmds = append(receiver.cmds, cmd)
+	switch cmd.Cmd {
+	case `go_goroutines{job="prometheus"}`:
+		return applications.RunResult{
+			Result: promql.Vector{
+				{
+					Metric: labels.FromStrings(labels.MetricName, "go_goroutines", "job", "prometheus", "instance", "localhost:9090"),
+					Point:  promql.Point{T: 2000, V: 2},
+				},
+				{
+					Metric: labels.FromStrings(labels.MetricName, "go_goroutines", "job", "prometheus", "instance", ""),
+					Point:  promql.Point{T: 1500, V: 1},
+				},
+			},
+		}, nil
+	case `go_threads{job="prometheus"}`, `go_threads`:
+		return applications.RunResult{
+			Result: promql.Vector{
+				{
+					Metric: labels.FromStrings(labels.MetricName, "go_threads", "job", "prometheus"),
+					Point:  promql.Point{T: 2000, V: 8},
+				},
+			},
+		}, nil
+	}
+	return applications.RunResult{
+		Result: promql.Vector{},
+	}, nil
+}
+
+func TestPromImpl_Federate(t *testing.T) {
+	match := []string{`{__name__=~"go_.*",job="prometheus"}`, `go_threads`}
+	tests := []struct {
+		name   string
+		format expfmt.Format
+		want   string
+	}{
+		{
+			name:   "",
+			format: expfmt.FmtText,
+			want: `# TYPE go_goroutines untyped
+go_goroutines{job="prometheus"} 1 1500
+go_goroutines{instance="localhost:9090",job="prometheus"} 2 2000
+# TYPE go_threads untyped
+go_threads{job="prometheus"} 8 2000
+`,
+		},
+		{
+			name:   "",
+			format: expfmt.FmtOpenMetrics,
+			want: `# TYPE go_goroutines unknown
+go_goroutines{job="prometheus"} 1.0 1.5
+go_goroutines{instance="localhost:9090",job="prometheus"} 2.0 2.0
+# TYPE go_threads unknown
+go_threads{job="prometheus"} 8.0 2.0
+# EOF
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adaptor := &federateAdaptor{}
+			receiver := NewProm(&config.Config{
+				BizConf: config.BizConfig{
+					AdaptorInfluxDatabase:        "prometheus",
+					AdaptorFederateLookbackDelta: 5 * time.Minute,
+				},
+			}, adaptor)
+			var buf bytes.Buffer
+			if err := receiver.Federate(context.Background(), &buf, tt.format, match); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Federate() got = %s, want %s", buf.String(), tt.want)
+			}
+			for _, cmd := range adaptor.cmds {
+				if cmd.DataType == applications.LABEL_VALUES_DATA {
+					continue
+				}
+				if cmd.QueryType != applications.INSTANT_QUERY || cmd.Evaluation == nil || cmd.LookbackDelta != 5*time.Minute || cmd.Database != "prometheus" {
+					t.Errorf("Federate() got command %+v", cmd)
+				}
+			}
+		})
+	}
+}
+
+func TestPromImpl_Federate_BadRequest(t *testing.T) {
+	receiver := NewProm(&config.Config{}, &federateAdaptor{})
+	for _, match := range [][]string{nil, {"go_goroutines{"}} {
+		var buf bytes.Buffer
+		if err := receiver.Federate(context.Background(), &buf, expfmt.FmtText, match); !errors.Is(err, ErrBadRequest) {
+			t.Errorf("Federate() error = %v, want ErrBadRequest", err)
+		}
+		if buf.Len() > 0 {
+			t.Errorf("Federate() wrote %s before error", buf.String())
+		}
+	}
+}
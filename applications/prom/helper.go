@@ -1,10 +1,15 @@
 package service
 
 import (
+	"context"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/dto"
+	pb "github.com/wubin1989/promql2influxql/applications/prom/transport/grpc"
 	"math"
 	"strconv"
 	"time"
@@ -76,3 +81,189 @@ OUTER:
 	}
 	return matcherSets, nil
 }
+
+var fillTypes = map[string]applications.FillType{
+	"none":     applications.FILL_NONE,
+	"null":     applications.FILL_NULL,
+	"previous": applications.FILL_PREVIOUS,
+	"linear":   applications.FILL_LINEAR,
+}
+
+func parseFill(s string) (applications.FillType, error) {
+	fill, ok := fillTypes[s]
+	if !ok {
+		return 0, errors.Errorf("cannot parse %q to a valid fill option, must be one of none, null, previous or linear", s)
+	}
+	return fill, nil
+}
+
+func parseFillParam(paramVal *string, defaultValue string) (applications.FillType, error) {
+	if paramVal == nil || stringutils.IsEmpty(*paramVal) {
+		return parseFill(defaultValue)
+	}
+	return parseFill(*paramVal)
+}
+
+var rateModes = map[string]applications.RateMode{
+	"approximate":  applications.RATE_APPROXIMATE,
+	"extrapolated": applications.RATE_EXTRAPOLATED,
+}
+
+func parseRateMode(s string) (applications.RateMode, error) {
+	if stringutils.IsEmpty(s) {
+		return applications.RATE_APPROXIMATE, nil
+	}
+	rateMode, ok := rateModes[s]
+	if !ok {
+		return 0, errors.Errorf("cannot parse %q to a valid rate mode, must be approximate or extrapolated", s)
+	}
+	return rateMode, nil
+}
+
+var labelValuesModes = map[string]applications.LabelValuesMode{
+	"index":    applications.LABEL_VALUES_INDEX,
+	"accurate": applications.LABEL_VALUES_ACCURATE,
+}
+
+func parseLabelValuesMode(s string) (applications.LabelValuesMode, error) {
+	if stringutils.IsEmpty(s) {
+		return applications.LABEL_VALUES_INDEX, nil
+	}
+	labelValuesMode, ok := labelValuesModes[s]
+	if !ok {
+		return 0, errors.Errorf("cannot parse %q to a valid label values mode, must be index or accurate", s)
+	}
+	return labelValuesMode, nil
+}
+
+// TimezoneHeader is the request header carrying IANA timezone name of the client
+const TimezoneHeader = "X-Timezone"
+
+type timezoneCtxKey struct{}
+
+// NewTimezoneContext returns a new context.Context carrying IANA timezone name tz from TimezoneHeader
+func NewTimezoneContext(ctx context.Context, tz string) context.Context {
+	return context.WithValue(ctx, timezoneCtxKey{}, tz)
+}
+
+func timezoneFromContext(ctx context.Context) (string, bool) {
+	tz, ok := ctx.Value(timezoneCtxKey{}).(string)
+	return tz, ok
+}
+
+// parseTimezoneParam returns location of IANA timezone name from paramVal, TimezoneHeader in ctx or defaultValue
+// in priority order. It returns time.Local if none of them is set.
+func parseTimezoneParam(ctx context.Context, paramVal *string, defaultValue string) (*time.Location, error) {
+	tz := defaultValue
+	if value, ok := timezoneFromContext(ctx); ok && stringutils.IsNotEmpty(value) {
+		tz = value
+	}
+	if paramVal != nil && stringutils.IsNotEmpty(*paramVal) {
+		tz = *paramVal
+	}
+	if stringutils.IsEmpty(tz) {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid timezone %q", tz)
+	}
+	return location, nil
+}
+
+// optionalParam converts an unset proto3 string field to nil, so that gRPC requests share the same defaults
+// with omitted HTTP parameters
+func optionalParam(s string) *string {
+	if stringutils.IsEmpty(s) {
+		return nil
+	}
+	return &s
+}
+
+// queryDataToPb converts query result in dto.QueryData to typed pb.QueryData
+func queryDataToPb(data dto.QueryData) (*pb.QueryData, error) {
+	result := &pb.QueryData{
+		ResultType: data.ResultType,
+	}
+	switch value := data.Result.(type) {
+	case promql.Vector:
+		vector := &pb.Vector{
+			Samples: make([]*pb.Sample, 0, len(value)),
+		}
+		for _, sample := range value {
+			vector.Samples = append(vector.Samples, &pb.Sample{
+				Metric:    sample.Metric.Map(),
+				Timestamp: sample.T,
+				Value:     sample.V,
+			})
+		}
+		result.Result = &pb.QueryData_Vector{Vector: vector}
+	case promql.Matrix:
+		result.Result = &pb.QueryData_Matrix{Matrix: &pb.Matrix{
+			Series: matrixToPb(value),
+		}}
+	case promql.Scalar:
+		result.Result = &pb.QueryData_Scalar{Scalar: &pb.Scalar{
+			Timestamp: value.T,
+			Value:     value.V,
+		}}
+	case promql.String:
+		result.Result = &pb.QueryData_StringValue{StringValue: &pb.String{
+			Timestamp: value.T,
+			Value:     value.V,
+		}}
+	case nil:
+	default:
+		return nil, errors.Errorf("unsupported result type %T", data.Result)
+	}
+	return result, nil
+}
+
+// matrixToPb converts promql.Matrix to pb.Series slice
+func matrixToPb(matrix promql.Matrix) []*pb.Series {
+	result := make([]*pb.Series, 0, len(matrix))
+	for _, series := range matrix {
+		points := make([]*pb.Point, 0, len(series.Points))
+		for _, point := range series.Points {
+			points = append(points, &pb.Point{
+				Timestamp: point.T,
+				Value:     point.V,
+			})
+		}
+		result = append(result, &pb.Series{
+			Metric: series.Metric.Map(),
+			Points: points,
+		})
+	}
+	return result
+}
+
+// labelSetsToPb converts label sets returned by series query to protobuf messages
+func labelSetsToPb(data []map[string]string) []*pb.LabelSet {
+	result := make([]*pb.LabelSet, 0, len(data))
+	for _, labelSet := range data {
+		result = append(result, &pb.LabelSet{
+			Labels: labelSet,
+		})
+	}
+	return result
+}
+
+// metadataToPb converts metric metadata returned by metadata query to protobuf messages
+func metadataToPb(data map[string][]dto.MetricMetadata) map[string]*pb.MetricMetadataList {
+	result := make(map[string]*pb.MetricMetadataList, len(data))
+	for metricName, metadata := range data {
+		list := &pb.MetricMetadataList{
+			Metadata: make([]*pb.MetricMetadata, 0, len(metadata)),
+		}
+		for _, item := range metadata {
+			list.Metadata = append(list.Metadata, &pb.MetricMetadata{
+				Type: item.Type,
+				Help: item.Help,
+				Unit: item.Unit,
+			})
+		}
+		result[metricName] = list
+	}
+	return result
+}
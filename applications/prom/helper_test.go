@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/wubin1989/promql2influxql/applications/prom/dto"
+	pb "github.com/wubin1989/promql2influxql/applications/prom/transport/grpc"
+	"google.golang.org/protobuf/proto"
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_parseTimezoneParam(t *testing.T) {
+	berlin := "Europe/Berlin"
+	invalid := "Mars/Olympus_Mons"
+	empty := ""
+	type args struct {
+		ctx          context.Context
+		paramVal     *string
+		defaultValue string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "",
+			args: args{
+				ctx: context.Background(),
+			},
+			want: time.Local.String(),
+		},
+		{
+			name: "",
+			args: args{
+				ctx:          context.Background(),
+				paramVal:     &empty,
+				defaultValue: "UTC",
+			},
+			want: "UTC",
+		},
+		{
+			name: "",
+			args: args{
+				ctx:          NewTimezoneContext(context.Background(), "America/New_York"),
+				defaultValue: "UTC",
+			},
+			want: "America/New_York",
+		},
+		{
+			name: "",
+			args: args{
+				ctx:          NewTimezoneContext(context.Background(), "America/New_York"),
+				paramVal:     &berlin,
+				defaultValue: "UTC",
+			},
+			want: berlin,
+		},
+		{
+			name: "",
+			args: args{
+				ctx:      context.Background(),
+				paramVal: &invalid,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimezoneParam(tt.args.ctx, tt.args.paramVal, tt.args.defaultValue)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseTimezoneParam() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("parseTimezoneParam() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_queryDataToPb(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    dto.QueryData
+		want    *pb.QueryData
+		wantErr bool
+	}{
+		{
+			name: "",
+			data: dto.QueryData{
+				Result: promql.Vector{
+					{
+						Metric: labels.FromStrings("host", "a"),
+						Point:  promql.Point{T: 1000, V: 1},
+					},
+				},
+				ResultType: string(parser.ValueTypeVector),
+			},
+			want: &pb.QueryData{
+				ResultType: string(parser.ValueTypeVector),
+				Result: &pb.QueryData_Vector{Vector: &pb.Vector{
+					Samples: []*pb.Sample{
+						{
+							Metric:    map[string]string{"host": "a"},
+							Timestamp: 1000,
+							Value:     1,
+						},
+					},
+				}},
+			},
+		},
+		{
+			name: "",
+			data: dto.QueryData{
+				Result: promql.Matrix{
+					{
+						Metric: labels.FromStrings("host", "a"),
+						Points: []promql.Point{{T: 1000, V: 1}, {T: 2000, V: math.Inf(1)}},
+					},
+				},
+				ResultType: string(parser.ValueTypeMatrix),
+			},
+			want: &pb.QueryData{
+				ResultType: string(parser.ValueTypeMatrix),
+				Result: &pb.QueryData_Matrix{Matrix: &pb.Matrix{
+					Series: []*pb.Series{
+						{
+							Metric: map[string]string{"host": "a"},
+							Points: []*pb.Point{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: math.Inf(1)}},
+						},
+					},
+				}},
+			},
+		},
+		{
+			name: "",
+			data: dto.QueryData{
+				Result:     promql.Scalar{T: 1000, V: 2},
+				ResultType: string(parser.ValueTypeScalar),
+			},
+			want: &pb.QueryData{
+				ResultType: string(parser.ValueTypeScalar),
+				Result:     &pb.QueryData_Scalar{Scalar: &pb.Scalar{Timestamp: 1000, Value: 2}},
+			},
+		},
+		{
+			name: "",
+			data: dto.QueryData{
+				Result:     promql.String{T: 1000, V: "up"},
+				ResultType: string(parser.ValueTypeString),
+			},
+			want: &pb.QueryData{
+				ResultType: string(parser.ValueTypeString),
+				Result:     &pb.QueryData_StringValue{StringValue: &pb.String{Timestamp: 1000, Value: "up"}},
+			},
+		},
+		{
+			name: "",
+			data: dto.QueryData{
+				Result:     []string{"up"},
+				ResultType: string(parser.ValueTypeVector),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := queryDataToPb(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("queryDataToPb() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("queryDataToPb() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_metadataToPb(t *testing.T) {
+	data := map[string][]dto.MetricMetadata{
+		"http_requests_total": {
+			{
+				Type: "counter",
+				Help: "Total number of HTTP requests.",
+				Unit: "requests",
+			},
+		},
+		"go_goroutines": {
+			{
+				Type: "gauge",
+			},
+		},
+	}
+	got := metadataToPb(data)
+	if len(got) != len(data) {
+		t.Fatalf("metadataToPb() got %d metrics, want %d", len(got), len(data))
+	}
+	want := &pb.MetricMetadataList{
+		Metadata: []*pb.MetricMetadata{
+			{
+				Type: "counter",
+				Help: "Total number of HTTP requests.",
+				Unit: "requests",
+			},
+		},
+	}
+	if !proto.Equal(got["http_requests_total"], want) {
+		t.Errorf("metadataToPb() got = %v, want %v", got["http_requests_total"], want)
+	}
+}
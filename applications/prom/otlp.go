@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
+	"github.com/wubin1989/promql2influxql/applications"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/encoding/protojson"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// otlpServiceName, otlpServiceNamespace and otlpServiceInstanceID are resource attributes mapped to job and instance labels
+	otlpServiceName       = "service.name"
+	otlpServiceNamespace  = "service.namespace"
+	otlpServiceInstanceID = "service.instance.id"
+)
+
+// OTLPWriter receives metrics of OTLP/HTTP protocol. It is not a part of Prom interface,
+// because request and response bodies of OTLP are protobuf or protobuf JSON messages rather than forms.
+type OTLPWriter interface {
+	// OTLPWrite converts metrics of req to Prometheus time series and writes them. Data points that can't be converted
+	// are counted in the partial success of the response. The error wraps applications.ErrRejectedSamples if some samples
+	// are rejected as invalid.
+	OTLPWrite(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error)
+}
+
+var _ OTLPWriter = (*PromImpl)(nil)
+
+// OTLPWrite converts metrics of req to Prometheus time series by otlpConverter, and writes them to the database of the service
+// in the same schema as remote write does, so they can be queried by PromQL functions like rate and histogram_quantile.
+func (receiver *PromImpl) OTLPWrite(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	var converter otlpConverter
+	for _, item := range req.ResourceMetrics {
+		converter.convertResourceMetrics(item)
+	}
+	if err := receiver.write(ctx, converter.timeSeries); err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	resp := &colmetricspb.ExportMetricsServiceResponse{}
+	if converter.rejected > 0 {
+		resp.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: converter.rejected,
+			ErrorMessage:       strings.Join(converter.reasons, "; "),
+		}
+	}
+	return resp, nil
+}
+
+// otlpConverter converts OTLP metrics to Prometheus time series following the OpenTelemetry Prometheus compatibility specification:
+//   - gauges and non-monotonic sums are gauges
+//   - monotonic sums are counters suffixed with _total
+//   - histograms with explicit buckets are xxx_bucket series with le label, xxx_sum and xxx_count
+//   - summaries are xxx series with quantile label, xxx_sum and xxx_count
+//   - service.name resource attribute prefixed with service.namespace/ if any is job label,
+//     and service.instance.id resource attribute is instance label
+//   - data point attributes are labels, whose names are sanitized and values of colliding names are joined with ;
+//
+// Sums and histograms of delta temporality and exponential histograms can't be represented by Prometheus time series,
+// so their data points are rejected.
+type otlpConverter struct {
+	timeSeries []applications.TimeSeries
+	// rejected is the number of rejected data points
+	rejected int64
+	// reasons are distinct reasons of rejected data points
+	reasons []string
+}
+
+func (receiver *otlpConverter) reject(count int, reason string) {
+	if count == 0 {
+		return
+	}
+	receiver.rejected += int64(count)
+	if !slices.Contains(receiver.reasons, reason) {
+		receiver.reasons = append(receiver.reasons, reason)
+	}
+}
+
+func (receiver *otlpConverter) convertResourceMetrics(resourceMetrics *metricspb.ResourceMetrics) {
+	resourceLabels := make(map[string]string)
+	var serviceName, serviceNamespace string
+	for _, item := range resourceMetrics.GetResource().GetAttributes() {
+		switch item.Key {
+		case otlpServiceName:
+			serviceName = otlpAttributeValue(item.Value)
+		case otlpServiceNamespace:
+			serviceNamespace = otlpAttributeValue(item.Value)
+		case otlpServiceInstanceID:
+			resourceLabels[model.InstanceLabel] = otlpAttributeValue(item.Value)
+		}
+	}
+	if serviceName != "" {
+		if serviceNamespace != "" {
+			serviceName = serviceNamespace + "/" + serviceName
+		}
+		resourceLabels[model.JobLabel] = serviceName
+	}
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		for _, metric := range scopeMetrics.Metrics {
+			receiver.convertMetric(metric, resourceLabels)
+		}
+	}
+}
+
+func (receiver *otlpConverter) convertMetric(metric *metricspb.Metric, resourceLabels map[string]string) {
+	switch data := metric.Data.(type) {
+	case *metricspb.Metric_Gauge:
+		metricName := otlpMetricName(metric.Name, metric.Unit, otlpGauge)
+		for _, point := range data.Gauge.DataPoints {
+			receiver.convertNumberDataPoint(metricName, point, resourceLabels)
+		}
+	case *metricspb.Metric_Sum:
+		if data.Sum.AggregationTemporality != metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+			receiver.reject(len(data.Sum.DataPoints), fmt.Sprintf("sum %s is not cumulative", metric.Name))
+			return
+		}
+		metricType := otlpOther
+		if data.Sum.IsMonotonic {
+			metricType = otlpCounter
+		}
+		metricName := otlpMetricName(metric.Name, metric.Unit, metricType)
+		for _, point := range data.Sum.DataPoints {
+			receiver.convertNumberDataPoint(metricName, point, resourceLabels)
+		}
+	case *metricspb.Metric_Histogram:
+		if data.Histogram.AggregationTemporality != metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE {
+			receiver.reject(len(data.Histogram.DataPoints), fmt.Sprintf("histogram %s is not cumulative", metric.Name))
+			return
+		}
+		metricName := otlpMetricName(metric.Name, metric.Unit, otlpOther)
+		for _, point := range data.Histogram.DataPoints {
+			receiver.convertHistogramDataPoint(metricName, point, resourceLabels)
+		}
+	case *metricspb.Metric_Summary:
+		metricName := otlpMetricName(metric.Name, metric.Unit, otlpOther)
+		for _, point := range data.Summary.DataPoints {
+			receiver.convertSummaryDataPoint(metricName, point, resourceLabels)
+		}
+	case *metricspb.Metric_ExponentialHistogram:
+		receiver.reject(len(data.ExponentialHistogram.DataPoints), fmt.Sprintf("exponential histogram %s is not supported", metric.Name))
+	}
+}
+
+func (receiver *otlpConverter) convertNumberDataPoint(metricName string, point *metricspb.NumberDataPoint, resourceLabels map[string]string) {
+	if otlpNoRecordedValue(point.Flags) {
+		return
+	}
+	var value float64
+	switch pointValue := point.Value.(type) {
+	case *metricspb.NumberDataPoint_AsDouble:
+		value = pointValue.AsDouble
+	case *metricspb.NumberDataPoint_AsInt:
+		value = float64(pointValue.AsInt)
+	}
+	receiver.add(metricName, otlpLabels(point.Attributes, resourceLabels), point.TimeUnixNano, value)
+}
+
+func (receiver *otlpConverter) convertHistogramDataPoint(metricName string, point *metricspb.HistogramDataPoint, resourceLabels map[string]string) {
+	if otlpNoRecordedValue(point.Flags) {
+		return
+	}
+	pointLabels := otlpLabels(point.Attributes, resourceLabels)
+	// Bucket counts of OTLP histograms are not cumulative, and the last one is for the +Inf bucket
+	var count uint64
+	for i, bound := range point.ExplicitBounds {
+		if i < len(point.BucketCounts) {
+			count += point.BucketCounts[i]
+		}
+		receiver.add(metricName+"_bucket", otlpWith(pointLabels, model.BucketLabel, otlpFormatFloat(bound)), point.TimeUnixNano, float64(count))
+	}
+	receiver.add(metricName+"_bucket", otlpWith(pointLabels, model.BucketLabel, "+Inf"), point.TimeUnixNano, float64(point.Count))
+	if point.Sum != nil {
+		receiver.add(metricName+"_sum", pointLabels, point.TimeUnixNano, *point.Sum)
+	}
+	receiver.add(metricName+"_count", pointLabels, point.TimeUnixNano, float64(point.Count))
+}
+
+func (receiver *otlpConverter) convertSummaryDataPoint(metricName string, point *metricspb.SummaryDataPoint, resourceLabels map[string]string) {
+	if otlpNoRecordedValue(point.Flags) {
+		return
+	}
+	pointLabels := otlpLabels(point.Attributes, resourceLabels)
+	for _, item := range point.QuantileValues {
+		receiver.add(metricName, otlpWith(pointLabels, model.QuantileLabel, otlpFormatFloat(item.Quantile)), point.TimeUnixNano, item.Value)
+	}
+	receiver.add(metricName+"_sum", pointLabels, point.TimeUnixNano, point.Sum)
+	receiver.add(metricName+"_count", pointLabels, point.TimeUnixNano, float64(point.Count))
+}
+
+// add appends a time series of a single sample, timeUnixNano is truncated to milliseconds
+func (receiver *otlpConverter) add(metricName string, pointLabels map[string]string, timeUnixNano uint64, value float64) {
+	if metricName == "" {
+		receiver.reject(1, "metric name is empty")
+		return
+	}
+	series := applications.TimeSeries{
+		Labels: make([]applications.Label, 0, len(pointLabels)+1),
+		Samples: []applications.Sample{
+			{
+				Timestamp: int64(timeUnixNano / 1e6),
+				Value:     value,
+			},
+		},
+	}
+	series.Labels = append(series.Labels, applications.Label{
+		Name:  labels.MetricName,
+		Value: metricName,
+	})
+	for name, value := range pointLabels {
+		series.Labels = append(series.Labels, applications.Label{
+			Name:  name,
+			Value: value,
+		})
+	}
+	sort.Slice(series.Labels, func(i, j int) bool {
+		return series.Labels[i].Name < series.Labels[j].Name
+	})
+	receiver.timeSeries = append(receiver.timeSeries, series)
+}
+
+// otlpLabels returns labels of data point attributes and resource labels. Values of attributes whose sanitized names
+// collide are joined with ; in the order of the original names. Resource labels override data point attributes.
+func otlpLabels(attributes []*commonpb.KeyValue, resourceLabels map[string]string) map[string]string {
+	sorted := make([]*commonpb.KeyValue, len(attributes))
+	copy(sorted, attributes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+	result := make(map[string]string, len(sorted)+len(resourceLabels))
+	for _, item := range sorted {
+		name := otlpLabelName(item.Key)
+		value := otlpAttributeValue(item.Value)
+		if name == "" || value == "" {
+			continue
+		}
+		if existing, ok := result[name]; ok {
+			value = existing + ";" + value
+		}
+		result[name] = value
+	}
+	for name, value := range resourceLabels {
+		result[name] = value
+	}
+	return result
+}
+
+// otlpWith returns a copy of pointLabels with label name set to value
+func otlpWith(pointLabels map[string]string, name string, value string) map[string]string {
+	result := make(map[string]string, len(pointLabels)+1)
+	for k, v := range pointLabels {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
+
+// otlpAttributeValue returns string representation of attribute value, arrays and key value lists are in protobuf JSON format
+func otlpAttributeValue(value *commonpb.AnyValue) string {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return otlpFormatFloat(v.DoubleValue)
+	case *commonpb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(v.BytesValue)
+	case *commonpb.AnyValue_ArrayValue:
+		return protojson.Format(v.ArrayValue)
+	case *commonpb.AnyValue_KvlistValue:
+		return protojson.Format(v.KvlistValue)
+	}
+	return ""
+}
+
+func otlpFormatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func otlpNoRecordedValue(flags uint32) bool {
+	return flags&uint32(metricspb.DataPointFlags_FLAG_NO_RECORDED_VALUE) != 0
+}
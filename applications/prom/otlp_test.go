@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_otlpMetricName(t *testing.T) {
+	tests := []struct {
+		name       string
+		metricName string
+		unit       string
+		metricType otlpMetricType
+		want       string
+	}{
+		{
+			name:       "",
+			metricName: "http.server.duration",
+			unit:       "ms",
+			metricType: otlpOther,
+			want:       "http_server_duration_milliseconds",
+		},
+		{
+			name:       "",
+			metricName: "http.server.requests",
+			unit:       "{request}",
+			metricType: otlpCounter,
+			want:       "http_server_requests_total",
+		},
+		{
+			name:       "",
+			metricName: "process.runtime.total.bytes",
+			unit:       "By",
+			metricType: otlpCounter,
+			want:       "process_runtime_bytes_total",
+		},
+		{
+			name:       "",
+			metricName: "system.network.io",
+			unit:       "By/s",
+			metricType: otlpGauge,
+			want:       "system_network_io_bytes_per_second",
+		},
+		{
+			name:       "",
+			metricName: "system.cpu.utilization",
+			unit:       "1",
+			metricType: otlpGauge,
+			want:       "system_cpu_utilization_ratio",
+		},
+		{
+			name:       "",
+			metricName: "queue.size",
+			unit:       "1",
+			metricType: otlpOther,
+			want:       "queue_size",
+		},
+		{
+			name:       "",
+			metricName: "2xx.responses",
+			unit:       "",
+			metricType: otlpGauge,
+			want:       "_2xx_responses",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := otlpMetricName(tt.metricName, tt.unit, tt.metricType); got != tt.want {
+				t.Errorf("otlpMetricName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_otlpLabelName(t *testing.T) {
+	tests := []struct {
+		name      string
+		labelName string
+		want      string
+	}{
+		{
+			name:      "",
+			labelName: "http.method",
+			want:      "http_method",
+		},
+		{
+			name:      "",
+			labelName: "0day",
+			want:      "key_0day",
+		},
+		{
+			name:      "",
+			labelName: "_private",
+			want:      "key_private",
+		},
+		{
+			name:      "",
+			labelName: "__reserved",
+			want:      "__reserved",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := otlpLabelName(tt.labelName); got != tt.want {
+				t.Errorf("otlpLabelName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func stringAttribute(key string, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func TestPromImpl_OTLPWrite(t *testing.T) {
+	sum := 3.5
+	req := &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				Resource: &resourcepb.Resource{
+					Attributes: []*commonpb.KeyValue{
+						stringAttribute("service.name", "checkout"),
+						stringAttribute("service.namespace", "shop"),
+						stringAttribute("service.instance.id", "pod-1"),
+						stringAttribute("host.name", "node-1"),
+					},
+				},
+				ScopeMetrics: []*metricspb.ScopeMetrics{
+					{
+						Metrics: []*metricspb.Metric{
+							{
+								Name: "http.server.requests",
+								Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+									AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+									IsMonotonic:            true,
+									DataPoints: []*metricspb.NumberDataPoint{
+										{
+											Attributes:   []*commonpb.KeyValue{stringAttribute("http.method", "GET")},
+											TimeUnixNano: 1000_000_000,
+											Value:        &metricspb.NumberDataPoint_AsInt{AsInt: 7},
+										},
+										{
+											TimeUnixNano: 2000_000_000,
+											Flags:        uint32(metricspb.DataPointFlags_FLAG_NO_RECORDED_VALUE),
+										},
+									},
+								}},
+							},
+							{
+								Name: "http.server.duration",
+								Unit: "s",
+								Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+									AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+									DataPoints: []*metricspb.HistogramDataPoint{
+										{
+											TimeUnixNano:   1000_000_000,
+											Count:          4,
+											Sum:            &sum,
+											BucketCounts:   []uint64{1, 2, 1},
+											ExplicitBounds: []float64{0.5, 1},
+										},
+									},
+								}},
+							},
+							{
+								Name: "jobs.processed",
+								Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+									AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+									IsMonotonic:            true,
+									DataPoints: []*metricspb.NumberDataPoint{
+										{
+											TimeUnixNano: 1000_000_000,
+											Value:        &metricspb.NumberDataPoint_AsInt{AsInt: 1},
+										},
+									},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	adaptor := &remoteWriteAdaptor{}
+	receiver := NewProm(&config.Config{
+		BizConf: config.BizConfig{
+			AdaptorInfluxDatabase: "prometheus",
+		},
+	}, adaptor)
+	resp, err := receiver.OTLPWrite(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.PartialSuccess.GetRejectedDataPoints() != 1 || resp.PartialSuccess.GetErrorMessage() != "sum jobs.processed is not cumulative" {
+		t.Errorf("OTLPWrite() partial success = %v", resp.PartialSuccess)
+	}
+	series := func(metricName string, timestamp int64, value float64, extra ...applications.Label) applications.TimeSeries {
+		seriesLabels := append([]applications.Label{{Name: "__name__", Value: metricName}}, extra...)
+		seriesLabels = append(seriesLabels, applications.Label{Name: "instance", Value: "pod-1"}, applications.Label{Name: "job", Value: "shop/checkout"})
+		sort.Slice(seriesLabels, func(i, j int) bool {
+			return seriesLabels[i].Name < seriesLabels[j].Name
+		})
+		return applications.TimeSeries{
+			Labels:  seriesLabels,
+			Samples: []applications.Sample{{Timestamp: timestamp, Value: value}},
+		}
+	}
+	want := []applications.PromWriteCommand{
+		{
+			Database: "prometheus",
+			TimeSeries: []applications.TimeSeries{
+				series("http_server_requests_total", 1000, 7, applications.Label{Name: "http_method", Value: "GET"}),
+				series("http_server_duration_seconds_bucket", 1000, 1, applications.Label{Name: "le", Value: "0.5"}),
+				series("http_server_duration_seconds_bucket", 1000, 3, applications.Label{Name: "le", Value: "1"}),
+				series("http_server_duration_seconds_bucket", 1000, 4, applications.Label{Name: "le", Value: "+Inf"}),
+				series("http_server_duration_seconds_sum", 1000, 3.5),
+				series("http_server_duration_seconds_count", 1000, 4),
+			},
+		},
+	}
+	if !reflect.DeepEqual(adaptor.cmds, want) {
+		t.Errorf("OTLPWrite() got = %v, want %v", adaptor.cmds, want)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"golang.org/x/exp/slices"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// otlpUnitNames maps UCUM units of OTLP metrics to the unit words of Prometheus metric names
+var otlpUnitNames = map[string]string{
+	// Time
+	"d":   "days",
+	"h":   "hours",
+	"min": "minutes",
+	"s":   "seconds",
+	"ms":  "milliseconds",
+	"us":  "microseconds",
+	"ns":  "nanoseconds",
+	// Bytes
+	"By":   "bytes",
+	"KiBy": "kibibytes",
+	"MiBy": "mebibytes",
+	"GiBy": "gibibytes",
+	"TiBy": "tibibytes",
+	"KBy":  "kilobytes",
+	"MBy":  "megabytes",
+	"GBy":  "gigabytes",
+	"TBy":  "terabytes",
+	// SI
+	"m":   "meters",
+	"V":   "volts",
+	"A":   "amperes",
+	"J":   "joules",
+	"W":   "watts",
+	"g":   "grams",
+	"Cel": "celsius",
+	"Hz":  "hertz",
+	"1":   "",
+	"%":   "percent",
+}
+
+// otlpPerUnitNames maps UCUM units after the slash of rate units like By/s to the words after per
+var otlpPerUnitNames = map[string]string{
+	"s":  "second",
+	"m":  "minute",
+	"h":  "hour",
+	"d":  "day",
+	"w":  "week",
+	"mo": "month",
+	"y":  "year",
+}
+
+// otlpUnitAnnotation matches curly braces annotations of UCUM units like {request}, which are not units at all
+var otlpUnitAnnotation = regexp.MustCompile(`\{[^}]*\}`)
+
+// otlpMetricType indicates how the name of an OTLP metric is suffixed
+// Basically,
+//   - otlpGauge is for gauges, suffixed with _ratio if unit is 1
+//   - otlpCounter is for monotonic sums, suffixed with _total
+//   - otlpOther is for non-monotonic sums, histograms and summaries, which are only suffixed with unit
+type otlpMetricType int
+
+const (
+	otlpGauge otlpMetricType = iota + 1
+	otlpCounter
+	otlpOther
+)
+
+// otlpMetricName builds Prometheus metric name from OTLP metric name and unit following the OpenTelemetry
+// Prometheus compatibility specification:
+//   - characters other than letters and digits are separators, tokens are joined with underscores
+//   - the unit is appended like _seconds or _bytes_per_second unless the name already contains it
+//   - _total is appended to counters, i.e. monotonic sums
+//   - _ratio is appended to gauges with unit 1
+//
+// _bucket, _sum and _count suffixes of histograms and summaries are appended by the caller.
+func otlpMetricName(name string, unit string, metricType otlpMetricType) string {
+	tokens := strings.FieldsFunc(name, isNotLetterOrDigit)
+	mainUnit, perUnit, _ := strings.Cut(strings.TrimSpace(otlpUnitAnnotation.ReplaceAllString(unit, "")), "/")
+	if mainUnit = otlpUnitWord(mainUnit, otlpUnitNames); mainUnit != "" && !slices.Contains(tokens, mainUnit) {
+		tokens = append(tokens, mainUnit)
+	}
+	if perUnit = otlpUnitWord(perUnit, otlpPerUnitNames); perUnit != "" && !slices.Contains(tokens, perUnit) {
+		tokens = append(tokens, "per", perUnit)
+	}
+	switch metricType {
+	case otlpCounter:
+		kept := tokens[:0]
+		for _, token := range tokens {
+			if token != "total" {
+				kept = append(kept, token)
+			}
+		}
+		tokens = append(kept, "total")
+	case otlpGauge:
+		if unit == "1" && !slices.Contains(tokens, "ratio") {
+			tokens = append(tokens, "ratio")
+		}
+	}
+	metricName := strings.Join(tokens, "_")
+	if metricName != "" && unicode.IsDigit(rune(metricName[0])) {
+		metricName = "_" + metricName
+	}
+	return metricName
+}
+
+// otlpUnitWord returns the word of unit in words, or unit itself with invalid characters removed if it is unknown
+func otlpUnitWord(unit string, words map[string]string) string {
+	unit = strings.TrimSpace(unit)
+	if word, ok := words[unit]; ok {
+		return word
+	}
+	return strings.Join(strings.FieldsFunc(unit, isNotLetterOrDigit), "_")
+}
+
+// otlpLabelName replaces characters that are not allowed in Prometheus label names with underscores,
+// and prefixes names starting with a digit or a single underscore with key
+func otlpLabelName(name string) string {
+	labelName := strings.Map(func(r rune) rune {
+		if isNotLetterOrDigit(r) {
+			return '_'
+		}
+		return r
+	}, name)
+	switch {
+	case labelName == "":
+		return labelName
+	case unicode.IsDigit(rune(labelName[0])):
+		return "key_" + labelName
+	case strings.HasPrefix(labelName, "_") && !strings.HasPrefix(labelName, "__"):
+		return "key" + labelName
+	}
+	return labelName
+}
+
+// isNotLetterOrDigit reports whether r is not an ASCII letter or digit, which are the only characters allowed
+// in Prometheus metric names and label names besides underscores and colons
+func isNotLetterOrDigit(r rune) bool {
+	return r >= unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
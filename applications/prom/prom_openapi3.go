@@ -3,5 +3,5 @@ package service
 import "github.com/unionj-cloud/go-doudou/v2/framework/rest"
 
 func init() {
-	rest.Oas = `{"openapi":"3.0.2","info":{"title":"Prom","version":"v20230118"},"servers":[{"url":"http://localhost:6060"}],"paths":{"/label/{label_name}/values":{"get":{"description":"GetLabel_Label_nameValues Returns label values\nThe following endpoint returns a list of label values for a provided label name\n\nThe \"data\" section of the JSON response is a list of string label values.\n","parameters":[{"name":"start","in":"query","description":"Start timestamp. Optional.\n","schema":{"type":"string","description":"Start timestamp. Optional.\n"}},{"name":"end","in":"query","description":"End timestamp. Optional.\n","schema":{"type":"string","description":"End timestamp. Optional.\n"}},{"name":"match","in":"query","description":"Repeated series selector argument that selects the series from which to read the label values. Optional.\n","schema":{"type":"array","items":{"type":"string"},"description":"Repeated series selector argument that selects the series from which to read the label values. Optional.\n"}},{"name":"labelname","in":"path","description":"Label name\n\nExample: \"/label/job/values\"\n\nrequired","required":true,"schema":{"type":"string","description":"Label name\n\nExample: \"/label/job/values\"\n\nrequired"}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetLabel_Label_nameValuesResp"}}}}}}},"/query":{"get":{"description":"GetQuery is compatible to Prometheus GET /api/v1/query","parameters":[{"name":"query","in":"query","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired","required":true,"schema":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"}},{"name":"time","in":"query","description":"Evaluation timestamp. Optional.\n\nThe current server time is used if the \"time\" parameter is omitted.\n\nOptional.","schema":{"type":"string","description":"Evaluation timestamp. Optional.\n\nThe current server time is used if the \"time\" parameter is omitted.\n\nOptional."}},{"name":"timeout","in":"query","description":"Evaluation timeout. Optional.","schema":{"type":"string","description":"Evaluation timeout. Optional."}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetQueryResp"}}}}}},"post":{"description":"Query is compatible to Prometheus POST /api/v1/query","requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"$ref":"#/components/schemas/QueryReq"}}},"required":true},"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/QueryResp"}}}}}}},"/query_range":{"get":{"description":"GetQuery_range is compatible to Prometheus GET /api/v1/query_range","parameters":[{"name":"query","in":"query","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired","required":true,"schema":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"}},{"name":"start","in":"query","description":"Start timestamp.\n\nExample: \"\u0026start=2015-07-01T20:10:30.781Z\"\n","schema":{"type":"string","description":"Start timestamp.\n\nExample: \"\u0026start=2015-07-01T20:10:30.781Z\"\n"}},{"name":"end","in":"query","description":"End timestamp.\n\nExample: \"\u0026end=2015-07-01T20:11:00.781Z\"\n","schema":{"type":"string","description":"End timestamp.\n\nExample: \"\u0026end=2015-07-01T20:11:00.781Z\"\n"}},{"name":"step","in":"query","description":"Query resolution step width in \"duration\" format or float number of seconds.\n\nExample: \"\u0026step=15s\"\n","schema":{"type":"string","description":"Query resolution step width in \"duration\" format or float number of seconds.\n\nExample: \"\u0026step=15s\"\n"}},{"name":"timeout","in":"query","description":"Evaluation timeout. Optional.","schema":{"type":"string","description":"Evaluation timeout. Optional."}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetQuery_rangeResp"}}}}}},"post":{"description":"Query_range is compatible to Prometheus POST /api/v1/query_range","requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"$ref":"#/components/schemas/Query_rangeReq"}}},"required":true},"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Query_rangeResp"}}}}}}}},"components":{"schemas":{"GetLabel_Label_nameValuesResp":{"title":"GetLabel_Label_nameValuesResp","type":"object","properties":{"data":{"type":"array","items":{"type":"string"}},"status":{"type":"string"}},"required":["data","status"]},"GetQueryResp":{"title":"GetQueryResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"GetQuery_rangeResp":{"title":"GetQuery_rangeResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"QueryData":{"title":"QueryData","type":"object","properties":{"result":{"type":"object"},"resultType":{"type":"string"}},"description":"\n\n\n\n","required":["result","resultType"]},"QueryReq":{"title":"QueryReq","type":"object","properties":{"query":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"},"time":{"type":"string","description":"Evaluation timestamp. Optional.\n\nThe current server time is used if the \"time\" parameter is omitted.\n\nOptional."},"timeout":{"type":"string","description":"Evaluation timeout. Optional."}},"required":["query"]},"QueryResp":{"title":"QueryResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"QueryResponse":{"title":"QueryResponse","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"Query_rangeReq":{"title":"Query_rangeReq","type":"object","properties":{"end":{"type":"string","description":"End timestamp.\n\nExample: \"\u0026end=2015-07-01T20:11:00.781Z\"\n"},"query":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"},"start":{"type":"string","description":"Start timestamp.\n\nExample: \"\u0026start=2015-07-01T20:10:30.781Z\"\n"},"step":{"type":"string","description":"Query resolution step width in \"duration\" format or float number of seconds.\n\nExample: \"\u0026step=15s\"\n"},"timeout":{"type":"string","description":"Evaluation timeout. Optional."}},"required":["query"]},"Query_rangeResp":{"title":"Query_rangeResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]}}}}`
+	rest.Oas = `{"openapi":"3.0.2","info":{"title":"Prom","version":"v20230118"},"servers":[{"url":"http://localhost:6060"}],"paths":{"/label/{label_name}/values":{"get":{"description":"GetLabel_Label_nameValues Returns label values\nThe following endpoint returns a list of label values for a provided label name\n\nThe \"data\" section of the JSON response is a list of string label values.\n","parameters":[{"name":"start","in":"query","description":"Start timestamp. Optional.\n","schema":{"type":"string","description":"Start timestamp. Optional.\n"}},{"name":"end","in":"query","description":"End timestamp. Optional.\n","schema":{"type":"string","description":"End timestamp. Optional.\n"}},{"name":"match","in":"query","description":"Repeated series selector argument that selects the series from which to read the label values. Optional.\n","schema":{"type":"array","items":{"type":"string"},"description":"Repeated series selector argument that selects the series from which to read the label values. Optional.\n"}},{"name":"labelname","in":"path","description":"Label name\n\nExample: \"/label/job/values\"\n\nrequired","required":true,"schema":{"type":"string","description":"Label name\n\nExample: \"/label/job/values\"\n\nrequired"}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetLabel_Label_nameValuesResp"}}}}}}},"/labels":{"get":{"description":"GetLabels is compatible to Prometheus GET /api/v1/labels\nThe following endpoint returns a list of label names.\n\nThe \"data\" section of the JSON response is a list of string label names.\n","parameters":[{"name":"match","in":"query","description":"Repeated series selector argument that selects the series from which to read the label names. Optional.\n","schema":{"type":"array","items":{"type":"string"},"description":"Repeated series selector argument that selects the series from which to read the label names. Optional.\n"}},{"name":"start","in":"query","description":"Start timestamp. Optional.\n","schema":{"type":"string","description":"Start timestamp. Optional.\n"}},{"name":"end","in":"query","description":"End timestamp. Optional.\n","schema":{"type":"string","description":"End timestamp. Optional.\n"}},{"name":"limit","in":"query","description":"Maximum number of returned label names. Optional. 0 means disabled.\n\nExample: \"\u0026limit=100\"\n","schema":{"type":"integer","format":"int32","description":"Maximum number of returned label names. Optional. 0 means disabled.\n\nExample: \"\u0026limit=100\"\n"}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetLabelsResp"}}}}}},"post":{"description":"Labels is compatible to Prometheus POST /api/v1/labels\nThe following endpoint returns a list of label names.\n\nThe \"data\" section of the JSON response is a list of string label names.\n","requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"$ref":"#/components/schemas/LabelsReq"}}}},"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/LabelsResp"}}}}}}},"/metadata":{"get":{"description":"GetMetadata is compatible to Prometheus GET /api/v1/metadata\nThe following endpoint returns metadata about metrics. As InfluxDB doesn't store metric metadata,\nmetric types are inferred from Prometheus naming conventions, and help and unit text are read from the metadata file.\n\nThe \"data\" section of the JSON response is an object where each key is a metric name and each value is a list of unique metadata objects.\n","parameters":[{"name":"limit","in":"query","description":"Maximum number of metrics to return. Optional.\n\nExample: \"?limit=2\"\n","schema":{"type":"integer","format":"int32","description":"Maximum number of metrics to return. Optional.\n\nExample: \"?limit=2\"\n"}},{"name":"metric","in":"query","description":"A metric name to filter metadata for. All metric metadata is retrieved if left empty. Optional.\n\nExample: \"?metric=http_requests_total\"\n","schema":{"type":"string","description":"A metric name to filter metadata for. All metric metadata is retrieved if left empty. Optional.\n\nExample: \"?metric=http_requests_total\"\n"}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetMetadataResp"}}}}}}},"/query":{"get":{"description":"GetQuery is compatible to Prometheus GET /api/v1/query","parameters":[{"name":"query","in":"query","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired","required":true,"schema":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"}},{"name":"time","in":"query","description":"Evaluation timestamp. Optional.\n\nThe current server time is used if the \"time\" parameter is omitted.\n\nOptional.","schema":{"type":"string","description":"Evaluation timestamp. Optional.\n\nThe current server time is used if the \"time\" parameter is omitted.\n\nOptional."}},{"name":"timezone","in":"query","description":"IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.\n\nDefault is the X-Timezone header, then the datasource's setting.\n\nExample: \"\u0026timezone=Europe/Berlin\"\n","schema":{"type":"string","description":"IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.\n\nDefault is the X-Timezone header, then the datasource's setting.\n\nExample: \"\u0026timezone=Europe/Berlin\"\n"}},{"name":"timeout","in":"query","description":"Evaluation timeout. Optional.","schema":{"type":"string","description":"Evaluation timeout. Optional."}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetQueryResp"}}}}}},"post":{"description":"Query is compatible to Prometheus POST /api/v1/query","requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"$ref":"#/components/schemas/QueryReq"}}},"required":true},"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/QueryResp"}}}}}}},"/query_range":{"get":{"description":"GetQuery_range is compatible to Prometheus GET /api/v1/query_range","parameters":[{"name":"query","in":"query","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired","required":true,"schema":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"}},{"name":"start","in":"query","description":"Start timestamp.\n\nExample: \"\u0026start=2015-07-01T20:10:30.781Z\"\n","schema":{"type":"string","description":"Start timestamp.\n\nExample: \"\u0026start=2015-07-01T20:10:30.781Z\"\n"}},{"name":"end","in":"query","description":"End timestamp.\n\nExample: \"\u0026end=2015-07-01T20:11:00.781Z\"\n","schema":{"type":"string","description":"End timestamp.\n\nExample: \"\u0026end=2015-07-01T20:11:00.781Z\"\n"}},{"name":"step","in":"query","description":"Query resolution step width in \"duration\" format or float number of seconds.\n\nExample: \"\u0026step=15s\"\n","schema":{"type":"string","description":"Query resolution step width in \"duration\" format or float number of seconds.\n\nExample: \"\u0026step=15s\"\n"}},{"name":"fill","in":"query","description":"How empty steps are filled: none, null, previous or linear. Optional.\n\nDefault is the datasource's setting.\n\nExample: \"\u0026fill=previous\"\n","schema":{"type":"string","description":"How empty steps are filled: none, null, previous or linear. Optional.\n\nDefault is the datasource's setting.\n\nExample: \"\u0026fill=previous\"\n"}},{"name":"timezone","in":"query","description":"IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.\n\nDefault is the X-Timezone header, then the datasource's setting.\n\nExample: \"\u0026timezone=Europe/Berlin\"\n","schema":{"type":"string","description":"IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.\n\nDefault is the X-Timezone header, then the datasource's setting.\n\nExample: \"\u0026timezone=Europe/Berlin\"\n"}},{"name":"timeout","in":"query","description":"Evaluation timeout. Optional.","schema":{"type":"string","description":"Evaluation timeout. Optional."}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetQuery_rangeResp"}}}}}},"post":{"description":"Query_range is compatible to Prometheus POST /api/v1/query_range","requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"$ref":"#/components/schemas/Query_rangeReq"}}},"required":true},"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Query_rangeResp"}}}}}}},"/series":{"get":{"description":"GetSeries is compatible to Prometheus GET /api/v1/series\nThe following endpoint returns the list of time series that match a certain label set.\n\nThe \"data\" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.\n","parameters":[{"name":"match","in":"query","description":"Repeated series selector argument that selects the series to return. At least one match[] argument must be provided.\n\nExample: \"?match[]=up\u0026match[]=process_start_time_seconds{job=\\\"prometheus\\\"}\"\n\nrequired","required":true,"schema":{"type":"array","items":{"type":"string"},"description":"Repeated series selector argument that selects the series to return. At least one match[] argument must be provided.\n\nExample: \"?match[]=up\u0026match[]=process_start_time_seconds{job=\\\"prometheus\\\"}\"\n\nrequired"}},{"name":"start","in":"query","description":"Start timestamp. Optional.\n","schema":{"type":"string","description":"Start timestamp. Optional.\n"}},{"name":"end","in":"query","description":"End timestamp. Optional.\n","schema":{"type":"string","description":"End timestamp. Optional.\n"}}],"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/GetSeriesResp"}}}}}},"post":{"description":"Series is compatible to Prometheus POST /api/v1/series\nThe following endpoint returns the list of time series that match a certain label set.\n\nThe \"data\" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.\n","requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"$ref":"#/components/schemas/SeriesReq"}}},"required":true},"responses":{"200":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/SeriesResp"}}}}}}}},"components":{"schemas":{"GetLabel_Label_nameValuesResp":{"title":"GetLabel_Label_nameValuesResp","type":"object","properties":{"data":{"type":"array","items":{"type":"string"}},"status":{"type":"string"}},"required":["data","status"]},"GetLabelsResp":{"title":"GetLabelsResp","type":"object","properties":{"data":{"type":"array","items":{"type":"string"}},"status":{"type":"string"}},"required":["data","status"]},"GetMetadataResp":{"title":"GetMetadataResp","type":"object","properties":{"data":{"type":"object","additionalProperties":{"type":"array","items":{"$ref":"#/components/schemas/MetricMetadata"}}},"status":{"type":"string"}},"required":["data","status"]},"GetQueryResp":{"title":"GetQueryResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"GetQuery_rangeResp":{"title":"GetQuery_rangeResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"GetSeriesResp":{"title":"GetSeriesResp","type":"object","properties":{"data":{"type":"array","items":{"type":"object","additionalProperties":{"type":"string"}}},"status":{"type":"string"}},"required":["data","status"]},"LabelsReq":{"title":"LabelsReq","type":"object","properties":{"end":{"type":"string","description":"End timestamp. Optional.\n"},"limit":{"type":"integer","format":"int32","description":"Maximum number of returned label names. Optional. 0 means disabled.\n\nExample: \"\u0026limit=100\"\n"},"match":{"type":"array","items":{"type":"string"},"description":"Repeated series selector argument that selects the series from which to read the label names. Optional.\n"},"start":{"type":"string","description":"Start timestamp. Optional.\n"}}},"LabelsResp":{"title":"LabelsResp","type":"object","properties":{"data":{"type":"array","items":{"type":"string"}},"status":{"type":"string"}},"required":["data","status"]},"MetricMetadata":{"title":"MetricMetadata","type":"object","properties":{"type":{"type":"string"},"help":{"type":"string"},"unit":{"type":"string"}},"required":["type","help","unit"]},"QueryData":{"title":"QueryData","type":"object","properties":{"result":{"type":"object"},"resultType":{"type":"string"}},"description":"\n\n\n\n","required":["result","resultType"]},"QueryReq":{"title":"QueryReq","type":"object","properties":{"query":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"},"time":{"type":"string","description":"Evaluation timestamp. Optional.\n\nThe current server time is used if the \"time\" parameter is omitted.\n\nOptional."},"timezone":{"type":"string","description":"IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.\n\nDefault is the X-Timezone header, then the datasource's setting.\n\nExample: \"\u0026timezone=Europe/Berlin\"\n"},"timeout":{"type":"string","description":"Evaluation timeout. Optional."}},"required":["query"]},"QueryResp":{"title":"QueryResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"QueryResponse":{"title":"QueryResponse","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"Query_rangeReq":{"title":"Query_rangeReq","type":"object","properties":{"end":{"type":"string","description":"End timestamp.\n\nExample: \"\u0026end=2015-07-01T20:11:00.781Z\"\n"},"query":{"type":"string","description":"Prometheus expression query string.\n\nExample: \"?query=up\"\n\nrequired"},"start":{"type":"string","description":"Start timestamp.\n\nExample: \"\u0026start=2015-07-01T20:10:30.781Z\"\n"},"step":{"type":"string","description":"Query resolution step width in \"duration\" format or float number of seconds.\n\nExample: \"\u0026step=15s\"\n"},"fill":{"type":"string","description":"How empty steps are filled: none, null, previous or linear. Optional.\n\nDefault is the datasource's setting.\n\nExample: \"\u0026fill=previous\"\n"},"timezone":{"type":"string","description":"IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.\n\nDefault is the X-Timezone header, then the datasource's setting.\n\nExample: \"\u0026timezone=Europe/Berlin\"\n"},"timeout":{"type":"string","description":"Evaluation timeout. Optional."}},"required":["query"]},"Query_rangeResp":{"title":"Query_rangeResp","type":"object","properties":{"data":{"$ref":"#/components/schemas/QueryData"},"status":{"type":"string"}},"required":["data","status"]},"SeriesReq":{"title":"SeriesReq","type":"object","properties":{"end":{"type":"string","description":"End timestamp. Optional.\n"},"match":{"type":"array","items":{"type":"string"},"description":"Repeated series selector argument that selects the series to return. At least one match[] argument must be provided.\n\nExample: \"?match[]=up\u0026match[]=process_start_time_seconds{job=\\\"prometheus\\\"}\"\n\nrequired"},"start":{"type":"string","description":"Start timestamp. Optional.\n"}},"required":["match"]},"SeriesResp":{"title":"SeriesResp","type":"object","properties":{"data":{"type":"array","items":{"type":"object","additionalProperties":{"type":"string"}}},"status":{"type":"string"}},"required":["data","status"]}}}}`
 }
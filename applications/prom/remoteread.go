@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
+	"github.com/wubin1989/promql2influxql/applications"
+	"sort"
+	"time"
+)
+
+// RemoteReader serves queries of Prometheus remote read protocol. It is not a part of Prom interface,
+// because request and response bodies of remote read are snappy-compressed protobuf messages rather than JSON.
+type RemoteReader interface {
+	// RemoteRead returns the series selected by query sorted by labels
+	RemoteRead(ctx context.Context, query *prompb.Query) (storage.SeriesSet, error)
+}
+
+var _ RemoteReader = (*PromImpl)(nil)
+
+// RemoteRead selects raw samples of the series matched by query within its time range. If query has hints,
+// the time range of the hints is used instead as Prometheus TSDB does, and only label sets are selected for series function.
+//
+// Each metric name selected by the matchers is queried as a vector selector, so the matchers are transpiled
+// by the same selector logic as PromQL queries. Metric names are listed in advance unless there is an equality __name__ matcher.
+func (receiver *PromImpl) RemoteRead(ctx context.Context, query *prompb.Query) (storage.SeriesSet, error) {
+	matchers, err := remote.FromLabelMatchers(query.Matchers)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	startMs, endMs := query.StartTimestampMs, query.EndTimestampMs
+	seriesOnly := false
+	if hints := query.Hints; hints != nil {
+		if hints.StartMs != 0 || hints.EndMs != 0 {
+			startMs, endMs = hints.StartMs, hints.EndMs
+		}
+		seriesOnly = hints.Func == "series"
+	}
+	startTime, endTime := time.UnixMilli(startMs), time.UnixMilli(endMs)
+	location, err := parseTimezoneParam(ctx, nil, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	metricNames, err := receiver.selectedMetricNames(ctx, matchers)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	// Metric names have been checked against all __name__ matchers, and PromQL doesn't allow them together with a metric name
+	var labelMatchers []*labels.Matcher
+	for _, item := range matchers {
+		if item.Name != labels.MetricName {
+			labelMatchers = append(labelMatchers, item)
+		}
+	}
+	var matrix promql.Matrix
+	for _, metricName := range metricNames {
+		cmd := applications.PromCommand{
+			Cmd: (&parser.VectorSelector{
+				Name:          metricName,
+				LabelMatchers: labelMatchers,
+			}).String(),
+			Database:  receiver.conf.BizConf.AdaptorInfluxDatabase,
+			Start:     &startTime,
+			End:       &endTime,
+			Timezone:  location,
+			QueryType: applications.RANGE_QUERY,
+			DataType:  applications.TABLE_DATA,
+		}
+		if seriesOnly {
+			cmd.DataType = applications.SERIES_DATA
+		}
+		runResult, err := receiver.adaptor.Query(ctx, cmd)
+		if err != nil {
+			return nil, errors.Wrap(err, caller.NewCaller().String())
+		}
+		switch result := runResult.Result.(type) {
+		case promql.Matrix:
+			matrix = append(matrix, result...)
+		case []labels.Labels:
+			for _, item := range result {
+				matrix = append(matrix, promql.Series{
+					Metric: item,
+				})
+			}
+		}
+	}
+	// The streamed response type requires series sorted by labels
+	sort.Sort(matrix)
+	return &matrixSeriesSet{
+		matrix: matrix,
+		index:  -1,
+	}, nil
+}
+
+// selectedMetricNames returns metric names selected by matchers. All __name__ matchers are checked
+// as there may be more than one of them.
+func (receiver *PromImpl) selectedMetricNames(ctx context.Context, matchers []*labels.Matcher) ([]string, error) {
+	var metricNames []string
+	for _, item := range matchers {
+		if item.Name == labels.MetricName && item.Type == labels.MatchEqual {
+			metricNames = []string{item.Value}
+			break
+		}
+	}
+	if metricNames == nil {
+		runResult, err := receiver.adaptor.Query(ctx, applications.PromCommand{
+			Cmd:       (&parser.VectorSelector{LabelMatchers: matchers}).String(),
+			Database:  receiver.conf.BizConf.AdaptorInfluxDatabase,
+			DataType:  applications.LABEL_VALUES_DATA,
+			LabelName: labels.MetricName,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, caller.NewCaller().String())
+		}
+		metricNames, _ = runResult.Result.([]string)
+	}
+	selected := metricNames[:0]
+NAMES:
+	for _, metricName := range metricNames {
+		if metricName == "" {
+			continue
+		}
+		for _, item := range matchers {
+			if item.Name == labels.MetricName && !item.Matches(metricName) {
+				continue NAMES
+			}
+		}
+		selected = append(selected, metricName)
+	}
+	return selected, nil
+}
+
+// selectedVectorSelectors returns a vector selector for each metric name selected by matchers, together with the other matchers,
+// because the adaptor queries the measurement of the metric name of a vector selector, and selectors without metric name
+// or selecting metric names by regex can't be transpiled directly.
+func (receiver *PromImpl) selectedVectorSelectors(ctx context.Context, matchers []*labels.Matcher) ([]string, error) {
+	metricNames, err := receiver.selectedMetricNames(ctx, matchers)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	var labelMatchers []*labels.Matcher
+	for _, item := range matchers {
+		if item.Name != labels.MetricName {
+			labelMatchers = append(labelMatchers, item)
+		}
+	}
+	selectors := make([]string, 0, len(metricNames))
+	for _, metricName := range metricNames {
+		selectors = append(selectors, (&parser.VectorSelector{
+			Name:          metricName,
+			LabelMatchers: labelMatchers,
+		}).String())
+	}
+	return selectors, nil
+}
+
+// matrixSeriesSet is storage.SeriesSet over the series of promql.Matrix
+type matrixSeriesSet struct {
+	matrix promql.Matrix
+	index  int
+}
+
+// Next implements storage.SeriesSet's Next method
+func (receiver *matrixSeriesSet) Next() bool {
+	receiver.index++
+	return receiver.index < len(receiver.matrix)
+}
+
+// At implements storage.SeriesSet's At method
+func (receiver *matrixSeriesSet) At() storage.Series {
+	series := receiver.matrix[receiver.index]
+	samples := make([]tsdbutil.Sample, 0, len(series.Points))
+	for _, point := range series.Points {
+		samples = append(samples, sample{
+			t: point.T,
+			v: point.V,
+		})
+	}
+	return storage.NewListSeries(series.Metric, samples)
+}
+
+// Err implements storage.SeriesSet's Err method
+func (receiver *matrixSeriesSet) Err() error {
+	return nil
+}
+
+// Warnings implements storage.SeriesSet's Warnings method
+func (receiver *matrixSeriesSet) Warnings() storage.Warnings {
+	return nil
+}
+
+// sample is tsdbutil.Sample of float value
+type sample struct {
+	t int64
+	v float64
+}
+
+func (receiver sample) T() int64 {
+	return receiver.t
+}
+
+func (receiver sample) V() float64 {
+	return receiver.v
+}
+
+func (receiver sample) H() *histogram.Histogram {
+	return nil
+}
+
+func (receiver sample) FH() *histogram.FloatHistogram {
+	return nil
+}
+
+func (receiver sample) Type() chunkenc.ValueType {
+	return chunkenc.ValFloat
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// remoteReadAdaptor answers metric names and raw samples queries of remote read
+type remoteReadAdaptor struct {
+	cmds []applications.PromCommand
+}
+
+func (receiver *remoteReadAdaptor) Query(ctx context.Context, cmd applications.PromCommand) (applications.RunResult, error) {
+	receiver.cmds = append(receiver.cmds, cmd)
+	switch cmd.DataType {
+	case applications.LABEL_VALUES_DATA:
+		return applications.RunResult{
+			Result: []string{"go_gc_duration_seconds", "go_goroutines", "go_threads"},
+		}, nil
+	case applications.SERIES_DATA:
+		return applications.RunResult{
+			Result: []labels.Labels{labels.FromStrings(labels.MetricName, "go_goroutines", "job", "prometheus")},
+		}, nil
+	}
+	metricName := "go_goroutines"
+	if cmd.Cmd == `go_threads{job="prometheus"}` {
+		metricName = "go_threads"
+	}
+	return applications.RunResult{
+		Result: promql.Matrix{
+			{
+				Metric: labels.FromStrings(labels.MetricName, metricName, "job", "prometheus"),
+				Points: []promql.Point{{T: 1000, V: 1}, {T: 2000, V: 2}},
+			},
+		},
+	}, nil
+}
+
+func (receiver *remoteReadAdaptor) QueryStream(ctx context.Context, cmd applications.PromCommand, send func(result applications.RunResult) error) error {
+	return nil
+}
+
+func TestPromImpl_RemoteRead(t *testing.T) {
+	query := &prompb.Query{
+		StartTimestampMs: 0,
+		EndTimestampMs:   3000,
+		Matchers: []*prompb.LabelMatcher{
+			{Type: prompb.LabelMatcher_RE, Name: labels.MetricName, Value: "go_.*"},
+			{Type: prompb.LabelMatcher_NEQ, Name: labels.MetricName, Value: "go_gc_duration_seconds"},
+			{Type: prompb.LabelMatcher_EQ, Name: "job", Value: "prometheus"},
+		},
+	}
+	adaptor := &remoteReadAdaptor{}
+	receiver := NewProm(&config.Config{}, adaptor)
+	seriesSet, err := receiver.RemoteRead(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := remote.ToQueryResult(seriesSet, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &prompb.QueryResult{
+		Timeseries: []*prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: labels.MetricName, Value: "go_goroutines"}, {Name: "job", Value: "prometheus"}},
+				Samples: []prompb.Sample{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: labels.MetricName, Value: "go_threads"}, {Name: "job", Value: "prometheus"}},
+				Samples: []prompb.Sample{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoteRead() got = %v, want %v", got, want)
+	}
+	if len(adaptor.cmds) != 3 {
+		t.Fatalf("RemoteRead() issued %d queries, want 3", len(adaptor.cmds))
+	}
+	cmd := adaptor.cmds[1]
+	if cmd.Cmd != `go_goroutines{job="prometheus"}` {
+		t.Errorf("RemoteRead() cmd = %s", cmd.Cmd)
+	}
+	if cmd.QueryType != applications.RANGE_QUERY || cmd.DataType != applications.TABLE_DATA {
+		t.Errorf("RemoteRead() query type = %d, data type = %d", cmd.QueryType, cmd.DataType)
+	}
+	if !cmd.Start.Equal(time.UnixMilli(0)) || !cmd.End.Equal(time.UnixMilli(3000)) {
+		t.Errorf("RemoteRead() start = %v, end = %v", cmd.Start, cmd.End)
+	}
+}
+
+func TestPromImpl_RemoteRead_Series(t *testing.T) {
+	query := &prompb.Query{
+		StartTimestampMs: 0,
+		EndTimestampMs:   3000,
+		Matchers: []*prompb.LabelMatcher{
+			{Type: prompb.LabelMatcher_EQ, Name: labels.MetricName, Value: "go_goroutines"},
+		},
+		Hints: &prompb.ReadHints{
+			StartMs: 1000,
+			EndMs:   2000,
+			Func:    "series",
+		},
+	}
+	adaptor := &remoteReadAdaptor{}
+	receiver := NewProm(&config.Config{}, adaptor)
+	seriesSet, err := receiver.RemoteRead(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := remote.ToQueryResult(seriesSet, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &prompb.QueryResult{
+		Timeseries: []*prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: labels.MetricName, Value: "go_goroutines"}, {Name: "job", Value: "prometheus"}},
+				Samples: []prompb.Sample{},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoteRead() got = %v, want %v", got, want)
+	}
+	if len(adaptor.cmds) != 1 {
+		t.Fatalf("RemoteRead() issued %d queries, want 1", len(adaptor.cmds))
+	}
+	cmd := adaptor.cmds[0]
+	if cmd.DataType != applications.SERIES_DATA || !cmd.Start.Equal(time.UnixMilli(1000)) || !cmd.End.Equal(time.UnixMilli(2000)) {
+		t.Errorf("RemoteRead() data type = %d, start = %v, end = %v", cmd.DataType, cmd.Start, cmd.End)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
+	"github.com/wubin1989/promql2influxql/applications"
+)
+
+// RemoteWriter receives requests of Prometheus remote write protocol. It is not a part of Prom interface,
+// because request bodies of remote write are snappy-compressed protobuf messages rather than forms.
+type RemoteWriter interface {
+	// RemoteWrite writes float samples of req. The error wraps applications.ErrRejectedSamples if some samples are rejected as invalid.
+	RemoteWrite(ctx context.Context, req *prompb.WriteRequest) error
+}
+
+var _ RemoteWriter = (*PromImpl)(nil)
+
+// RemoteWrite writes float samples of req to the database of the service. Native histogram samples and exemplars are ignored, as the schemas of InfluxDB have no place for them.
+func (receiver *PromImpl) RemoteWrite(ctx context.Context, req *prompb.WriteRequest) error {
+	timeSeries := make([]applications.TimeSeries, 0, len(req.Timeseries))
+	for _, series := range req.Timeseries {
+		if len(series.Samples) == 0 {
+			continue
+		}
+		item := applications.TimeSeries{
+			Labels:  make([]applications.Label, 0, len(series.Labels)),
+			Samples: make([]applications.Sample, 0, len(series.Samples)),
+		}
+		for _, label := range series.Labels {
+			item.Labels = append(item.Labels, applications.Label{
+				Name:  label.Name,
+				Value: label.Value,
+			})
+		}
+		for _, sample := range series.Samples {
+			item.Samples = append(item.Samples, applications.Sample{
+				Timestamp: sample.Timestamp,
+				Value:     sample.Value,
+			})
+		}
+		timeSeries = append(timeSeries, item)
+	}
+	if err := receiver.write(ctx, timeSeries); err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
+}
+
+// write writes timeSeries to the database of the service through the adaptor, which must implement applications.IPromWriter
+func (receiver *PromImpl) write(ctx context.Context, timeSeries []applications.TimeSeries) error {
+	writer, ok := receiver.adaptor.(applications.IPromWriter)
+	if !ok {
+		return errors.New("adaptor doesn't support writing samples")
+	}
+	if err := writer.Write(ctx, applications.PromWriteCommand{
+		Database:   receiver.conf.BizConf.AdaptorInfluxDatabase,
+		TimeSeries: timeSeries,
+	}); err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	"reflect"
+	"testing"
+)
+
+// remoteWriteAdaptor records write commands of remote write
+type remoteWriteAdaptor struct {
+	remoteReadAdaptor
+	cmds []applications.PromWriteCommand
+}
+
+func (receiver *remoteWriteAdaptor) Write(ctx context.Context, cmd applications.PromWriteCommand) error {
+	receiver.cmds = append(receiver.cmds, cmd)
+	return nil
+}
+
+func TestPromImpl_RemoteWrite(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "go_goroutines"}, {Name: "job", Value: "prometheus"}},
+				Samples: []prompb.Sample{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+			},
+			{
+				Labels:     []prompb.Label{{Name: "__name__", Value: "http_request_duration_seconds"}},
+				Histograms: []prompb.Histogram{{Timestamp: 1000}},
+			},
+		},
+	}
+	adaptor := &remoteWriteAdaptor{}
+	receiver := NewProm(&config.Config{
+		BizConf: config.BizConfig{
+			AdaptorInfluxDatabase: "prometheus",
+		},
+	}, adaptor)
+	if err := receiver.RemoteWrite(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	want := []applications.PromWriteCommand{
+		{
+			Database: "prometheus",
+			TimeSeries: []applications.TimeSeries{
+				{
+					Labels:  []applications.Label{{Name: "__name__", Value: "go_goroutines"}, {Name: "job", Value: "prometheus"}},
+					Samples: []applications.Sample{{Timestamp: 1000, Value: 1}, {Timestamp: 2000, Value: 2}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(adaptor.cmds, want) {
+		t.Errorf("RemoteWrite() got = %v, want %v", adaptor.cmds, want)
+	}
+}
+
+func TestPromImpl_RemoteWrite_Unsupported(t *testing.T) {
+	receiver := NewProm(&config.Config{}, &remoteReadAdaptor{})
+	err := receiver.RemoteWrite(context.Background(), &prompb.WriteRequest{})
+	if err == nil || errors.Is(err, applications.ErrRejectedSamples) {
+		t.Errorf("RemoteWrite() error = %v", err)
+	}
+}
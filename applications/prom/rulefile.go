@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+)
+
+// RuleGroups is the content of a Prometheus rule file
+type RuleGroups struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a group of rules evaluated in order at the same interval. It is parsed by ourselves rather than
+// rulefmt of Prometheus, because rulefmt of the Prometheus version we depend on doesn't support keep_firing_for yet.
+type RuleGroup struct {
+	Name string `yaml:"name"`
+	// Interval defaults to BIZ_ADAPTOR_RULE_EVALUATION_INTERVAL
+	Interval model.Duration `yaml:"interval,omitempty"`
+	// Limit fails the evaluation of a rule yielding more series than it. Zero means no limit.
+	Limit int    `yaml:"limit,omitempty"`
+	Rules []Rule `yaml:"rules"`
+	// File is the rule file the group is loaded from
+	File string `yaml:"-"`
+}
+
+// Rule is either a recording rule or an alerting rule
+type Rule struct {
+	Record        string            `yaml:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           model.Duration    `yaml:"for,omitempty"`
+	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+}
+
+// LoadRuleFiles loads rule groups from files matching patterns in the order of file names.
+// Files are validated as Prometheus does, and the first invalid one fails the loading.
+func LoadRuleFiles(patterns []string) ([]RuleGroup, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rule file pattern %q", pattern)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	var groups []RuleGroup
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to read rule file")
+		}
+		loaded, err := parseRuleGroups(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rule file %s", file)
+		}
+		for i := range loaded {
+			loaded[i].File = file
+		}
+		groups = append(groups, loaded...)
+	}
+	return groups, nil
+}
+
+// parseRuleGroups parses and validates content of a rule file. Unknown fields are refused to catch typos early.
+func parseRuleGroups(content []byte) ([]RuleGroup, error) {
+	var ruleGroups RuleGroups
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&ruleGroups); err != nil {
+		return nil, errors.Wrap(err, "fail to parse rule file")
+	}
+	names := make(map[string]struct{})
+	for _, group := range ruleGroups.Groups {
+		if group.Name == "" {
+			return nil, errors.New("group name must not be empty")
+		}
+		if _, exists := names[group.Name]; exists {
+			return nil, errors.Errorf("group %q is defined more than once", group.Name)
+		}
+		names[group.Name] = struct{}{}
+		for i, rule := range group.Rules {
+			if err := validateRule(rule); err != nil {
+				return nil, errors.Wrapf(err, "group %q, rule %d", group.Name, i+1)
+			}
+		}
+	}
+	return ruleGroups.Groups, nil
+}
+
+func validateRule(rule Rule) error {
+	switch {
+	case rule.Record != "" && rule.Alert != "":
+		return errors.New("only one of record and alert must be set")
+	case rule.Record == "" && rule.Alert == "":
+		return errors.New("one of record or alert must be set")
+	case rule.Record != "":
+		if !model.IsValidMetricName(model.LabelValue(rule.Record)) {
+			return errors.Errorf("invalid recording rule name %q", rule.Record)
+		}
+		if rule.For != 0 || rule.KeepFiringFor != 0 || len(rule.Annotations) > 0 {
+			return errors.Errorf("invalid field for recording rule %q: for, keep_firing_for and annotations are only for alerting rules", rule.Record)
+		}
+	}
+	if _, err := parser.ParseExpr(rule.Expr); err != nil {
+		return errors.Wrapf(err, "invalid expr %q", rule.Expr)
+	}
+	for name := range rule.Labels {
+		if !model.LabelName(name).IsValid() || name == model.MetricNameLabel {
+			return errors.Errorf("invalid label name %q", name)
+		}
+	}
+	if rule.Record != "" {
+		return nil
+	}
+	// Labels and annotations of alerting rules are templates
+	for name, value := range rule.Labels {
+		if _, err := newRuleTemplate(name, value); err != nil {
+			return err
+		}
+	}
+	for name, value := range rule.Annotations {
+		if !model.LabelName(name).IsValid() {
+			return errors.Errorf("invalid annotation name %q", name)
+		}
+		if _, err := newRuleTemplate(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ruleTemplatePreamble defines $labels and $value in label and annotation templates like Prometheus does
+const ruleTemplatePreamble = "{{$labels := .Labels}}{{$value := .Value}}"
+
+// ruleTemplateData is the data of label and annotation templates
+type ruleTemplateData struct {
+	Labels map[string]string
+	Value  float64
+}
+
+func newRuleTemplate(name string, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(ruleTemplatePreamble + text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid template of %q", name)
+	}
+	return tmpl, nil
+}
+
+// expandRuleTemplate expands tmpl with data. Errors are expanded into the result as Prometheus does, so that alerts are still sent.
+func expandRuleTemplate(tmpl *template.Template, data ruleTemplateData) string {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "error expanding template: " + err.Error()
+	}
+	return buf.String()
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"github.com/prometheus/common/model"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadRuleFiles(t *testing.T) {
+	dir := t.TempDir()
+	content := `groups:
+  - name: example
+    interval: 30s
+    rules:
+      - record: job:go_goroutines:sum
+        expr: sum by (job) (go_goroutines)
+        labels:
+          env: prod
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        keep_firing_for: 10m
+        labels:
+          severity: page
+        annotations:
+          summary: "{{ $labels.instance }} down"
+`
+	if err := os.WriteFile(filepath.Join(dir, "example.rules.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadRuleFiles([]string{filepath.Join(dir, "*.yml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RuleGroup{
+		{
+			Name:     "example",
+			Interval: model.Duration(30 * time.Second),
+			Rules: []Rule{
+				{
+					Record: "job:go_goroutines:sum",
+					Expr:   "sum by (job) (go_goroutines)",
+					Labels: map[string]string{"env": "prod"},
+				},
+				{
+					Alert:         "InstanceDown",
+					Expr:          "up == 0",
+					For:           model.Duration(5 * time.Minute),
+					KeepFiringFor: model.Duration(10 * time.Minute),
+					Labels:        map[string]string{"severity": "page"},
+					Annotations:   map[string]string{"summary": "{{ $labels.instance }} down"},
+				},
+			},
+			File: filepath.Join(dir, "example.rules.yml"),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadRuleFiles() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_parseRuleGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules:\n      - record: a:b\n        expr: up\n",
+			wantErr: false,
+		},
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules:\n      - record: a:b\n        alert: A\n        expr: up\n",
+			wantErr: true,
+		},
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules:\n      - expr: up\n",
+			wantErr: true,
+		},
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules:\n      - alert: A\n        expr: up ==\n",
+			wantErr: true,
+		},
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules:\n      - record: a:b\n        expr: up\n        for: 1m\n",
+			wantErr: true,
+		},
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules:\n      - alert: A\n        expr: up\n        annotations:\n          summary: '{{ $labels.instance '\n",
+			wantErr: true,
+		},
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules:\n      - alert: A\n        expr: up\n        fore: 1m\n",
+			wantErr: true,
+		},
+		{
+			name:    "",
+			content: "groups:\n  - name: a\n    rules: []\n  - name: a\n    rules: []\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseRuleGroups([]byte(tt.content)); (err != nil) != tt.wantErr {
+				t.Errorf("parseRuleGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,513 @@
+package service
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/dto"
+	"sort"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	ALERT_STATE_INACTIVE = "inactive"
+	ALERT_STATE_PENDING  = "pending"
+	ALERT_STATE_FIRING   = "firing"
+
+	RULE_HEALTH_UNKNOWN = "unknown"
+	RULE_HEALTH_OK      = "ok"
+	RULE_HEALTH_ERR     = "err"
+
+	RULE_TYPE_ALERT  = "alert"
+	RULE_TYPE_RECORD = "record"
+
+	// defaultRuleEvaluationInterval is the evaluation interval of groups if neither the group nor BIZ_ADAPTOR_RULE_EVALUATION_INTERVAL sets it
+	defaultRuleEvaluationInterval = time.Minute
+	// alertResendDelay is the minimum interval of sending a firing alert again, same as Prometheus default
+	alertResendDelay = time.Minute
+	// resolvedAlertRetention is how long resolved alerts are kept for sending them to Alertmanager, same as Prometheus
+	resolvedAlertRetention = 15 * time.Minute
+)
+
+// RuleManager evaluates recording rules and alerting rules through the adaptor like Prometheus rule manager does.
+// Results of recording rules are written back to the database of the service, and alerts are sent by AlertNotifier.
+type RuleManager struct {
+	prom     *PromImpl
+	notifier AlertNotifier
+	groups   []*ruleGroupState
+}
+
+// ruleGroupState is a rule group with the states of its rules. Evaluation results are guarded by mutex as they are read by
+// the API while the group is being evaluated.
+type ruleGroupState struct {
+	RuleGroup
+	interval time.Duration
+	rules    []*ruleState
+
+	mutex          sync.RWMutex
+	evaluationTime time.Duration
+	lastEvaluation time.Time
+}
+
+type ruleState struct {
+	Rule
+	labelTemplates      map[string]*template.Template
+	annotationTemplates map[string]*template.Template
+
+	health         string
+	lastError      error
+	evaluationTime time.Duration
+	lastEvaluation time.Time
+	// active are alerts of alerting rules keyed by their labels, including resolved ones kept for sending
+	active map[string]*ruleAlert
+}
+
+type ruleAlert struct {
+	labels      labels.Labels
+	annotations map[string]string
+	state       string
+	value       float64
+	activeAt    time.Time
+	resolvedAt  time.Time
+	lastSentAt  time.Time
+	validUntil  time.Time
+	// keepFiringSince is when the alert has stopped being returned by the expression but keeps firing because of keep_firing_for
+	keepFiringSince time.Time
+}
+
+// NewRuleManager creates RuleManager evaluating groups through the adaptor of prom. Notifier may be nil if alerts are not sent.
+func NewRuleManager(prom *PromImpl, groups []RuleGroup, notifier AlertNotifier) (*RuleManager, error) {
+	manager := &RuleManager{
+		prom:     prom,
+		notifier: notifier,
+	}
+	for _, group := range groups {
+		state := &ruleGroupState{
+			RuleGroup: group,
+			interval:  time.Duration(group.Interval),
+		}
+		if state.interval <= 0 {
+			state.interval = prom.conf.BizConf.AdaptorRuleEvaluationInterval
+		}
+		if state.interval <= 0 {
+			state.interval = defaultRuleEvaluationInterval
+		}
+		for _, rule := range group.Rules {
+			item := &ruleState{
+				Rule:   rule,
+				health: RULE_HEALTH_UNKNOWN,
+			}
+			if rule.Alert != "" {
+				item.active = make(map[string]*ruleAlert)
+				item.labelTemplates = make(map[string]*template.Template)
+				item.annotationTemplates = make(map[string]*template.Template)
+				for name, text := range rule.Labels {
+					tmpl, err := newRuleTemplate(name, text)
+					if err != nil {
+						return nil, errors.Wrapf(err, "group %q, alert %q", group.Name, rule.Alert)
+					}
+					item.labelTemplates[name] = tmpl
+				}
+				for name, text := range rule.Annotations {
+					tmpl, err := newRuleTemplate(name, text)
+					if err != nil {
+						return nil, errors.Wrapf(err, "group %q, alert %q", group.Name, rule.Alert)
+					}
+					item.annotationTemplates[name] = tmpl
+				}
+			}
+			state.rules = append(state.rules, item)
+		}
+		manager.groups = append(manager.groups, state)
+	}
+	return manager, nil
+}
+
+// Run evaluates every group at its interval until ctx is done. Groups are evaluated concurrently, and rules of a group in order.
+func (receiver *RuleManager) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, group := range receiver.groups {
+		wg.Add(1)
+		go func(group *ruleGroupState) {
+			defer wg.Done()
+			ticker := time.NewTicker(group.interval)
+			defer ticker.Stop()
+			for {
+				receiver.evalGroup(ctx, group, time.Now())
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(group)
+	}
+	wg.Wait()
+}
+
+// evalGroup evaluates rules of group at ts, then sends alerts of the group
+func (receiver *RuleManager) evalGroup(ctx context.Context, group *ruleGroupState, ts time.Time) {
+	start := time.Now()
+	for _, rule := range group.rules {
+		ruleStart := time.Now()
+		vector, err := receiver.query(ctx, rule.Expr, ts)
+		if err == nil && group.Limit > 0 && len(vector) > group.Limit {
+			err = errors.Errorf("exceeded limit of %d with %d series", group.Limit, len(vector))
+		}
+		if err == nil {
+			if rule.Record != "" {
+				err = receiver.record(ctx, rule, vector, ts)
+			} else {
+				group.mutex.Lock()
+				err = receiver.alert(group, rule, vector, ts)
+				group.mutex.Unlock()
+			}
+		}
+		group.mutex.Lock()
+		rule.health, rule.lastError = RULE_HEALTH_OK, err
+		if err != nil {
+			rule.health = RULE_HEALTH_ERR
+		}
+		rule.evaluationTime, rule.lastEvaluation = time.Since(ruleStart), ts
+		group.mutex.Unlock()
+		if err != nil {
+			zlogger.Error().Err(err).Msgf("fail to evaluate rule %s%s of group %s", rule.Record, rule.Alert, group.Name)
+		}
+	}
+	group.mutex.Lock()
+	group.evaluationTime, group.lastEvaluation = time.Since(start), ts
+	group.mutex.Unlock()
+	receiver.sendAlerts(ctx, group, ts)
+}
+
+// query evaluates expr as an instant query at ts bounded by BIZ_ADAPTOR_RULE_LOOKBACK_DELTA, so that series stopping being
+// written are not selected forever. Scalar results are converted to a single sample without labels as Prometheus does.
+func (receiver *RuleManager) query(ctx context.Context, expr string, ts time.Time) (promql.Vector, error) {
+	bizConf := receiver.prom.conf.BizConf
+	rateMode, err := parseRateMode(bizConf.AdaptorRateMode)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	location, err := parseTimezoneParam(ctx, nil, bizConf.AdaptorTimezone)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	runResult, err := receiver.prom.adaptor.Query(ctx, applications.PromCommand{
+		Cmd:           expr,
+		Database:      bizConf.AdaptorInfluxDatabase,
+		Evaluation:    &ts,
+		Timezone:      location,
+		QueryType:     applications.INSTANT_QUERY,
+		LookbackDelta: bizConf.AdaptorRuleLookbackDelta,
+		RateMode:      rateMode,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	switch result := runResult.Result.(type) {
+	case promql.Vector:
+		return result, nil
+	case promql.Scalar:
+		return promql.Vector{{Point: promql.Point{T: result.T, V: result.V}}}, nil
+	case nil:
+		return nil, nil
+	}
+	return nil, errors.Errorf("rule result is not a vector or scalar: %T", runResult.Result)
+}
+
+// record writes samples of vector at ts with metric name replaced by the name of the recording rule and its labels applied
+func (receiver *RuleManager) record(ctx context.Context, rule *ruleState, vector promql.Vector, ts time.Time) error {
+	timeSeries := make([]applications.TimeSeries, 0, len(vector))
+	seen := make(map[string]struct{})
+	for _, sample := range vector {
+		builder := labels.NewBuilder(sample.Metric).Set(labels.MetricName, rule.Record)
+		for name, value := range rule.Labels {
+			builder.Set(name, value)
+		}
+		metric := builder.Labels(nil)
+		key := metric.String()
+		if _, exists := seen[key]; exists {
+			return errors.New("vector contains metrics with the same labelset after applying rule labels")
+		}
+		seen[key] = struct{}{}
+		item := applications.TimeSeries{
+			Labels: make([]applications.Label, 0, len(metric)),
+			Samples: []applications.Sample{
+				{
+					Timestamp: ts.UnixMilli(),
+					Value:     sample.V,
+				},
+			},
+		}
+		for _, label := range metric {
+			item.Labels = append(item.Labels, applications.Label{
+				Name:  label.Name,
+				Value: label.Value,
+			})
+		}
+		timeSeries = append(timeSeries, item)
+	}
+	if len(timeSeries) == 0 {
+		return nil
+	}
+	if err := receiver.prom.write(ctx, timeSeries); err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
+}
+
+// alert updates alerts of rule by vector evaluated at ts like Prometheus alerting rules do:
+//   - new alerts are pending, and become firing after being returned by the expression for the duration of for
+//   - pending alerts not returned any more are deleted
+//   - firing alerts not returned any more keep firing for the duration of keep_firing_for, then are resolved
+//   - resolved alerts are kept for resolvedAlertRetention so that they are sent to Alertmanager
+//
+// The caller must hold the lock of group.
+func (receiver *RuleManager) alert(group *ruleGroupState, rule *ruleState, vector promql.Vector, ts time.Time) error {
+	seen := make(map[string]struct{})
+	for _, sample := range vector {
+		data := ruleTemplateData{
+			Labels: sample.Metric.Map(),
+			Value:  sample.V,
+		}
+		delete(data.Labels, labels.MetricName)
+		builder := labels.NewBuilder(sample.Metric).Del(labels.MetricName)
+		for name, tmpl := range rule.labelTemplates {
+			builder.Set(name, expandRuleTemplate(tmpl, data))
+		}
+		builder.Set(labels.AlertName, rule.Alert)
+		metric := builder.Labels(nil)
+		annotations := make(map[string]string, len(rule.annotationTemplates))
+		for name, tmpl := range rule.annotationTemplates {
+			annotations[name] = expandRuleTemplate(tmpl, data)
+		}
+		key := metric.String()
+		if _, exists := seen[key]; exists {
+			return errors.New("vector contains metrics with the same labelset after applying alert labels")
+		}
+		seen[key] = struct{}{}
+		if item, ok := rule.active[key]; ok && item.state != ALERT_STATE_INACTIVE {
+			item.value, item.annotations = sample.V, annotations
+			item.keepFiringSince = time.Time{}
+			continue
+		}
+		rule.active[key] = &ruleAlert{
+			labels:      metric,
+			annotations: annotations,
+			state:       ALERT_STATE_PENDING,
+			value:       sample.V,
+			activeAt:    ts,
+		}
+	}
+	for key, item := range rule.active {
+		if _, ok := seen[key]; !ok {
+			switch item.state {
+			case ALERT_STATE_PENDING:
+				delete(rule.active, key)
+			case ALERT_STATE_INACTIVE:
+				if ts.Sub(item.resolvedAt) > resolvedAlertRetention {
+					delete(rule.active, key)
+				}
+			case ALERT_STATE_FIRING:
+				if rule.KeepFiringFor > 0 {
+					if item.keepFiringSince.IsZero() {
+						item.keepFiringSince = ts
+					}
+					if ts.Sub(item.keepFiringSince) < time.Duration(rule.KeepFiringFor) {
+						continue
+					}
+				}
+				item.state, item.resolvedAt, item.keepFiringSince = ALERT_STATE_INACTIVE, ts, time.Time{}
+			}
+			continue
+		}
+		if item.state == ALERT_STATE_PENDING && ts.Sub(item.activeAt) >= time.Duration(rule.For) {
+			item.state = ALERT_STATE_FIRING
+		}
+	}
+	// Alertmanager resolves firing alerts by itself if they are not sent again before validUntil
+	validFor := 4 * alertResendDelay
+	if 4*group.interval > validFor {
+		validFor = 4 * group.interval
+	}
+	for _, item := range rule.active {
+		if item.state == ALERT_STATE_FIRING {
+			item.validUntil = ts.Add(validFor)
+		}
+	}
+	return nil
+}
+
+// sendAlerts sends firing alerts not sent within alertResendDelay and resolved alerts not sent since being resolved.
+// Alerts failed to be sent are sent again at the next evaluation.
+func (receiver *RuleManager) sendAlerts(ctx context.Context, group *ruleGroupState, ts time.Time) {
+	if receiver.notifier == nil {
+		return
+	}
+	var (
+		alerts []PostableAlert
+		sent   []*ruleAlert
+	)
+	group.mutex.RLock()
+	for _, rule := range group.rules {
+		for _, item := range rule.active {
+			switch {
+			case item.state == ALERT_STATE_PENDING:
+				continue
+			case item.state == ALERT_STATE_INACTIVE && !item.resolvedAt.After(item.lastSentAt):
+				continue
+			case item.state == ALERT_STATE_FIRING && item.lastSentAt.Add(alertResendDelay).After(ts):
+				continue
+			}
+			alert := PostableAlert{
+				Labels:      item.labels.Map(),
+				Annotations: item.annotations,
+				StartsAt:    item.activeAt,
+				EndsAt:      item.validUntil,
+			}
+			if item.state == ALERT_STATE_INACTIVE {
+				alert.EndsAt = item.resolvedAt
+			}
+			alerts = append(alerts, alert)
+			sent = append(sent, item)
+		}
+	}
+	group.mutex.RUnlock()
+	if len(alerts) == 0 {
+		return
+	}
+	if err := receiver.notifier.Notify(ctx, alerts); err != nil {
+		zlogger.Error().Err(err).Msgf("fail to send %d alerts of group %s", len(alerts), group.Name)
+		return
+	}
+	group.mutex.Lock()
+	for _, item := range sent {
+		item.lastSentAt = ts
+	}
+	group.mutex.Unlock()
+}
+
+// RuleGroups returns states of rule groups like Prometheus /api/v1/rules does. ruleType filters rules, one of alert and record,
+// and empty means both.
+func (receiver *RuleManager) RuleGroups(ruleType string) (dto.RuleDiscovery, error) {
+	if ruleType != "" && ruleType != RULE_TYPE_ALERT && ruleType != RULE_TYPE_RECORD {
+		return dto.RuleDiscovery{}, errors.Wrapf(ErrBadRequest, "invalid type %q, must be alert or record", ruleType)
+	}
+	discovery := dto.RuleDiscovery{
+		Groups: make([]dto.RuleGroup, 0, len(receiver.groups)),
+	}
+	for _, group := range receiver.groups {
+		group.mutex.RLock()
+		item := dto.RuleGroup{
+			Name:           group.Name,
+			File:           group.File,
+			Rules:          make([]interface{}, 0, len(group.rules)),
+			Interval:       group.interval.Seconds(),
+			Limit:          group.Limit,
+			EvaluationTime: group.evaluationTime.Seconds(),
+			LastEvaluation: group.lastEvaluation,
+		}
+		for _, rule := range group.rules {
+			var lastError string
+			if rule.lastError != nil {
+				lastError = rule.lastError.Error()
+			}
+			if rule.Record != "" {
+				if ruleType == RULE_TYPE_ALERT {
+					continue
+				}
+				item.Rules = append(item.Rules, dto.RecordingRule{
+					Name:           rule.Record,
+					Query:          rule.Expr,
+					Labels:         rule.Labels,
+					Health:         rule.health,
+					LastError:      lastError,
+					EvaluationTime: rule.evaluationTime.Seconds(),
+					LastEvaluation: rule.lastEvaluation,
+					Type:           "recording",
+				})
+				continue
+			}
+			if ruleType == RULE_TYPE_RECORD {
+				continue
+			}
+			alerts := activeAlerts(rule)
+			state := ALERT_STATE_INACTIVE
+			for _, alert := range alerts {
+				if alert.State == ALERT_STATE_FIRING || state == ALERT_STATE_INACTIVE {
+					state = alert.State
+				}
+			}
+			item.Rules = append(item.Rules, dto.AlertingRule{
+				State:          state,
+				Name:           rule.Alert,
+				Query:          rule.Expr,
+				Duration:       time.Duration(rule.For).Seconds(),
+				KeepFiringFor:  time.Duration(rule.KeepFiringFor).Seconds(),
+				Labels:         rule.Labels,
+				Annotations:    rule.Annotations,
+				Alerts:         alerts,
+				Health:         rule.health,
+				LastError:      lastError,
+				EvaluationTime: rule.evaluationTime.Seconds(),
+				LastEvaluation: rule.lastEvaluation,
+				Type:           "alerting",
+			})
+		}
+		group.mutex.RUnlock()
+		discovery.Groups = append(discovery.Groups, item)
+	}
+	return discovery, nil
+}
+
+// Alerts returns pending and firing alerts of all alerting rules like Prometheus /api/v1/alerts does
+func (receiver *RuleManager) Alerts() dto.AlertDiscovery {
+	discovery := dto.AlertDiscovery{
+		Alerts: make([]dto.Alert, 0),
+	}
+	for _, group := range receiver.groups {
+		group.mutex.RLock()
+		for _, rule := range group.rules {
+			discovery.Alerts = append(discovery.Alerts, activeAlerts(rule)...)
+		}
+		group.mutex.RUnlock()
+	}
+	return discovery
+}
+
+// activeAlerts returns pending and firing alerts of rule sorted by labels. The caller must hold the lock of the group.
+func activeAlerts(rule *ruleState) []dto.Alert {
+	var items []*ruleAlert
+	for _, item := range rule.active {
+		if item.state != ALERT_STATE_INACTIVE {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return labels.Compare(items[i].labels, items[j].labels) < 0
+	})
+	alerts := make([]dto.Alert, 0, len(items))
+	for _, item := range items {
+		activeAt := item.activeAt
+		alert := dto.Alert{
+			Labels:      item.labels.Map(),
+			Annotations: item.annotations,
+			State:       item.state,
+			ActiveAt:    &activeAt,
+			Value:       strconv.FormatFloat(item.value, 'e', 10, 64),
+		}
+		if !item.keepFiringSince.IsZero() {
+			keepFiringSince := item.keepFiringSince
+			alert.KeepFiringSince = &keepFiringSince
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
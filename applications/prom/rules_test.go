@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/wubin1989/promql2influxql/applications"
+	"github.com/wubin1989/promql2influxql/applications/prom/config"
+	"github.com/wubin1989/promql2influxql/applications/prom/dto"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ruleAdaptor answers rule expressions with the vectors set by the test, and records write commands of recording rules
+type ruleAdaptor struct {
+	remoteWriteAdaptor
+	results map[string]promql.Vector
+}
+
+func (receiver *ruleAdaptor) Query(ctx context.Context, cmd applications.PromCommand) (applications.RunResult, error) {
+	receiver.remoteReadAdaptor.cmds = append(receiver.remoteReadAdaptor.cmds, cmd)
+	return applications.RunResult{
+		Result: receiver.results[cmd.Cmd],
+	}, nil
+}
+
+// alertmanagerStub records alerts posted to it
+type alertmanagerStub struct {
+	mutex sync.Mutex
+	posts [][]PostableAlert
+}
+
+func (receiver *alertmanagerStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var alerts []PostableAlert
+	if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	receiver.mutex.Lock()
+	receiver.posts = append(receiver.posts, alerts)
+	receiver.mutex.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestRuleManager_record(t *testing.T) {
+	adaptor := &ruleAdaptor{
+		results: map[string]promql.Vector{
+			"sum by (job) (go_goroutines)": {
+				{Metric: labels.FromStrings("job", "prometheus"), Point: promql.Point{T: 1000, V: 5}},
+			},
+		},
+	}
+	prom := NewProm(&config.Config{
+		BizConf: config.BizConfig{
+			AdaptorInfluxDatabase:    "prometheus",
+			AdaptorRuleLookbackDelta: 5 * time.Minute,
+		},
+	}, adaptor)
+	manager, err := NewRuleManager(prom, []RuleGroup{
+		{
+			Name: "example",
+			Rules: []Rule{
+				{
+					Record: "job:go_goroutines:sum",
+					Expr:   "sum by (job) (go_goroutines)",
+					Labels: map[string]string{"env": "prod"},
+				},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.UnixMilli(60000)
+	manager.evalGroup(context.Background(), manager.groups[0], ts)
+	want := []applications.PromWriteCommand{
+		{
+			Database: "prometheus",
+			TimeSeries: []applications.TimeSeries{
+				{
+					Labels: []applications.Label{
+						{Name: labels.MetricName, Value: "job:go_goroutines:sum"},
+						{Name: "env", Value: "prod"},
+						{Name: "job", Value: "prometheus"},
+					},
+					Samples: []applications.Sample{{Timestamp: 60000, Value: 5}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(adaptor.cmds, want) {
+		t.Errorf("evalGroup() wrote %v, want %v", adaptor.cmds, want)
+	}
+	cmd := adaptor.remoteReadAdaptor.cmds[0]
+	if cmd.QueryType != applications.INSTANT_QUERY || !cmd.Evaluation.Equal(ts) || cmd.LookbackDelta != 5*time.Minute {
+		t.Errorf("evalGroup() got command %+v", cmd)
+	}
+	discovery, err := manager.RuleGroups("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := discovery.Groups[0].Rules[0].(dto.RecordingRule)
+	if rule.Health != RULE_HEALTH_OK || rule.Type != "recording" || !rule.LastEvaluation.Equal(ts) || discovery.Groups[0].Interval != 60 {
+		t.Errorf("RuleGroups() got = %+v", discovery)
+	}
+	if discovery, _ = manager.RuleGroups(RULE_TYPE_ALERT); len(discovery.Groups[0].Rules) > 0 {
+		t.Errorf("RuleGroups() got = %+v, want no rules", discovery)
+	}
+}
+
+func TestRuleManager_alert(t *testing.T) {
+	stub := &alertmanagerStub{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+	adaptor := &ruleAdaptor{}
+	prom := NewProm(&config.Config{}, adaptor)
+	manager, err := NewRuleManager(prom, []RuleGroup{
+		{
+			Name: "example",
+			Rules: []Rule{
+				{
+					Alert:         "InstanceDown",
+					Expr:          "up == 0",
+					For:           model.Duration(2 * time.Minute),
+					KeepFiringFor: model.Duration(2 * time.Minute),
+					Labels:        map[string]string{"severity": "page"},
+					Annotations:   map[string]string{"summary": "{{ $labels.instance }} down, value {{ $value }}"},
+				},
+			},
+		},
+	}, NewAlertmanagerNotifier(server.URL, time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	down := promql.Vector{
+		{Metric: labels.FromStrings(labels.MetricName, "up", "instance", "localhost:9100", "job", "node"), Point: promql.Point{T: start.UnixMilli(), V: 0}},
+	}
+	wantLabels := map[string]string{"alertname": "InstanceDown", "instance": "localhost:9100", "job": "node", "severity": "page"}
+	wantAnnotations := map[string]string{"summary": "localhost:9100 down, value 0"}
+	steps := []struct {
+		minutes   int
+		down      bool
+		wantState string
+		wantPosts int
+	}{
+		{minutes: 0, down: true, wantState: ALERT_STATE_PENDING, wantPosts: 0},
+		{minutes: 1, down: true, wantState: ALERT_STATE_PENDING, wantPosts: 0},
+		{minutes: 2, down: true, wantState: ALERT_STATE_FIRING, wantPosts: 1},
+		// keeps firing for keep_firing_for after the expression stops returning it, and is sent again after the resend delay
+		{minutes: 3, down: false, wantState: ALERT_STATE_FIRING, wantPosts: 2},
+		{minutes: 5, down: false, wantState: "", wantPosts: 3},
+		// resolved alerts are sent only once
+		{minutes: 6, down: false, wantState: "", wantPosts: 3},
+	}
+	for _, step := range steps {
+		adaptor.results = map[string]promql.Vector{}
+		if step.down {
+			adaptor.results["up == 0"] = down
+		}
+		manager.evalGroup(context.Background(), manager.groups[0], start.Add(time.Duration(step.minutes)*time.Minute))
+		alerts := manager.Alerts().Alerts
+		switch {
+		case step.wantState == "" && len(alerts) > 0:
+			t.Errorf("minute %d: Alerts() got = %+v, want no alert", step.minutes, alerts)
+		case step.wantState != "" && (len(alerts) != 1 || alerts[0].State != step.wantState):
+			t.Errorf("minute %d: Alerts() got = %+v, want %s alert", step.minutes, alerts, step.wantState)
+		case step.wantState != "":
+			if !reflect.DeepEqual(alerts[0].Labels, wantLabels) || !reflect.DeepEqual(alerts[0].Annotations, wantAnnotations) || !alerts[0].ActiveAt.Equal(start) {
+				t.Errorf("minute %d: Alerts() got = %+v", step.minutes, alerts[0])
+			}
+			if step.down != (alerts[0].KeepFiringSince == nil) {
+				t.Errorf("minute %d: Alerts() got keepFiringSince %v", step.minutes, alerts[0].KeepFiringSince)
+			}
+		}
+		if len(stub.posts) != step.wantPosts {
+			t.Fatalf("minute %d: got %d posts, want %d", step.minutes, len(stub.posts), step.wantPosts)
+		}
+	}
+	// alerts start when they become pending, as Prometheus sends ActiveAt as StartsAt
+	fired := stub.posts[0][0]
+	if !reflect.DeepEqual(fired.Labels, wantLabels) || !fired.StartsAt.Equal(start) || !fired.EndsAt.Equal(start.Add(6*time.Minute)) {
+		t.Errorf("got fired alert %+v", fired)
+	}
+	resolved := stub.posts[2][0]
+	if !resolved.StartsAt.Equal(start) || !resolved.EndsAt.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("got resolved alert %+v", resolved)
+	}
+	discovery, err := manager.RuleGroups(RULE_TYPE_ALERT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := discovery.Groups[0].Rules[0].(dto.AlertingRule)
+	if rule.State != ALERT_STATE_INACTIVE || rule.Duration != 120 || rule.KeepFiringFor != 120 || rule.Health != RULE_HEALTH_OK {
+		t.Errorf("RuleGroups() got = %+v", rule)
+	}
+	if _, err = manager.RuleGroups("alerts"); err == nil {
+		t.Error("RuleGroups() error = nil, want error")
+	}
+}
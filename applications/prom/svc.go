@@ -27,6 +27,13 @@ type Prom interface {
 		//
 		// Optional.
 		time *string,
+		// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+		//
+		// Default is the X-Timezone header, then the datasource's setting.
+		//
+		// Example: "&timezone=Europe/Berlin"
+		//
+		timezone *string,
 		// Evaluation timeout. Optional.
 		timeout *string) (data dto.QueryData, status string, err error)
 
@@ -44,6 +51,13 @@ type Prom interface {
 		//
 		// Optional.
 		time *string,
+		// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+		//
+		// Default is the X-Timezone header, then the datasource's setting.
+		//
+		// Example: "&timezone=Europe/Berlin"
+		//
+		timezone *string,
 		// Evaluation timeout. Optional.
 		timeout *string) (data dto.QueryData, status string, err error)
 
@@ -70,6 +84,20 @@ type Prom interface {
 		// Example: "&step=15s"
 		//
 		step *string,
+		// How empty steps are filled: none, null, previous or linear. Optional.
+		//
+		// Default is the datasource's setting.
+		//
+		// Example: "&fill=previous"
+		//
+		fill *string,
+		// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+		//
+		// Default is the X-Timezone header, then the datasource's setting.
+		//
+		// Example: "&timezone=Europe/Berlin"
+		//
+		timezone *string,
 		// Evaluation timeout. Optional.
 		timeout *string) (data dto.QueryData, status string, err error)
 
@@ -96,6 +124,20 @@ type Prom interface {
 		// Example: "&step=15s"
 		//
 		step *string,
+		// How empty steps are filled: none, null, previous or linear. Optional.
+		//
+		// Default is the datasource's setting.
+		//
+		// Example: "&fill=previous"
+		//
+		fill *string,
+		// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+		//
+		// Default is the X-Timezone header, then the datasource's setting.
+		//
+		// Example: "&timezone=Europe/Berlin"
+		//
+		timezone *string,
 		// Evaluation timeout. Optional.
 		timeout *string) (data dto.QueryData, status string, err error)
 
@@ -120,4 +162,102 @@ type Prom interface {
 		//
 		// required
 		label_name string) (data []string, status string, err error)
+
+	// Series is compatible to Prometheus POST /api/v1/series
+	// The following endpoint returns the list of time series that match a certain label set.
+	//
+	// The "data" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.
+	//
+	Series(ctx context.Context,
+		// Repeated series selector argument that selects the series to return. At least one match[] argument must be provided.
+		//
+		// Example: "?match[]=up&match[]=process_start_time_seconds{job=\"prometheus\"}"
+		//
+		// required
+		match []string,
+		// Start timestamp. Optional.
+		//
+		start *string,
+		// End timestamp. Optional.
+		//
+		end *string) (data []map[string]string, status string, err error)
+
+	// GetSeries is compatible to Prometheus GET /api/v1/series
+	// The following endpoint returns the list of time series that match a certain label set.
+	//
+	// The "data" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.
+	//
+	GetSeries(ctx context.Context,
+		// Repeated series selector argument that selects the series to return. At least one match[] argument must be provided.
+		//
+		// Example: "?match[]=up&match[]=process_start_time_seconds{job=\"prometheus\"}"
+		//
+		// required
+		match []string,
+		// Start timestamp. Optional.
+		//
+		start *string,
+		// End timestamp. Optional.
+		//
+		end *string) (data []map[string]string, status string, err error)
+
+	// Labels is compatible to Prometheus POST /api/v1/labels
+	// The following endpoint returns a list of label names.
+	//
+	// The "data" section of the JSON response is a list of string label names.
+	//
+	Labels(ctx context.Context,
+		// Repeated series selector argument that selects the series from which to read the label names. Optional.
+		//
+		match *[]string,
+		// Start timestamp. Optional.
+		//
+		start *string,
+		// End timestamp. Optional.
+		//
+		end *string,
+		// Maximum number of returned label names. Optional. 0 means disabled.
+		//
+		// Example: "&limit=100"
+		//
+		limit *int) (data []string, status string, err error)
+
+	// GetLabels is compatible to Prometheus GET /api/v1/labels
+	// The following endpoint returns a list of label names.
+	//
+	// The "data" section of the JSON response is a list of string label names.
+	//
+	GetLabels(ctx context.Context,
+		// Repeated series selector argument that selects the series from which to read the label names. Optional.
+		//
+		match *[]string,
+		// Start timestamp. Optional.
+		//
+		start *string,
+		// End timestamp. Optional.
+		//
+		end *string,
+		// Maximum number of returned label names. Optional. 0 means disabled.
+		//
+		// Example: "&limit=100"
+		//
+		limit *int) (data []string, status string, err error)
+
+	// GetMetadata is compatible to Prometheus GET /api/v1/metadata
+	// The following endpoint returns metadata about metrics. As InfluxDB doesn't store metric metadata,
+	// metric types are inferred from Prometheus naming conventions, and help and unit text are read from the metadata file.
+	//
+	// The "data" section of the JSON response is an object where each key is a metric name and each value is a list of unique metadata objects.
+	//
+	GetMetadata(ctx context.Context,
+		// Maximum number of metrics to return. Optional.
+		//
+		// Example: "?limit=2"
+		//
+		limit *int,
+		// A metric name to filter metadata for. All metric metadata is retrieved if left empty. Optional.
+		//
+		// Example: "?metric=http_requests_total"
+		//
+		metric *string) (data map[string][]dto.MetricMetadata, status string, err error)
 }
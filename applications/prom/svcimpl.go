@@ -10,10 +10,14 @@ import (
 	"time"
 
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/promql"
 	"github.com/wubin1989/promql2influxql/applications"
 	"golang.org/x/exp/slices"
 
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/cast"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
 
 	"github.com/pkg/errors"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/caller"
@@ -41,9 +45,11 @@ type PromImpl struct {
 
 	conf    *config.Config
 	adaptor applications.IPromAdaptor
+
+	labelValuesCache *labelValuesCache
 }
 
-func (receiver *PromImpl) query(ctx context.Context, query string, t *string, resultChan chan QueryResponseWrapper) {
+func (receiver *PromImpl) query(ctx context.Context, query string, t *string, timezone *string, resultChan chan QueryResponseWrapper) {
 	var ts *time.Time
 	if t != nil {
 		floatT, err := cast.ToFloat64E(*t)
@@ -56,12 +62,27 @@ func (receiver *PromImpl) query(ctx context.Context, query string, t *string, re
 		tmp := time.UnixMilli(int64(floatT * 1000))
 		ts = &tmp
 	}
+	rateMode, err := parseRateMode(receiver.conf.BizConf.AdaptorRateMode)
+	if err != nil {
+		resultChan <- QueryResponseWrapper{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
+	location, err := parseTimezoneParam(ctx, timezone, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		resultChan <- QueryResponseWrapper{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
 	runResult, err := receiver.adaptor.Query(ctx, applications.PromCommand{
-		Cmd:      query,
-		Database: receiver.conf.BizConf.AdaptorInfluxDatabase,
-		Start:    ts,
-		End:      ts,
-		Timezone: time.Local,
+		Cmd:        query,
+		Database:   receiver.conf.BizConf.AdaptorInfluxDatabase,
+		Evaluation: ts,
+		Timezone:   location,
+		QueryType:  applications.INSTANT_QUERY,
+		RateMode:   rateMode,
 	})
 	if err != nil {
 		resultChan <- QueryResponseWrapper{
@@ -82,7 +103,7 @@ func (receiver *PromImpl) query(ctx context.Context, query string, t *string, re
 
 var emptyResult = dto.QueryResponse{}
 
-func (receiver *PromImpl) Query(ctx context.Context, query string, t *string, timeout *string) (data dto.QueryData, status string, err error) {
+func (receiver *PromImpl) Query(ctx context.Context, query string, t *string, timezone *string, timeout *string) (data dto.QueryData, status string, err error) {
 	if timeout != nil {
 		timeoutDuration, err := time.ParseDuration(*timeout)
 		if err != nil {
@@ -98,37 +119,35 @@ func (receiver *PromImpl) Query(ctx context.Context, query string, t *string, ti
 	defer cancel()
 
 	go func() {
-		receiver.query(ctx, query, t, resultChan)
+		receiver.query(ctx, query, t, timezone, resultChan)
 		close(resultChan)
 	}()
 
 	for {
 		select {
 		case <-ctx.Done():
-			return emptyResult.Data, emptyResult.Status, errors.Wrap(err, caller.NewCaller().String())
+			return emptyResult.Data, emptyResult.Status, errors.Wrap(ctx.Err(), caller.NewCaller().String())
 		case resp := <-resultChan:
 			if resp.Err != nil {
-				return emptyResult.Data, emptyResult.Status, errors.Wrap(err, caller.NewCaller().String())
+				return emptyResult.Data, emptyResult.Status, errors.Wrap(resp.Err, caller.NewCaller().String())
 			}
 			result := resp.Result
 			return result.Data, result.Status, nil
 		}
 	}
 }
-func (receiver *PromImpl) GetQuery(ctx context.Context, query string, time *string, timeout *string) (data dto.QueryData, status string, err error) {
-	return receiver.Query(ctx, query, time, timeout)
+func (receiver *PromImpl) GetQuery(ctx context.Context, query string, time *string, timezone *string, timeout *string) (data dto.QueryData, status string, err error) {
+	return receiver.Query(ctx, query, time, timezone, timeout)
 }
 
-func (receiver *PromImpl) query_range(ctx context.Context, query string, start *string, end *string, step *string, resultChan chan QueryResponseWrapper) {
+// rangeCommand builds applications.PromCommand of range query from the request parameters
+func (receiver *PromImpl) rangeCommand(ctx context.Context, query string, start *string, end *string, step *string, fill *string, timezone *string) (applications.PromCommand, error) {
 	var startTs, endTs *time.Time
 	var err error
 	if start != nil {
 		floatT, err := cast.ToFloat64E(*start)
 		if err != nil {
-			resultChan <- QueryResponseWrapper{
-				Err: errors.Wrap(err, caller.NewCaller().String()),
-			}
-			return
+			return applications.PromCommand{}, errors.Wrap(err, caller.NewCaller().String())
 		}
 		tmp := time.UnixMilli(int64(floatT * 1000))
 		startTs = &tmp
@@ -136,30 +155,46 @@ func (receiver *PromImpl) query_range(ctx context.Context, query string, start *
 	if end != nil {
 		floatT, err := cast.ToFloat64E(*end)
 		if err != nil {
-			resultChan <- QueryResponseWrapper{
-				Err: errors.Wrap(err, caller.NewCaller().String()),
-			}
-			return
+			return applications.PromCommand{}, errors.Wrap(err, caller.NewCaller().String())
 		}
 		tmp := time.UnixMilli(int64(floatT * 1000))
 		endTs = &tmp
 	}
+	location, err := parseTimezoneParam(ctx, timezone, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		return applications.PromCommand{}, errors.Wrap(err, caller.NewCaller().String())
+	}
 	cmd := applications.PromCommand{
-		Cmd:      query,
-		Database: receiver.conf.BizConf.AdaptorInfluxDatabase,
-		Start:    startTs,
-		End:      endTs,
-		Timezone: time.Local,
-		DataType: applications.GRAPH_DATA,
+		Cmd:       query,
+		Database:  receiver.conf.BizConf.AdaptorInfluxDatabase,
+		Start:     startTs,
+		End:       endTs,
+		Timezone:  location,
+		QueryType: applications.RANGE_QUERY,
+		DataType:  applications.GRAPH_DATA,
 	}
 	if step != nil {
 		if cmd.Step, err = time.ParseDuration(*step + "s"); err != nil {
-			resultChan <- QueryResponseWrapper{
-				Err: errors.Wrap(err, caller.NewCaller().String()),
-			}
-			return
+			return applications.PromCommand{}, errors.Wrap(err, caller.NewCaller().String())
 		}
 	}
+	if cmd.Fill, err = parseFillParam(fill, receiver.conf.BizConf.AdaptorFill); err != nil {
+		return applications.PromCommand{}, errors.Wrap(err, caller.NewCaller().String())
+	}
+	if cmd.RateMode, err = parseRateMode(receiver.conf.BizConf.AdaptorRateMode); err != nil {
+		return applications.PromCommand{}, errors.Wrap(err, caller.NewCaller().String())
+	}
+	return cmd, nil
+}
+
+func (receiver *PromImpl) query_range(ctx context.Context, query string, start *string, end *string, step *string, fill *string, timezone *string, resultChan chan QueryResponseWrapper) {
+	cmd, err := receiver.rangeCommand(ctx, query, start, end, step, fill, timezone)
+	if err != nil {
+		resultChan <- QueryResponseWrapper{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
 	runResult, err := receiver.adaptor.Query(ctx, cmd)
 	if err != nil {
 		resultChan <- QueryResponseWrapper{
@@ -178,7 +213,7 @@ func (receiver *PromImpl) query_range(ctx context.Context, query string, start *
 	}
 }
 
-func (receiver *PromImpl) Query_range(ctx context.Context, query string, start *string, end *string, step *string, timeout *string) (data dto.QueryData, status string, err error) {
+func (receiver *PromImpl) Query_range(ctx context.Context, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (data dto.QueryData, status string, err error) {
 	if timeout != nil {
 		timeoutDuration, err := time.ParseDuration(*timeout)
 		if err != nil {
@@ -194,31 +229,32 @@ func (receiver *PromImpl) Query_range(ctx context.Context, query string, start *
 	defer cancel()
 
 	go func() {
-		receiver.query_range(ctx, query, start, end, step, resultChan)
+		receiver.query_range(ctx, query, start, end, step, fill, timezone, resultChan)
 		close(resultChan)
 	}()
 
 	for {
 		select {
 		case <-ctx.Done():
-			return emptyResult.Data, emptyResult.Status, errors.Wrap(err, caller.NewCaller().String())
+			return emptyResult.Data, emptyResult.Status, errors.Wrap(ctx.Err(), caller.NewCaller().String())
 		case resp := <-resultChan:
 			if resp.Err != nil {
-				return emptyResult.Data, emptyResult.Status, errors.Wrap(err, caller.NewCaller().String())
+				return emptyResult.Data, emptyResult.Status, errors.Wrap(resp.Err, caller.NewCaller().String())
 			}
 			result := resp.Result
 			return result.Data, result.Status, nil
 		}
 	}
 }
-func (receiver *PromImpl) GetQuery_range(ctx context.Context, query string, start *string, end *string, step *string, timeout *string) (data dto.QueryData, status string, err error) {
-	return receiver.Query_range(ctx, query, start, end, step, timeout)
+func (receiver *PromImpl) GetQuery_range(ctx context.Context, query string, start *string, end *string, step *string, fill *string, timezone *string, timeout *string) (data dto.QueryData, status string, err error) {
+	return receiver.Query_range(ctx, query, start, end, step, fill, timezone, timeout)
 }
 
 func NewProm(conf *config.Config, adaptor applications.IPromAdaptor) *PromImpl {
 	return &PromImpl{
-		conf:    conf,
-		adaptor: adaptor,
+		conf:             conf,
+		adaptor:          adaptor,
+		labelValuesCache: newLabelValuesCache(conf.BizConf.AdaptorLabelValuesWindow),
 	}
 }
 
@@ -227,15 +263,43 @@ type StringSliceResult struct {
 	Err    error
 }
 
-func (receiver PromImpl) doLabelValuesQuery(ctx context.Context, cmd string, startTime, endTime time.Time, label_name string, resultChan chan StringSliceResult) {
+func (receiver PromImpl) doLabelValuesQuery(ctx context.Context, cmd string, startTime *time.Time, endTime time.Time, label_name string, resultChan chan StringSliceResult) {
+	location, err := parseTimezoneParam(ctx, nil, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		resultChan <- StringSliceResult{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
+	labelValuesMode, err := parseLabelValuesMode(receiver.conf.BizConf.AdaptorLabelValuesMode)
+	if err != nil {
+		resultChan <- StringSliceResult{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
+	// Time-bounded queries are much slower than index lookups, so results are cached per window
+	var cacheKey labelValuesCacheKey
+	if labelValuesMode == applications.LABEL_VALUES_ACCURATE {
+		startTime, endTime = receiver.labelValuesCache.align(startTime, endTime)
+		cacheKey = receiver.labelValuesCache.key(cmd, label_name, startTime, endTime)
+		if values, ok := receiver.labelValuesCache.get(cacheKey); ok {
+			resultChan <- StringSliceResult{
+				Result: values,
+			}
+			return
+		}
+	}
 	runResult, err := receiver.adaptor.Query(ctx, applications.PromCommand{
-		Cmd:       cmd,
-		Database:  receiver.conf.BizConf.AdaptorInfluxDatabase,
-		Start:     &startTime,
-		End:       &endTime,
-		Timezone:  time.Local,
-		DataType:  applications.LABEL_VALUES_DATA,
-		LabelName: label_name,
+		Cmd:             cmd,
+		Database:        receiver.conf.BizConf.AdaptorInfluxDatabase,
+		Start:           startTime,
+		End:             &endTime,
+		Timezone:        location,
+		QueryType:       applications.RANGE_QUERY,
+		DataType:        applications.LABEL_VALUES_DATA,
+		LabelName:       label_name,
+		LabelValuesMode: labelValuesMode,
 	})
 	if err != nil {
 		resultChan <- StringSliceResult{
@@ -243,8 +307,12 @@ func (receiver PromImpl) doLabelValuesQuery(ctx context.Context, cmd string, sta
 		}
 		return
 	}
+	values := runResult.Result.([]string)
+	if labelValuesMode == applications.LABEL_VALUES_ACCURATE {
+		receiver.labelValuesCache.set(cacheKey, values)
+	}
 	resultChan <- StringSliceResult{
-		Result: runResult.Result.([]string),
+		Result: values,
 	}
 }
 
@@ -252,9 +320,14 @@ func (receiver *PromImpl) GetLabel_Label_nameValues(ctx context.Context, start *
 	if !model.LabelNameRE.MatchString(label_name) {
 		return nil, "", errors.Errorf("invalid label name: %q", label_name)
 	}
-	startTime, err := parseTimeParam("start", start, minTime)
-	if err != nil {
-		return nil, "", errors.Errorf("invalid start: %q", *start)
+	// Start is left unbounded if it is omitted, because minTime cannot be formatted as InfluxQL time literal
+	var startTime *time.Time
+	if start != nil {
+		parsed, err := parseTimeParam("start", start, minTime)
+		if err != nil {
+			return nil, "", errors.Errorf("invalid start: %q", *start)
+		}
+		startTime = &parsed
 	}
 	endTime, err := parseTimeParam("end", end, time.Now())
 	if err != nil {
@@ -276,10 +349,10 @@ func (receiver *PromImpl) GetLabel_Label_nameValues(ctx context.Context, start *
 		if match != nil {
 			wg.Add(len(*match))
 			for _, item := range *match {
-				go func() {
+				go func(item string) {
 					defer wg.Done()
 					receiver.doLabelValuesQuery(ctx, item, startTime, endTime, label_name, resultChan)
-				}()
+				}(item)
 			}
 		} else {
 			wg.Add(1)
@@ -298,7 +371,7 @@ LOOP:
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, "", errors.Wrap(err, caller.NewCaller().String())
+			return nil, "", errors.Wrap(ctx.Err(), caller.NewCaller().String())
 		case resp, ok := <-resultChan:
 			if !ok {
 				break LOOP
@@ -324,23 +397,484 @@ LOOP:
 	return data, SUCCESS_STATUS, nil
 }
 
+func (receiver PromImpl) doLabelNamesQuery(ctx context.Context, matchers []*labels.Matcher, startTime *time.Time, endTime time.Time, resultChan chan StringSliceResult) {
+	location, err := parseTimezoneParam(ctx, nil, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		resultChan <- StringSliceResult{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
+	// Empty command lists label names of all measurements
+	selectors := []string{""}
+	if matchers != nil {
+		if selectors, err = receiver.selectedVectorSelectors(ctx, matchers); err != nil {
+			resultChan <- StringSliceResult{
+				Err: errors.Wrap(err, caller.NewCaller().String()),
+			}
+			return
+		}
+	}
+	var result []string
+	for _, selector := range selectors {
+		runResult, err := receiver.adaptor.Query(ctx, applications.PromCommand{
+			Cmd:       selector,
+			Database:  receiver.conf.BizConf.AdaptorInfluxDatabase,
+			Start:     startTime,
+			End:       &endTime,
+			Timezone:  location,
+			QueryType: applications.RANGE_QUERY,
+			DataType:  applications.LABEL_NAMES_DATA,
+		})
+		if err != nil {
+			resultChan <- StringSliceResult{
+				Err: errors.Wrap(err, caller.NewCaller().String()),
+			}
+			return
+		}
+		labelNames, _ := runResult.Result.([]string)
+		result = append(result, labelNames...)
+	}
+	resultChan <- StringSliceResult{
+		Result: result,
+	}
+}
+
+// Labels resolves metric names selected by every match[] at first like Series does, then lists label names of each metric name.
+func (receiver *PromImpl) Labels(ctx context.Context, match *[]string, start *string, end *string, limit *int) (data []string, status string, err error) {
+	if limit != nil && *limit < 0 {
+		return nil, "", errors.Errorf("invalid limit: %d", *limit)
+	}
+	matcherSets, err := parseMatchersParam(match)
+	if err != nil {
+		return nil, "", errors.Wrap(err, caller.NewCaller().String())
+	}
+	// Start is left unbounded if it is omitted, because minTime cannot be formatted as InfluxQL time literal
+	var startTime *time.Time
+	if start != nil {
+		parsed, err := parseTimeParam("start", start, minTime)
+		if err != nil {
+			return nil, "", errors.Errorf("invalid start: %q", *start)
+		}
+		startTime = &parsed
+	}
+	endTime, err := parseTimeParam("end", end, time.Now())
+	if err != nil {
+		return nil, "", errors.Errorf("invalid end: %q", *end)
+	}
+
+	bufferSize := 1
+	if matcherSets != nil {
+		bufferSize = len(matcherSets)
+	}
+
+	resultChan := make(chan StringSliceResult, bufferSize)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		var wg sync.WaitGroup
+		if matcherSets != nil {
+			wg.Add(len(matcherSets))
+			for _, item := range matcherSets {
+				go func(item []*labels.Matcher) {
+					defer wg.Done()
+					receiver.doLabelNamesQuery(ctx, item, startTime, endTime, resultChan)
+				}(item)
+			}
+		} else {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				receiver.doLabelNamesQuery(ctx, nil, startTime, endTime, resultChan)
+			}()
+		}
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	labelNameMap := make(map[string]struct{})
+
+LOOP:
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, "", errors.Wrap(ctx.Err(), caller.NewCaller().String())
+		case resp, ok := <-resultChan:
+			if !ok {
+				break LOOP
+			}
+			if resp.Err != nil {
+				return nil, "", errors.Wrap(resp.Err, caller.NewCaller().String())
+			}
+			for _, value := range resp.Result {
+				if _, exists := labelNameMap[value]; exists {
+					continue
+				}
+				labelNameMap[value] = struct{}{}
+				data = append(data, value)
+			}
+		}
+	}
+
+	slices.Sort(data)
+	if limit != nil && *limit > 0 && len(data) > *limit {
+		data = data[:*limit]
+	}
+	return data, SUCCESS_STATUS, nil
+}
+
+func (receiver *PromImpl) GetLabels(ctx context.Context, match *[]string, start *string, end *string, limit *int) (data []string, status string, err error) {
+	return receiver.Labels(ctx, match, start, end, limit)
+}
+
+type LabelSetsResult struct {
+	Result []labels.Labels
+	Err    error
+}
+
+func (receiver PromImpl) doSeriesQuery(ctx context.Context, matchers []*labels.Matcher, startTime *time.Time, endTime time.Time, resultChan chan LabelSetsResult) {
+	location, err := parseTimezoneParam(ctx, nil, receiver.conf.BizConf.AdaptorTimezone)
+	if err != nil {
+		resultChan <- LabelSetsResult{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
+	selectors, err := receiver.selectedVectorSelectors(ctx, matchers)
+	if err != nil {
+		resultChan <- LabelSetsResult{
+			Err: errors.Wrap(err, caller.NewCaller().String()),
+		}
+		return
+	}
+	var result []labels.Labels
+	for _, selector := range selectors {
+		runResult, err := receiver.adaptor.Query(ctx, applications.PromCommand{
+			Cmd:       selector,
+			Database:  receiver.conf.BizConf.AdaptorInfluxDatabase,
+			Start:     startTime,
+			End:       &endTime,
+			Timezone:  location,
+			QueryType: applications.RANGE_QUERY,
+			DataType:  applications.SERIES_DATA,
+		})
+		if err != nil {
+			resultChan <- LabelSetsResult{
+				Err: errors.Wrap(err, caller.NewCaller().String()),
+			}
+			return
+		}
+		labelSets, _ := runResult.Result.([]labels.Labels)
+		result = append(result, labelSets...)
+	}
+	resultChan <- LabelSetsResult{
+		Result: result,
+	}
+}
+
+// Series resolves metric names selected by every match[] at first, as selectors without metric name like {job="prometheus"}
+// or selecting metric names by regex can't be transpiled directly, then queries series of each metric name.
+func (receiver *PromImpl) Series(ctx context.Context, match []string, start *string, end *string) (data []map[string]string, status string, err error) {
+	if len(match) == 0 {
+		return nil, "", errors.New("no match[] parameter provided")
+	}
+	matcherSets, err := parseMatchersParam(&match)
+	if err != nil {
+		return nil, "", errors.Wrap(err, caller.NewCaller().String())
+	}
+	// Start is left unbounded if it is omitted, because minTime cannot be formatted as InfluxQL time literal
+	var startTime *time.Time
+	if start != nil {
+		parsed, err := parseTimeParam("start", start, minTime)
+		if err != nil {
+			return nil, "", errors.Errorf("invalid start: %q", *start)
+		}
+		startTime = &parsed
+	}
+	endTime, err := parseTimeParam("end", end, time.Now())
+	if err != nil {
+		return nil, "", errors.Errorf("invalid end: %q", *end)
+	}
+
+	resultChan := make(chan LabelSetsResult, len(matcherSets))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		var wg sync.WaitGroup
+		wg.Add(len(matcherSets))
+		for _, item := range matcherSets {
+			go func(item []*labels.Matcher) {
+				defer wg.Done()
+				receiver.doSeriesQuery(ctx, item, startTime, endTime, resultChan)
+			}(item)
+		}
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	labelSetMap := make(map[uint64]struct{})
+	var labelSets []labels.Labels
+
+LOOP:
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, "", errors.Wrap(ctx.Err(), caller.NewCaller().String())
+		case resp, ok := <-resultChan:
+			if !ok {
+				break LOOP
+			}
+			if resp.Err != nil {
+				return nil, "", errors.Wrap(resp.Err, caller.NewCaller().String())
+			}
+			for _, labelSet := range resp.Result {
+				if _, exists := labelSetMap[labelSet.Hash()]; exists {
+					continue
+				}
+				labelSetMap[labelSet.Hash()] = struct{}{}
+				labelSets = append(labelSets, labelSet)
+			}
+		}
+	}
+
+	slices.SortFunc(labelSets, func(a, b labels.Labels) bool {
+		return labels.Compare(a, b) < 0
+	})
+	data = make([]map[string]string, 0, len(labelSets))
+	for _, labelSet := range labelSets {
+		data = append(data, labelSet.Map())
+	}
+	return data, SUCCESS_STATUS, nil
+}
+
+func (receiver *PromImpl) GetSeries(ctx context.Context, match []string, start *string, end *string) (data []map[string]string, status string, err error) {
+	return receiver.Series(ctx, match, start, end)
+}
+
+func (receiver *PromImpl) GetMetadata(ctx context.Context, limit *int, metric *string) (data map[string][]dto.MetricMetadata, status string, err error) {
+	var cmd string
+	if metric != nil {
+		cmd = *metric
+	}
+	runResult, err := receiver.adaptor.Query(ctx, applications.PromCommand{
+		Cmd:      cmd,
+		Database: receiver.conf.BizConf.AdaptorInfluxDatabase,
+		DataType: applications.METADATA_DATA,
+	})
+	if err != nil {
+		return nil, "", errors.Wrap(err, caller.NewCaller().String())
+	}
+	metricTypes, _ := runResult.Result.(map[string]textparse.MetricType)
+	metricNames := make([]string, 0, len(metricTypes))
+	for metricName := range metricTypes {
+		metricNames = append(metricNames, metricName)
+	}
+	slices.Sort(metricNames)
+	// Negative limit means disabled as Prometheus does
+	if limit != nil && *limit >= 0 && len(metricNames) > *limit {
+		metricNames = metricNames[:*limit]
+	}
+	data = make(map[string][]dto.MetricMetadata, len(metricNames))
+	for _, metricName := range metricNames {
+		metadata := dto.MetricMetadata{
+			Type: string(metricTypes[metricName]),
+		}
+		if supplied, ok := receiver.conf.Metadata[metricName]; ok {
+			if stringutils.IsNotEmpty(supplied.Type) {
+				metadata.Type = supplied.Type
+			}
+			metadata.Help = supplied.Help
+			metadata.Unit = supplied.Unit
+		}
+		data[metricName] = []dto.MetricMetadata{metadata}
+	}
+	return data, SUCCESS_STATUS, nil
+}
+
 func (receiver *PromImpl) QueryRpc(ctx context.Context, request *pb.QueryRpcRequest) (*pb.QueryRpcResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	data, status, err := receiver.Query(ctx, request.Query, optionalParam(request.Time), optionalParam(request.Timezone), optionalParam(request.Timeout))
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	pbData, err := queryDataToPb(data)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	return &pb.QueryRpcResponse{
+		Data:   pbData,
+		Status: status,
+	}, nil
 }
 func (receiver *PromImpl) GetQueryRpc(ctx context.Context, request *pb.GetQueryRpcRequest) (*pb.GetQueryRpcResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	response, err := receiver.QueryRpc(ctx, &pb.QueryRpcRequest{
+		Query:    request.Query,
+		Time:     request.Time,
+		Timeout:  request.Timeout,
+		Timezone: request.Timezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetQueryRpcResponse{
+		Data:   response.Data,
+		Status: response.Status,
+	}, nil
 }
 func (receiver *PromImpl) QueryRangeRpc(ctx context.Context, request *pb.QueryRangeRpcRequest) (*pb.QueryRangeRpcResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	data, status, err := receiver.Query_range(ctx, request.Query, optionalParam(request.Start), optionalParam(request.End), optionalParam(request.Step),
+		optionalParam(request.Fill), optionalParam(request.Timezone), optionalParam(request.Timeout))
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	pbData, err := queryDataToPb(data)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	return &pb.QueryRangeRpcResponse{
+		Data:   pbData,
+		Status: status,
+	}, nil
 }
 func (receiver *PromImpl) GetQueryRangeRpc(ctx context.Context, request *pb.GetQueryRangeRpcRequest) (*pb.GetQueryRangeRpcResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	response, err := receiver.QueryRangeRpc(ctx, &pb.QueryRangeRpcRequest{
+		Query:    request.Query,
+		Start:    request.Start,
+		End:      request.End,
+		Step:     request.Step,
+		Timeout:  request.Timeout,
+		Fill:     request.Fill,
+		Timezone: request.Timezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetQueryRangeRpcResponse{
+		Data:   response.Data,
+		Status: response.Status,
+	}, nil
+}
+
+// QueryRangeStreamRpc sends matrix result of range query part by part as soon as every part is decoded from InfluxDB.
+// Sending blocks while the flow control window of the stream is full, which stops reading from InfluxDB as well,
+// and the query is aborted once the stream is canceled by the client or exceeds the timeout.
+func (receiver *PromImpl) QueryRangeStreamRpc(request *pb.QueryRangeRpcRequest, stream pb.PromService_QueryRangeStreamRpcServer) error {
+	ctx := stream.Context()
+	if timeout := optionalParam(request.Timeout); timeout != nil {
+		timeoutDuration, err := time.ParseDuration(*timeout)
+		if err != nil {
+			return errors.Wrap(err, caller.NewCaller().String())
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeoutDuration)
+		defer cancel()
+	}
+	cmd, err := receiver.rangeCommand(ctx, request.Query, optionalParam(request.Start), optionalParam(request.End), optionalParam(request.Step),
+		optionalParam(request.Fill), optionalParam(request.Timezone))
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	err = receiver.adaptor.QueryStream(ctx, cmd, func(result applications.RunResult) error {
+		matrix, ok := result.Result.(promql.Matrix)
+		if !ok {
+			return errors.Errorf("unsupported result type %T", result.Result)
+		}
+		return stream.Send(&pb.QueryRangeStreamRpcResponse{
+			Series: matrixToPb(matrix),
+		})
+	})
+	if err != nil {
+		return errors.Wrap(err, caller.NewCaller().String())
+	}
+	return nil
 }
 func (receiver *PromImpl) GetLabelLabelNameValuesRpc(ctx context.Context, request *pb.GetLabelLabelNameValuesRpcRequest) (*pb.GetLabelLabelNameValuesRpcResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	var match *[]string
+	if len(request.Match) > 0 {
+		match = &request.Match
+	}
+	data, status, err := receiver.GetLabel_Label_nameValues(ctx, optionalParam(request.Start), optionalParam(request.End), match, request.LabelName)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	return &pb.GetLabelLabelNameValuesRpcResponse{
+		Data:   data,
+		Status: status,
+	}, nil
+}
+func (receiver *PromImpl) SeriesRpc(ctx context.Context, request *pb.SeriesRpcRequest) (*pb.SeriesRpcResponse, error) {
+	data, status, err := receiver.Series(ctx, request.Match, optionalParam(request.Start), optionalParam(request.End))
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	return &pb.SeriesRpcResponse{
+		Data:   labelSetsToPb(data),
+		Status: status,
+	}, nil
+}
+func (receiver *PromImpl) GetSeriesRpc(ctx context.Context, request *pb.GetSeriesRpcRequest) (*pb.GetSeriesRpcResponse, error) {
+	response, err := receiver.SeriesRpc(ctx, &pb.SeriesRpcRequest{
+		Match: request.Match,
+		Start: request.Start,
+		End:   request.End,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetSeriesRpcResponse{
+		Data:   response.Data,
+		Status: response.Status,
+	}, nil
+}
+func (receiver *PromImpl) LabelsRpc(ctx context.Context, request *pb.LabelsRpcRequest) (*pb.LabelsRpcResponse, error) {
+	var match *[]string
+	if len(request.Match) > 0 {
+		match = &request.Match
+	}
+	var limit *int
+	if request.Limit != 0 {
+		_limit := int(request.Limit)
+		limit = &_limit
+	}
+	data, status, err := receiver.Labels(ctx, match, optionalParam(request.Start), optionalParam(request.End), limit)
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	return &pb.LabelsRpcResponse{
+		Data:   data,
+		Status: status,
+	}, nil
+}
+func (receiver *PromImpl) GetLabelsRpc(ctx context.Context, request *pb.GetLabelsRpcRequest) (*pb.GetLabelsRpcResponse, error) {
+	response, err := receiver.LabelsRpc(ctx, &pb.LabelsRpcRequest{
+		Match: request.Match,
+		Start: request.Start,
+		End:   request.End,
+		Limit: request.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetLabelsRpcResponse{
+		Data:   response.Data,
+		Status: response.Status,
+	}, nil
+}
+func (receiver *PromImpl) GetMetadataRpc(ctx context.Context, request *pb.GetMetadataRpcRequest) (*pb.GetMetadataRpcResponse, error) {
+	var limit *int
+	if request.Limit != 0 {
+		_limit := int(request.Limit)
+		limit = &_limit
+	}
+	data, status, err := receiver.GetMetadata(ctx, limit, optionalParam(request.Metric))
+	if err != nil {
+		return nil, errors.Wrap(err, caller.NewCaller().String())
+	}
+	return &pb.GetMetadataRpcResponse{
+		Data:   metadataToPb(data),
+		Status: status,
+	}, nil
 }
@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/unionj-cloud/go-doudou/v2/toolkit/copier"
 	"github.com/wubin1989/promql2influxql/adaptors/prom"
 	"github.com/wubin1989/promql2influxql/applications"
 	"github.com/wubin1989/promql2influxql/applications/prom/config"
 	"reflect"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 )
@@ -62,10 +67,11 @@ func TestRpcImpl_Query(t *testing.T) {
 		adaptor applications.IPromAdaptor
 	}
 	type args struct {
-		ctx     context.Context
-		query   string
-		t       *string
-		timeout *string
+		ctx      context.Context
+		query    string
+		t        *string
+		timezone *string
+		timeout  *string
 	}
 	tests := []struct {
 		name    string
@@ -95,7 +101,7 @@ func TestRpcImpl_Query(t *testing.T) {
 				conf:    tt.fields.conf,
 				adaptor: tt.fields.adaptor,
 			}
-			gotRet, _, err := receiver.Query(tt.args.ctx, tt.args.query, tt.args.t, tt.args.timeout)
+			gotRet, _, err := receiver.Query(tt.args.ctx, tt.args.query, tt.args.t, tt.args.timezone, tt.args.timeout)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -174,3 +180,158 @@ func TestRpcImpl_GetLabel_Label_nameValues(t *testing.T) {
 		})
 	}
 }
+
+// selectorAdaptor answers metric names and series of the selectors transpiled by the adaptor, and records the commands
+type selectorAdaptor struct {
+	remoteReadAdaptor
+	mutex sync.Mutex
+}
+
+// selectorLabelNames are label names of each metric answered by selectorAdaptor
+var selectorLabelNames = map[string][]string{
+	"go_goroutines":       {labels.MetricName, "job"},
+	"go_threads":          {labels.MetricName, "instance", "job"},
+	"http_requests_total": {labels.MetricName, "handler", "job"},
+}
+
+func (receiver *selectorAdaptor) Query(ctx context.Context, cmd applications.PromCommand) (applications.RunResult, error) {
+	receiver.mutex.Lock()
+	receiver.cmds = append(receiver.cmds, cmd)
+	receiver.mutex.Unlock()
+	switch {
+	case cmd.DataType == applications.LABEL_VALUES_DATA:
+		return applications.RunResult{
+			Result: []string{"go_goroutines", "go_threads", "http_requests_total"},
+		}, nil
+	case cmd.DataType == applications.LABEL_NAMES_DATA && cmd.Cmd == "":
+		return applications.RunResult{
+			Result: []string{labels.MetricName, "handler", "instance", "job"},
+		}, nil
+	}
+	expr, err := parser.ParseExpr(cmd.Cmd)
+	if err != nil {
+		return applications.RunResult{}, err
+	}
+	v, ok := expr.(*parser.VectorSelector)
+	if !ok || v.Name == "" {
+		return applications.RunResult{}, errors.Errorf("vector selector %s must select a single metric name", cmd.Cmd)
+	}
+	if cmd.DataType == applications.LABEL_NAMES_DATA {
+		return applications.RunResult{
+			Result: selectorLabelNames[v.Name],
+		}, nil
+	}
+	return applications.RunResult{
+		Result: []labels.Labels{labels.FromStrings(labels.MetricName, v.Name, "job", "prometheus")},
+	}, nil
+}
+
+func TestPromImpl_Series(t *testing.T) {
+	adaptor := &selectorAdaptor{}
+	receiver := NewProm(&config.Config{}, adaptor)
+	data, _, err := receiver.Series(context.Background(), []string{`{job="prometheus"}`, `{__name__="go_goroutines"}`, `{__name__=~"http_.*"}`}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]string{
+		{labels.MetricName: "go_goroutines", "job": "prometheus"},
+		{labels.MetricName: "go_threads", "job": "prometheus"},
+		{labels.MetricName: "http_requests_total", "job": "prometheus"},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Series() got = %v, want %v", data, want)
+	}
+	var selectors []string
+	for _, cmd := range adaptor.cmds {
+		if cmd.DataType == applications.SERIES_DATA {
+			selectors = append(selectors, cmd.Cmd)
+		}
+	}
+	sort.Strings(selectors)
+	wantSelectors := []string{`go_goroutines`, `go_goroutines{job="prometheus"}`, `go_threads{job="prometheus"}`, `http_requests_total`, `http_requests_total{job="prometheus"}`}
+	if !reflect.DeepEqual(selectors, wantSelectors) {
+		t.Errorf("Series() queried %v, want %v", selectors, wantSelectors)
+	}
+	if _, _, err = receiver.Series(context.Background(), []string{`{job=~".*"}`}, nil, nil); err == nil {
+		t.Error("Series() error = nil, want error")
+	}
+}
+
+func TestPromImpl_Labels(t *testing.T) {
+	tests := []struct {
+		name          string
+		match         *[]string
+		want          []string
+		wantSelectors []string
+	}{
+		{
+			name:          "",
+			match:         &[]string{`{__name__=~"go_.*"}`, `{__name__="go_goroutines",job="prometheus"}`},
+			want:          []string{labels.MetricName, "instance", "job"},
+			wantSelectors: []string{`go_goroutines`, `go_goroutines{job="prometheus"}`, `go_threads`},
+		},
+		{
+			name:          "",
+			match:         &[]string{`{job="prometheus"}`},
+			want:          []string{labels.MetricName, "handler", "instance", "job"},
+			wantSelectors: []string{`go_goroutines{job="prometheus"}`, `go_threads{job="prometheus"}`, `http_requests_total{job="prometheus"}`},
+		},
+		{
+			name:          "",
+			match:         nil,
+			want:          []string{labels.MetricName, "handler", "instance", "job"},
+			wantSelectors: []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adaptor := &selectorAdaptor{}
+			receiver := NewProm(&config.Config{}, adaptor)
+			end := "10"
+			data, _, err := receiver.Labels(context.Background(), tt.match, nil, &end, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(data, tt.want) {
+				t.Errorf("Labels() got = %v, want %v", data, tt.want)
+			}
+			var selectors []string
+			for _, cmd := range adaptor.cmds {
+				if cmd.DataType != applications.LABEL_NAMES_DATA {
+					continue
+				}
+				selectors = append(selectors, cmd.Cmd)
+				if cmd.Start != nil || cmd.End.Unix() != 10 {
+					t.Errorf("Labels() got command %+v", cmd)
+				}
+			}
+			sort.Strings(selectors)
+			if !reflect.DeepEqual(selectors, tt.wantSelectors) {
+				t.Errorf("Labels() queried %v, want %v", selectors, tt.wantSelectors)
+			}
+		})
+	}
+}
+
+// blockingAdaptor blocks queries until they are canceled
+type blockingAdaptor struct {
+	remoteReadAdaptor
+}
+
+func (receiver *blockingAdaptor) Query(ctx context.Context, cmd applications.PromCommand) (applications.RunResult, error) {
+	<-ctx.Done()
+	return applications.RunResult{}, ctx.Err()
+}
+
+func TestPromImpl_GetLabel_Label_nameValues_Canceled(t *testing.T) {
+	receiver := NewProm(&config.Config{}, &blockingAdaptor{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data, _, err := receiver.GetLabel_Label_nameValues(ctx, nil, nil, &[]string{"go_goroutines"}, "job")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetLabel_Label_nameValues() error = %v, want context.Canceled", err)
+	}
+	if data != nil {
+		t.Errorf("GetLabel_Label_nameValues() got = %v, want nil", data)
+	}
+}
@@ -6,7 +6,7 @@
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.27.1
+// 	protoc-gen-go v1.28.1
 // 	protoc        v3.19.4
 // source: transport/grpc/prom.proto
 
@@ -15,7 +15,6 @@ package grpc
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	anypb "google.golang.org/protobuf/types/known/anypb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -164,38 +163,29 @@ func (x *GetLabelLabelNameValuesRpcResponse) GetStatus() string {
 	return ""
 }
 
-type GetQueryRangeRpcRequest struct {
+type GetLabelsRpcRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Prometheus expression query string.
-	//
-	// Example: "?query=up"
-	//
-	// required
-	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	// Start timestamp.
+	// Repeated series selector argument that selects the series from which to read the label names. Optional.
 	//
-	// Example: "&start=2015-07-01T20:10:30.781Z"
+	Match []string `protobuf:"bytes,1,rep,name=match,proto3" json:"match,omitempty"`
+	// Start timestamp. Optional.
 	//
 	Start string `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
-	// End timestamp.
-	//
-	// Example: "&end=2015-07-01T20:11:00.781Z"
+	// End timestamp. Optional.
 	//
 	End string `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
-	// Query resolution step width in "duration" format or float number of seconds.
+	// Maximum number of returned label names. Optional. 0 means disabled.
 	//
-	// Example: "&step=15s"
+	// Example: "&limit=100"
 	//
-	Step string `protobuf:"bytes,4,opt,name=step,proto3" json:"step,omitempty"`
-	// Evaluation timeout. Optional.
-	Timeout string `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Limit int64 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *GetQueryRangeRpcRequest) Reset() {
-	*x = GetQueryRangeRpcRequest{}
+func (x *GetLabelsRpcRequest) Reset() {
+	*x = GetLabelsRpcRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_transport_grpc_prom_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -203,13 +193,13 @@ func (x *GetQueryRangeRpcRequest) Reset() {
 	}
 }
 
-func (x *GetQueryRangeRpcRequest) String() string {
+func (x *GetLabelsRpcRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetQueryRangeRpcRequest) ProtoMessage() {}
+func (*GetLabelsRpcRequest) ProtoMessage() {}
 
-func (x *GetQueryRangeRpcRequest) ProtoReflect() protoreflect.Message {
+func (x *GetLabelsRpcRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_transport_grpc_prom_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -221,57 +211,50 @@ func (x *GetQueryRangeRpcRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetQueryRangeRpcRequest.ProtoReflect.Descriptor instead.
-func (*GetQueryRangeRpcRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetLabelsRpcRequest.ProtoReflect.Descriptor instead.
+func (*GetLabelsRpcRequest) Descriptor() ([]byte, []int) {
 	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetQueryRangeRpcRequest) GetQuery() string {
+func (x *GetLabelsRpcRequest) GetMatch() []string {
 	if x != nil {
-		return x.Query
+		return x.Match
 	}
-	return ""
+	return nil
 }
 
-func (x *GetQueryRangeRpcRequest) GetStart() string {
+func (x *GetLabelsRpcRequest) GetStart() string {
 	if x != nil {
 		return x.Start
 	}
 	return ""
 }
 
-func (x *GetQueryRangeRpcRequest) GetEnd() string {
+func (x *GetLabelsRpcRequest) GetEnd() string {
 	if x != nil {
 		return x.End
 	}
 	return ""
 }
 
-func (x *GetQueryRangeRpcRequest) GetStep() string {
-	if x != nil {
-		return x.Step
-	}
-	return ""
-}
-
-func (x *GetQueryRangeRpcRequest) GetTimeout() string {
+func (x *GetLabelsRpcRequest) GetLimit() int64 {
 	if x != nil {
-		return x.Timeout
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-type GetQueryRangeRpcResponse struct {
+type GetLabelsRpcResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Data   []string `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Status string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 }
 
-func (x *GetQueryRangeRpcResponse) Reset() {
-	*x = GetQueryRangeRpcResponse{}
+func (x *GetLabelsRpcResponse) Reset() {
+	*x = GetLabelsRpcResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_transport_grpc_prom_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -279,13 +262,13 @@ func (x *GetQueryRangeRpcResponse) Reset() {
 	}
 }
 
-func (x *GetQueryRangeRpcResponse) String() string {
+func (x *GetLabelsRpcResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetQueryRangeRpcResponse) ProtoMessage() {}
+func (*GetLabelsRpcResponse) ProtoMessage() {}
 
-func (x *GetQueryRangeRpcResponse) ProtoReflect() protoreflect.Message {
+func (x *GetLabelsRpcResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_transport_grpc_prom_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -297,48 +280,44 @@ func (x *GetQueryRangeRpcResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetQueryRangeRpcResponse.ProtoReflect.Descriptor instead.
-func (*GetQueryRangeRpcResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetLabelsRpcResponse.ProtoReflect.Descriptor instead.
+func (*GetLabelsRpcResponse) Descriptor() ([]byte, []int) {
 	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetQueryRangeRpcResponse) GetData() *QueryData {
+func (x *GetLabelsRpcResponse) GetData() []string {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-func (x *GetQueryRangeRpcResponse) GetStatus() string {
+func (x *GetLabelsRpcResponse) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
 
-type GetQueryRpcRequest struct {
+type GetMetadataRpcRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Prometheus expression query string.
+	// Maximum number of metrics to return. Optional.
 	//
-	// Example: "?query=up"
+	// Example: "?limit=2"
 	//
-	// required
-	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	// Evaluation timestamp. Optional.
+	Limit int64 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// A metric name to filter metadata for. All metric metadata is retrieved if left empty. Optional.
 	//
-	// The current server time is used if the "time" parameter is omitted.
+	// Example: "?metric=http_requests_total"
 	//
-	// Optional.
-	Time string `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
-	// Evaluation timeout. Optional.
-	Timeout string `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Metric string `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
 }
 
-func (x *GetQueryRpcRequest) Reset() {
-	*x = GetQueryRpcRequest{}
+func (x *GetMetadataRpcRequest) Reset() {
+	*x = GetMetadataRpcRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_transport_grpc_prom_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -346,13 +325,13 @@ func (x *GetQueryRpcRequest) Reset() {
 	}
 }
 
-func (x *GetQueryRpcRequest) String() string {
+func (x *GetMetadataRpcRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetQueryRpcRequest) ProtoMessage() {}
+func (*GetMetadataRpcRequest) ProtoMessage() {}
 
-func (x *GetQueryRpcRequest) ProtoReflect() protoreflect.Message {
+func (x *GetMetadataRpcRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_transport_grpc_prom_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -364,43 +343,36 @@ func (x *GetQueryRpcRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetQueryRpcRequest.ProtoReflect.Descriptor instead.
-func (*GetQueryRpcRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetMetadataRpcRequest.ProtoReflect.Descriptor instead.
+func (*GetMetadataRpcRequest) Descriptor() ([]byte, []int) {
 	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GetQueryRpcRequest) GetQuery() string {
-	if x != nil {
-		return x.Query
-	}
-	return ""
-}
-
-func (x *GetQueryRpcRequest) GetTime() string {
+func (x *GetMetadataRpcRequest) GetLimit() int64 {
 	if x != nil {
-		return x.Time
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-func (x *GetQueryRpcRequest) GetTimeout() string {
+func (x *GetMetadataRpcRequest) GetMetric() string {
 	if x != nil {
-		return x.Timeout
+		return x.Metric
 	}
 	return ""
 }
 
-type GetQueryRpcResponse struct {
+type GetMetadataRpcResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Data   map[string]*MetricMetadataList `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Status string                         `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 }
 
-func (x *GetQueryRpcResponse) Reset() {
-	*x = GetQueryRpcResponse{}
+func (x *GetMetadataRpcResponse) Reset() {
+	*x = GetMetadataRpcResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_transport_grpc_prom_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -408,13 +380,13 @@ func (x *GetQueryRpcResponse) Reset() {
 	}
 }
 
-func (x *GetQueryRpcResponse) String() string {
+func (x *GetMetadataRpcResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetQueryRpcResponse) ProtoMessage() {}
+func (*GetMetadataRpcResponse) ProtoMessage() {}
 
-func (x *GetQueryRpcResponse) ProtoReflect() protoreflect.Message {
+func (x *GetMetadataRpcResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_transport_grpc_prom_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -426,81 +398,26 @@ func (x *GetQueryRpcResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetQueryRpcResponse.ProtoReflect.Descriptor instead.
-func (*GetQueryRpcResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetMetadataRpcResponse.ProtoReflect.Descriptor instead.
+func (*GetMetadataRpcResponse) Descriptor() ([]byte, []int) {
 	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetQueryRpcResponse) GetData() *QueryData {
+func (x *GetMetadataRpcResponse) GetData() map[string]*MetricMetadataList {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-func (x *GetQueryRpcResponse) GetStatus() string {
+func (x *GetMetadataRpcResponse) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
 
-type QueryData struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Result     *anypb.Any `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
-	ResultType string     `protobuf:"bytes,2,opt,name=resultType,proto3" json:"resultType,omitempty"`
-}
-
-func (x *QueryData) Reset() {
-	*x = QueryData{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_transport_grpc_prom_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *QueryData) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*QueryData) ProtoMessage() {}
-
-func (x *QueryData) ProtoReflect() protoreflect.Message {
-	mi := &file_transport_grpc_prom_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use QueryData.ProtoReflect.Descriptor instead.
-func (*QueryData) Descriptor() ([]byte, []int) {
-	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *QueryData) GetResult() *anypb.Any {
-	if x != nil {
-		return x.Result
-	}
-	return nil
-}
-
-func (x *QueryData) GetResultType() string {
-	if x != nil {
-		return x.ResultType
-	}
-	return ""
-}
-
-type QueryRangeRpcRequest struct {
+type GetQueryRangeRpcRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -528,25 +445,39 @@ type QueryRangeRpcRequest struct {
 	Step string `protobuf:"bytes,4,opt,name=step,proto3" json:"step,omitempty"`
 	// Evaluation timeout. Optional.
 	Timeout string `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// How empty steps are filled: none, null, previous or linear. Optional.
+	//
+	// Default is the datasource's setting.
+	//
+	// Example: "&fill=previous"
+	//
+	Fill string `protobuf:"bytes,6,opt,name=fill,proto3" json:"fill,omitempty"`
+	// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+	//
+	// Default is the X-Timezone header, then the datasource's setting.
+	//
+	// Example: "&timezone=Europe/Berlin"
+	//
+	Timezone string `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`
 }
 
-func (x *QueryRangeRpcRequest) Reset() {
-	*x = QueryRangeRpcRequest{}
+func (x *GetQueryRangeRpcRequest) Reset() {
+	*x = GetQueryRangeRpcRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_transport_grpc_prom_proto_msgTypes[7]
+		mi := &file_transport_grpc_prom_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *QueryRangeRpcRequest) String() string {
+func (x *GetQueryRangeRpcRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryRangeRpcRequest) ProtoMessage() {}
+func (*GetQueryRangeRpcRequest) ProtoMessage() {}
 
-func (x *QueryRangeRpcRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_transport_grpc_prom_proto_msgTypes[7]
+func (x *GetQueryRangeRpcRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -557,47 +488,61 @@ func (x *QueryRangeRpcRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryRangeRpcRequest.ProtoReflect.Descriptor instead.
-func (*QueryRangeRpcRequest) Descriptor() ([]byte, []int) {
-	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use GetQueryRangeRpcRequest.ProtoReflect.Descriptor instead.
+func (*GetQueryRangeRpcRequest) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *QueryRangeRpcRequest) GetQuery() string {
+func (x *GetQueryRangeRpcRequest) GetQuery() string {
 	if x != nil {
 		return x.Query
 	}
 	return ""
 }
 
-func (x *QueryRangeRpcRequest) GetStart() string {
+func (x *GetQueryRangeRpcRequest) GetStart() string {
 	if x != nil {
 		return x.Start
 	}
 	return ""
 }
 
-func (x *QueryRangeRpcRequest) GetEnd() string {
+func (x *GetQueryRangeRpcRequest) GetEnd() string {
 	if x != nil {
 		return x.End
 	}
 	return ""
 }
 
-func (x *QueryRangeRpcRequest) GetStep() string {
+func (x *GetQueryRangeRpcRequest) GetStep() string {
 	if x != nil {
 		return x.Step
 	}
 	return ""
 }
 
-func (x *QueryRangeRpcRequest) GetTimeout() string {
+func (x *GetQueryRangeRpcRequest) GetTimeout() string {
 	if x != nil {
 		return x.Timeout
 	}
 	return ""
 }
 
-type QueryRangeRpcResponse struct {
+func (x *GetQueryRangeRpcRequest) GetFill() string {
+	if x != nil {
+		return x.Fill
+	}
+	return ""
+}
+
+func (x *GetQueryRangeRpcRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type GetQueryRangeRpcResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -606,23 +551,23 @@ type QueryRangeRpcResponse struct {
 	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 }
 
-func (x *QueryRangeRpcResponse) Reset() {
-	*x = QueryRangeRpcResponse{}
+func (x *GetQueryRangeRpcResponse) Reset() {
+	*x = GetQueryRangeRpcResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_transport_grpc_prom_proto_msgTypes[8]
+		mi := &file_transport_grpc_prom_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *QueryRangeRpcResponse) String() string {
+func (x *GetQueryRangeRpcResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryRangeRpcResponse) ProtoMessage() {}
+func (*GetQueryRangeRpcResponse) ProtoMessage() {}
 
-func (x *QueryRangeRpcResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_transport_grpc_prom_proto_msgTypes[8]
+func (x *GetQueryRangeRpcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -633,50 +578,138 @@ func (x *QueryRangeRpcResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryRangeRpcResponse.ProtoReflect.Descriptor instead.
-func (*QueryRangeRpcResponse) Descriptor() ([]byte, []int) {
-	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetQueryRangeRpcResponse.ProtoReflect.Descriptor instead.
+func (*GetQueryRangeRpcResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *QueryRangeRpcResponse) GetData() *QueryData {
+func (x *GetQueryRangeRpcResponse) GetData() *QueryData {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-func (x *QueryRangeRpcResponse) GetStatus() string {
+func (x *GetQueryRangeRpcResponse) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
 
-type QueryResponse struct {
+type GetQueryRpcRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Prometheus expression query string.
+	//
+	// Example: "?query=up"
+	//
+	// required
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Evaluation timestamp. Optional.
+	//
+	// The current server time is used if the "time" parameter is omitted.
+	//
+	// Optional.
+	Time string `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// Evaluation timeout. Optional.
+	Timeout string `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+	//
+	// Default is the X-Timezone header, then the datasource's setting.
+	//
+	// Example: "&timezone=Europe/Berlin"
+	//
+	Timezone string `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
 }
 
-func (x *QueryResponse) Reset() {
-	*x = QueryResponse{}
+func (x *GetQueryRpcRequest) Reset() {
+	*x = GetQueryRpcRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_transport_grpc_prom_proto_msgTypes[9]
+		mi := &file_transport_grpc_prom_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *QueryResponse) String() string {
+func (x *GetQueryRpcRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryResponse) ProtoMessage() {}
+func (*GetQueryRpcRequest) ProtoMessage() {}
 
-func (x *QueryResponse) ProtoReflect() protoreflect.Message {
+func (x *GetQueryRpcRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQueryRpcRequest.ProtoReflect.Descriptor instead.
+func (*GetQueryRpcRequest) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetQueryRpcRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GetQueryRpcRequest) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *GetQueryRpcRequest) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *GetQueryRpcRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type GetQueryRpcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *GetQueryRpcResponse) Reset() {
+	*x = GetQueryRpcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetQueryRpcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQueryRpcResponse) ProtoMessage() {}
+
+func (x *GetQueryRpcResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_transport_grpc_prom_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -688,48 +721,46 @@ func (x *QueryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
-func (*QueryResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetQueryRpcResponse.ProtoReflect.Descriptor instead.
+func (*GetQueryRpcResponse) Descriptor() ([]byte, []int) {
 	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *QueryResponse) GetData() *QueryData {
+func (x *GetQueryRpcResponse) GetData() *QueryData {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-func (x *QueryResponse) GetStatus() string {
+func (x *GetQueryRpcResponse) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
 
-type QueryRpcRequest struct {
+type GetSeriesRpcRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Prometheus expression query string.
+	// Repeated series selector argument that selects the series to return. At least one match[] argument must be provided.
 	//
-	// Example: "?query=up"
+	// Example: "?match[]=up&match[]=process_start_time_seconds{job=\"prometheus\"}"
 	//
 	// required
-	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	// Evaluation timestamp. Optional.
+	Match []string `protobuf:"bytes,1,rep,name=match,proto3" json:"match,omitempty"`
+	// Start timestamp. Optional.
 	//
-	// The current server time is used if the "time" parameter is omitted.
+	Start string `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	// End timestamp. Optional.
 	//
-	// Optional.
-	Time string `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
-	// Evaluation timeout. Optional.
-	Timeout string `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	End string `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
 }
 
-func (x *QueryRpcRequest) Reset() {
-	*x = QueryRpcRequest{}
+func (x *GetSeriesRpcRequest) Reset() {
+	*x = GetSeriesRpcRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_transport_grpc_prom_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -737,13 +768,13 @@ func (x *QueryRpcRequest) Reset() {
 	}
 }
 
-func (x *QueryRpcRequest) String() string {
+func (x *GetSeriesRpcRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryRpcRequest) ProtoMessage() {}
+func (*GetSeriesRpcRequest) ProtoMessage() {}
 
-func (x *QueryRpcRequest) ProtoReflect() protoreflect.Message {
+func (x *GetSeriesRpcRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_transport_grpc_prom_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -755,58 +786,1410 @@ func (x *QueryRpcRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryRpcRequest.ProtoReflect.Descriptor instead.
-func (*QueryRpcRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetSeriesRpcRequest.ProtoReflect.Descriptor instead.
+func (*GetSeriesRpcRequest) Descriptor() ([]byte, []int) {
 	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *QueryRpcRequest) GetQuery() string {
+func (x *GetSeriesRpcRequest) GetMatch() []string {
 	if x != nil {
-		return x.Query
+		return x.Match
+	}
+	return nil
+}
+
+func (x *GetSeriesRpcRequest) GetStart() string {
+	if x != nil {
+		return x.Start
 	}
 	return ""
 }
 
-func (x *QueryRpcRequest) GetTime() string {
+func (x *GetSeriesRpcRequest) GetEnd() string {
 	if x != nil {
-		return x.Time
+		return x.End
 	}
 	return ""
 }
 
-func (x *QueryRpcRequest) GetTimeout() string {
+type GetSeriesRpcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   []*LabelSet `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Status string      `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *GetSeriesRpcResponse) Reset() {
+	*x = GetSeriesRpcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSeriesRpcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSeriesRpcResponse) ProtoMessage() {}
+
+func (x *GetSeriesRpcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSeriesRpcResponse.ProtoReflect.Descriptor instead.
+func (*GetSeriesRpcResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetSeriesRpcResponse) GetData() []*LabelSet {
 	if x != nil {
-		return x.Timeout
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GetSeriesRpcResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type LabelSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Labels map[string]string `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *LabelSet) Reset() {
+	*x = LabelSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelSet) ProtoMessage() {}
+
+func (x *LabelSet) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelSet.ProtoReflect.Descriptor instead.
+func (*LabelSet) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LabelSet) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type LabelsRpcRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Repeated series selector argument that selects the series from which to read the label names. Optional.
+	//
+	Match []string `protobuf:"bytes,1,rep,name=match,proto3" json:"match,omitempty"`
+	// Start timestamp. Optional.
+	//
+	Start string `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	// End timestamp. Optional.
+	//
+	End string `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	// Maximum number of returned label names. Optional. 0 means disabled.
+	//
+	// Example: "&limit=100"
+	//
+	Limit int64 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *LabelsRpcRequest) Reset() {
+	*x = LabelsRpcRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelsRpcRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelsRpcRequest) ProtoMessage() {}
+
+func (x *LabelsRpcRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelsRpcRequest.ProtoReflect.Descriptor instead.
+func (*LabelsRpcRequest) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LabelsRpcRequest) GetMatch() []string {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *LabelsRpcRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *LabelsRpcRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *LabelsRpcRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type LabelsRpcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   []string `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Status string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *LabelsRpcResponse) Reset() {
+	*x = LabelsRpcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelsRpcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelsRpcResponse) ProtoMessage() {}
+
+func (x *LabelsRpcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelsRpcResponse.ProtoReflect.Descriptor instead.
+func (*LabelsRpcResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LabelsRpcResponse) GetData() []string {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *LabelsRpcResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type Matrix struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Series []*Series `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+}
+
+func (x *Matrix) Reset() {
+	*x = Matrix{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Matrix) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Matrix) ProtoMessage() {}
+
+func (x *Matrix) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Matrix.ProtoReflect.Descriptor instead.
+func (*Matrix) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Matrix) GetSeries() []*Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type MetricMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Help string `protobuf:"bytes,2,opt,name=help,proto3" json:"help,omitempty"`
+	Unit string `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (x *MetricMetadata) Reset() {
+	*x = MetricMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricMetadata) ProtoMessage() {}
+
+func (x *MetricMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricMetadata.ProtoReflect.Descriptor instead.
+func (*MetricMetadata) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MetricMetadata) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MetricMetadata) GetHelp() string {
+	if x != nil {
+		return x.Help
+	}
+	return ""
+}
+
+func (x *MetricMetadata) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type MetricMetadataList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metadata []*MetricMetadata `protobuf:"bytes,1,rep,name=metadata,proto3" json:"metadata,omitempty"`
+}
+
+func (x *MetricMetadataList) Reset() {
+	*x = MetricMetadataList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricMetadataList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricMetadataList) ProtoMessage() {}
+
+func (x *MetricMetadataList) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricMetadataList.ProtoReflect.Descriptor instead.
+func (*MetricMetadataList) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MetricMetadataList) GetMetadata() []*MetricMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type Point struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unix timestamp in milliseconds
+	Timestamp int64   `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value     float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Point) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Point) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type QueryData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ResultType string `protobuf:"bytes,2,opt,name=resultType,proto3" json:"resultType,omitempty"`
+	// Result is decided by resultType
+	//
+	// Types that are assignable to Result:
+	//	*QueryData_Vector
+	//	*QueryData_Matrix
+	//	*QueryData_Scalar
+	//	*QueryData_StringValue
+	Result isQueryData_Result `protobuf_oneof:"result"`
+}
+
+func (x *QueryData) Reset() {
+	*x = QueryData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryData) ProtoMessage() {}
+
+func (x *QueryData) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryData.ProtoReflect.Descriptor instead.
+func (*QueryData) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *QueryData) GetResultType() string {
+	if x != nil {
+		return x.ResultType
+	}
+	return ""
+}
+
+func (m *QueryData) GetResult() isQueryData_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *QueryData) GetVector() *Vector {
+	if x, ok := x.GetResult().(*QueryData_Vector); ok {
+		return x.Vector
+	}
+	return nil
+}
+
+func (x *QueryData) GetMatrix() *Matrix {
+	if x, ok := x.GetResult().(*QueryData_Matrix); ok {
+		return x.Matrix
+	}
+	return nil
+}
+
+func (x *QueryData) GetScalar() *Scalar {
+	if x, ok := x.GetResult().(*QueryData_Scalar); ok {
+		return x.Scalar
+	}
+	return nil
+}
+
+func (x *QueryData) GetStringValue() *String {
+	if x, ok := x.GetResult().(*QueryData_StringValue); ok {
+		return x.StringValue
+	}
+	return nil
+}
+
+type isQueryData_Result interface {
+	isQueryData_Result()
+}
+
+type QueryData_Vector struct {
+	Vector *Vector `protobuf:"bytes,3,opt,name=vector,proto3,oneof"`
+}
+
+type QueryData_Matrix struct {
+	Matrix *Matrix `protobuf:"bytes,4,opt,name=matrix,proto3,oneof"`
+}
+
+type QueryData_Scalar struct {
+	Scalar *Scalar `protobuf:"bytes,5,opt,name=scalar,proto3,oneof"`
+}
+
+type QueryData_StringValue struct {
+	StringValue *String `protobuf:"bytes,6,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+func (*QueryData_Vector) isQueryData_Result() {}
+
+func (*QueryData_Matrix) isQueryData_Result() {}
+
+func (*QueryData_Scalar) isQueryData_Result() {}
+
+func (*QueryData_StringValue) isQueryData_Result() {}
+
+type QueryRangeRpcRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Prometheus expression query string.
+	//
+	// Example: "?query=up"
+	//
+	// required
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Start timestamp.
+	//
+	// Example: "&start=2015-07-01T20:10:30.781Z"
+	//
+	Start string `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	// End timestamp.
+	//
+	// Example: "&end=2015-07-01T20:11:00.781Z"
+	//
+	End string `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	// Query resolution step width in "duration" format or float number of seconds.
+	//
+	// Example: "&step=15s"
+	//
+	Step string `protobuf:"bytes,4,opt,name=step,proto3" json:"step,omitempty"`
+	// Evaluation timeout. Optional.
+	Timeout string `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// How empty steps are filled: none, null, previous or linear. Optional.
+	//
+	// Default is the datasource's setting.
+	//
+	// Example: "&fill=previous"
+	//
+	Fill string `protobuf:"bytes,6,opt,name=fill,proto3" json:"fill,omitempty"`
+	// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+	//
+	// Default is the X-Timezone header, then the datasource's setting.
+	//
+	// Example: "&timezone=Europe/Berlin"
+	//
+	Timezone string `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`
+}
+
+func (x *QueryRangeRpcRequest) Reset() {
+	*x = QueryRangeRpcRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryRangeRpcRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRangeRpcRequest) ProtoMessage() {}
+
+func (x *QueryRangeRpcRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRangeRpcRequest.ProtoReflect.Descriptor instead.
+func (*QueryRangeRpcRequest) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *QueryRangeRpcRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *QueryRangeRpcRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *QueryRangeRpcRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *QueryRangeRpcRequest) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *QueryRangeRpcRequest) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *QueryRangeRpcRequest) GetFill() string {
+	if x != nil {
+		return x.Fill
+	}
+	return ""
+}
+
+func (x *QueryRangeRpcRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type QueryRangeRpcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *QueryRangeRpcResponse) Reset() {
+	*x = QueryRangeRpcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryRangeRpcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRangeRpcResponse) ProtoMessage() {}
+
+func (x *QueryRangeRpcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRangeRpcResponse.ProtoReflect.Descriptor instead.
+func (*QueryRangeRpcResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *QueryRangeRpcResponse) GetData() *QueryData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *QueryRangeRpcResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type QueryRangeStreamRpcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Series decoded from a chunk of InfluxDB response, a series may be split across consecutive responses
+	Series []*Series `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+}
+
+func (x *QueryRangeStreamRpcResponse) Reset() {
+	*x = QueryRangeStreamRpcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryRangeStreamRpcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRangeStreamRpcResponse) ProtoMessage() {}
+
+func (x *QueryRangeStreamRpcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRangeStreamRpcResponse.ProtoReflect.Descriptor instead.
+func (*QueryRangeStreamRpcResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *QueryRangeStreamRpcResponse) GetSeries() []*Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type QueryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *QueryResponse) Reset() {
+	*x = QueryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryResponse) ProtoMessage() {}
+
+func (x *QueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *QueryResponse) GetData() *QueryData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type QueryRpcRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Prometheus expression query string.
+	//
+	// Example: "?query=up"
+	//
+	// required
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Evaluation timestamp. Optional.
+	//
+	// The current server time is used if the "time" parameter is omitted.
+	//
+	// Optional.
+	Time string `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// Evaluation timeout. Optional.
+	Timeout string `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// IANA timezone name used to align GROUP BY time() buckets, e.g. Asia/Shanghai. Optional.
+	//
+	// Default is the X-Timezone header, then the datasource's setting.
+	//
+	// Example: "&timezone=Europe/Berlin"
+	//
+	Timezone string `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+}
+
+func (x *QueryRpcRequest) Reset() {
+	*x = QueryRpcRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryRpcRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRpcRequest) ProtoMessage() {}
+
+func (x *QueryRpcRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRpcRequest.ProtoReflect.Descriptor instead.
+func (*QueryRpcRequest) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *QueryRpcRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *QueryRpcRequest) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *QueryRpcRequest) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *QueryRpcRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type QueryRpcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *QueryRpcResponse) Reset() {
+	*x = QueryRpcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryRpcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRpcResponse) ProtoMessage() {}
+
+func (x *QueryRpcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRpcResponse.ProtoReflect.Descriptor instead.
+func (*QueryRpcResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *QueryRpcResponse) GetData() *QueryData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *QueryRpcResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type Sample struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metric map[string]string `protobuf:"bytes,1,rep,name=metric,proto3" json:"metric,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Unix timestamp in milliseconds
+	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value     float64 `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Sample) Reset() {
+	*x = Sample{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Sample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sample) ProtoMessage() {}
+
+func (x *Sample) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sample.ProtoReflect.Descriptor instead.
+func (*Sample) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Sample) GetMetric() map[string]string {
+	if x != nil {
+		return x.Metric
+	}
+	return nil
+}
+
+func (x *Sample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Sample) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type Scalar struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unix timestamp in milliseconds
+	Timestamp int64   `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value     float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Scalar) Reset() {
+	*x = Scalar{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Scalar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scalar) ProtoMessage() {}
+
+func (x *Scalar) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scalar.ProtoReflect.Descriptor instead.
+func (*Scalar) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Scalar) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Scalar) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type Series struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metric map[string]string `protobuf:"bytes,1,rep,name=metric,proto3" json:"metric,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Points []*Point          `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+}
+
+func (x *Series) Reset() {
+	*x = Series{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Series) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Series) ProtoMessage() {}
+
+func (x *Series) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Series.ProtoReflect.Descriptor instead.
+func (*Series) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Series) GetMetric() map[string]string {
+	if x != nil {
+		return x.Metric
+	}
+	return nil
+}
+
+func (x *Series) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+type SeriesRpcRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Repeated series selector argument that selects the series to return. At least one match[] argument must be provided.
+	//
+	// Example: "?match[]=up&match[]=process_start_time_seconds{job=\"prometheus\"}"
+	//
+	// required
+	Match []string `protobuf:"bytes,1,rep,name=match,proto3" json:"match,omitempty"`
+	// Start timestamp. Optional.
+	//
+	Start string `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	// End timestamp. Optional.
+	//
+	End string `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *SeriesRpcRequest) Reset() {
+	*x = SeriesRpcRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SeriesRpcRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeriesRpcRequest) ProtoMessage() {}
+
+func (x *SeriesRpcRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeriesRpcRequest.ProtoReflect.Descriptor instead.
+func (*SeriesRpcRequest) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SeriesRpcRequest) GetMatch() []string {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *SeriesRpcRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *SeriesRpcRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type SeriesRpcResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   []*LabelSet `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Status string      `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *SeriesRpcResponse) Reset() {
+	*x = SeriesRpcResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SeriesRpcResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeriesRpcResponse) ProtoMessage() {}
+
+func (x *SeriesRpcResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeriesRpcResponse.ProtoReflect.Descriptor instead.
+func (*SeriesRpcResponse) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SeriesRpcResponse) GetData() []*LabelSet {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SeriesRpcResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type String struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unix timestamp in milliseconds
+	Timestamp int64  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value     string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *String) Reset() {
+	*x = String{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_grpc_prom_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *String) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*String) ProtoMessage() {}
+
+func (x *String) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use String.ProtoReflect.Descriptor instead.
+func (*String) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *String) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *String) GetValue() string {
+	if x != nil {
+		return x.Value
 	}
 	return ""
 }
 
-type QueryRpcResponse struct {
+type Vector struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data   *QueryData `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Status string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Samples []*Sample `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
 }
 
-func (x *QueryRpcResponse) Reset() {
-	*x = QueryRpcResponse{}
+func (x *Vector) Reset() {
+	*x = Vector{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_transport_grpc_prom_proto_msgTypes[11]
+		mi := &file_transport_grpc_prom_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *QueryRpcResponse) String() string {
+func (x *Vector) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryRpcResponse) ProtoMessage() {}
+func (*Vector) ProtoMessage() {}
 
-func (x *QueryRpcResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_transport_grpc_prom_proto_msgTypes[11]
+func (x *Vector) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_grpc_prom_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -817,136 +2200,291 @@ func (x *QueryRpcResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryRpcResponse.ProtoReflect.Descriptor instead.
-func (*QueryRpcResponse) Descriptor() ([]byte, []int) {
-	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use Vector.ProtoReflect.Descriptor instead.
+func (*Vector) Descriptor() ([]byte, []int) {
+	return file_transport_grpc_prom_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *QueryRpcResponse) GetData() *QueryData {
+func (x *Vector) GetSamples() []*Sample {
 	if x != nil {
-		return x.Data
+		return x.Samples
 	}
 	return nil
 }
 
-func (x *QueryRpcResponse) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
-}
-
 var File_transport_grpc_prom_proto protoreflect.FileDescriptor
 
 var file_transport_grpc_prom_proto_rawDesc = []byte{
 	0x0a, 0x19, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x67, 0x72, 0x70, 0x63,
 	0x2f, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x70, 0x72, 0x6f,
-	0x6d, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x7f, 0x0a, 0x21,
-	0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d,
-	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12,
-	0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x50, 0x0a,
-	0x22, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61,
-	0x6d, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
-	0x85, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67,
-	0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71,
-	0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x6d, 0x22, 0x7f, 0x0a, 0x21, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61,
+	0x6d, 0x65, 0x22, 0x50, 0x0a, 0x22, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x69, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65,
-	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x18, 0x0a,
-	0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x57, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61,
-	0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22,
+	0x42, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x52, 0x70, 0x63, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x22, 0x45, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x22, 0xbf, 0x01, 0x0a, 0x16, 0x47,
+	0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x1a, 0x51, 0x0a, 0x09, 0x44, 0x61, 0x74,
+	0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x4d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb5, 0x01, 0x0a,
+	0x17, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70,
+	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x6c, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65,
+	0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65,
+	0x7a, 0x6f, 0x6e, 0x65, 0x22, 0x57, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x23, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x74, 0x0a,
+	0x12, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a,
+	0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a,
+	0x6f, 0x6e, 0x65, 0x22, 0x52, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x53, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x52, 0x0a, 0x14,
+	0x47, 0x65, 0x74, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53,
+	0x65, 0x74, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
 	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x22, 0x58, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04,
-	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
-	0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x52, 0x0a, 0x13, 0x47, 0x65,
-	0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x22, 0x79, 0x0a, 0x08, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x74, 0x12, 0x32, 0x0a, 0x06,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70,
+	0x72, 0x6f, 0x6d, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x74, 0x2e, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x66, 0x0a, 0x10, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65,
+	0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x22, 0x3f, 0x0a, 0x11, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x52, 0x70, 0x63,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x2e, 0x0a, 0x06, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x12, 0x24,
+	0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x06, 0x73, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x22, 0x4c, 0x0a, 0x0e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x65,
+	0x6c, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x65, 0x6c, 0x70, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e,
+	0x69, 0x74, 0x22, 0x46, 0x0a, 0x12, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f,
+	0x6d, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x3b, 0x0a, 0x05, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xe6, 0x01, 0x0a, 0x09, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x56, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x06, 0x76, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x26, 0x0a,
+	0x06, 0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x48, 0x00, 0x52, 0x06, 0x6d,
+	0x61, 0x74, 0x72, 0x69, 0x78, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53, 0x63, 0x61,
+	0x6c, 0x61, 0x72, 0x48, 0x00, 0x52, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x12, 0x31, 0x0a,
+	0x0c, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02,
+	0x22, 0xb2, 0x01, 0x0a, 0x14, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65,
+	0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x6c, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x22, 0x54, 0x0a, 0x15, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70,
+	0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x43, 0x0a, 0x1b, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x06, 0x73, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f,
+	0x6d, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73,
+	0x22, 0x4c, 0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
 	0x65, 0x12, 0x23, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61,
 	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x59,
-	0x0a, 0x09, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x06, 0x72,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e,
-	0x79, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x82, 0x01, 0x0a, 0x14, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10,
-	0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64,
-	0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x73, 0x74, 0x65, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x54,
-	0x0a, 0x15, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65,
-	0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x22, 0x4c, 0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x71,
+	0x0a, 0x0f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e,
+	0x65, 0x22, 0x4f, 0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73,
 	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79,
 	0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
 	0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x22, 0x55, 0x0a, 0x0f, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74,
-	0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x4f, 0x0a, 0x10, 0x51, 0x75, 0x65,
-	0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72,
-	0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x32, 0x9a, 0x03, 0x0a, 0x0b, 0x50,
-	0x72, 0x6f, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x08, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
-	0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x52, 0x70, 0x63, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x51,
-	0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
-	0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70,
-	0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0d, 0x51, 0x75, 0x65,
-	0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f,
-	0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
-	0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47,
-	0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65,
-	0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62,
-	0x65, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65,
-	0x73, 0x52, 0x70, 0x63, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4c,
-	0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x56, 0x61, 0x6c,
-	0x75, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e,
-	0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61, 0x62,
-	0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x75, 0x62, 0x69, 0x6e, 0x31, 0x39, 0x38, 0x39, 0x2f,
-	0x70, 0x72, 0x6f, 0x6d, 0x71, 0x6c, 0x32, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x78, 0x71, 0x6c, 0x2f,
-	0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x70, 0x72, 0x6f,
-	0x6d, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x67, 0x72, 0x70, 0x63,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x75, 0x73, 0x22, 0xa9, 0x01, 0x0a, 0x06, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12, 0x30, 0x0a,
+	0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x1a, 0x39, 0x0a, 0x0b, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3c,
+	0x0a, 0x06, 0x53, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x9a, 0x01, 0x0a,
+	0x06, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x23, 0x0a, 0x06, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x6d,
+	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x1a, 0x39,
+	0x0a, 0x0b, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x50, 0x0a, 0x10, 0x53, 0x65, 0x72,
+	0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x4f, 0x0a, 0x11, 0x53,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x74, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x3c, 0x0a, 0x06,
+	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x30, 0x0a, 0x06, 0x56, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x32, 0xc9, 0x06, 0x0a,
+	0x0b, 0x50, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x08,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65,
+	0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0d, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x12, 0x1a, 0x2e, 0x70,
+	0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70,
+	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x6d,
+	0x2e, 0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70,
+	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e,
+	0x47, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x70, 0x63,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x13, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x70, 0x63, 0x12,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x70, 0x72,
+	0x6f, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01,
+	0x12, 0x6f, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63, 0x12, 0x27,
+	0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47,
+	0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3c, 0x0a, 0x09, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x12, 0x16,
+	0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x53, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x45, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x12,
+	0x19, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73,
+	0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f,
+	0x6d, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x52, 0x70, 0x63, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72,
+	0x6f, 0x6d, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x52, 0x70, 0x63, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x52, 0x70, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0e, 0x47,
+	0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x70, 0x63, 0x12, 0x1b, 0x2e,
+	0x70, 0x72, 0x6f, 0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x52, 0x70, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f,
+	0x6d, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x70, 0x63,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x75, 0x62, 0x69, 0x6e, 0x31, 0x39, 0x38, 0x39,
+	0x2f, 0x70, 0x72, 0x6f, 0x6d, 0x71, 0x6c, 0x32, 0x69, 0x6e, 0x66, 0x6c, 0x75, 0x78, 0x71, 0x6c,
+	0x2f, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x70, 0x72,
+	0x6f, 0x6d, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -961,44 +2499,95 @@ func file_transport_grpc_prom_proto_rawDescGZIP() []byte {
 	return file_transport_grpc_prom_proto_rawDescData
 }
 
-var file_transport_grpc_prom_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_transport_grpc_prom_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
 var file_transport_grpc_prom_proto_goTypes = []interface{}{
 	(*GetLabelLabelNameValuesRpcRequest)(nil),  // 0: prom.GetLabelLabelNameValuesRpcRequest
 	(*GetLabelLabelNameValuesRpcResponse)(nil), // 1: prom.GetLabelLabelNameValuesRpcResponse
-	(*GetQueryRangeRpcRequest)(nil),            // 2: prom.GetQueryRangeRpcRequest
-	(*GetQueryRangeRpcResponse)(nil),           // 3: prom.GetQueryRangeRpcResponse
-	(*GetQueryRpcRequest)(nil),                 // 4: prom.GetQueryRpcRequest
-	(*GetQueryRpcResponse)(nil),                // 5: prom.GetQueryRpcResponse
-	(*QueryData)(nil),                          // 6: prom.QueryData
-	(*QueryRangeRpcRequest)(nil),               // 7: prom.QueryRangeRpcRequest
-	(*QueryRangeRpcResponse)(nil),              // 8: prom.QueryRangeRpcResponse
-	(*QueryResponse)(nil),                      // 9: prom.QueryResponse
-	(*QueryRpcRequest)(nil),                    // 10: prom.QueryRpcRequest
-	(*QueryRpcResponse)(nil),                   // 11: prom.QueryRpcResponse
-	(*anypb.Any)(nil),                          // 12: google.protobuf.Any
+	(*GetLabelsRpcRequest)(nil),                // 2: prom.GetLabelsRpcRequest
+	(*GetLabelsRpcResponse)(nil),               // 3: prom.GetLabelsRpcResponse
+	(*GetMetadataRpcRequest)(nil),              // 4: prom.GetMetadataRpcRequest
+	(*GetMetadataRpcResponse)(nil),             // 5: prom.GetMetadataRpcResponse
+	(*GetQueryRangeRpcRequest)(nil),            // 6: prom.GetQueryRangeRpcRequest
+	(*GetQueryRangeRpcResponse)(nil),           // 7: prom.GetQueryRangeRpcResponse
+	(*GetQueryRpcRequest)(nil),                 // 8: prom.GetQueryRpcRequest
+	(*GetQueryRpcResponse)(nil),                // 9: prom.GetQueryRpcResponse
+	(*GetSeriesRpcRequest)(nil),                // 10: prom.GetSeriesRpcRequest
+	(*GetSeriesRpcResponse)(nil),               // 11: prom.GetSeriesRpcResponse
+	(*LabelSet)(nil),                           // 12: prom.LabelSet
+	(*LabelsRpcRequest)(nil),                   // 13: prom.LabelsRpcRequest
+	(*LabelsRpcResponse)(nil),                  // 14: prom.LabelsRpcResponse
+	(*Matrix)(nil),                             // 15: prom.Matrix
+	(*MetricMetadata)(nil),                     // 16: prom.MetricMetadata
+	(*MetricMetadataList)(nil),                 // 17: prom.MetricMetadataList
+	(*Point)(nil),                              // 18: prom.Point
+	(*QueryData)(nil),                          // 19: prom.QueryData
+	(*QueryRangeRpcRequest)(nil),               // 20: prom.QueryRangeRpcRequest
+	(*QueryRangeRpcResponse)(nil),              // 21: prom.QueryRangeRpcResponse
+	(*QueryRangeStreamRpcResponse)(nil),        // 22: prom.QueryRangeStreamRpcResponse
+	(*QueryResponse)(nil),                      // 23: prom.QueryResponse
+	(*QueryRpcRequest)(nil),                    // 24: prom.QueryRpcRequest
+	(*QueryRpcResponse)(nil),                   // 25: prom.QueryRpcResponse
+	(*Sample)(nil),                             // 26: prom.Sample
+	(*Scalar)(nil),                             // 27: prom.Scalar
+	(*Series)(nil),                             // 28: prom.Series
+	(*SeriesRpcRequest)(nil),                   // 29: prom.SeriesRpcRequest
+	(*SeriesRpcResponse)(nil),                  // 30: prom.SeriesRpcResponse
+	(*String)(nil),                             // 31: prom.String
+	(*Vector)(nil),                             // 32: prom.Vector
+	nil,                                        // 33: prom.GetMetadataRpcResponse.DataEntry
+	nil,                                        // 34: prom.LabelSet.LabelsEntry
+	nil,                                        // 35: prom.Sample.MetricEntry
+	nil,                                        // 36: prom.Series.MetricEntry
 }
 var file_transport_grpc_prom_proto_depIdxs = []int32{
-	6,  // 0: prom.GetQueryRangeRpcResponse.data:type_name -> prom.QueryData
-	6,  // 1: prom.GetQueryRpcResponse.data:type_name -> prom.QueryData
-	12, // 2: prom.QueryData.result:type_name -> google.protobuf.Any
-	6,  // 3: prom.QueryRangeRpcResponse.data:type_name -> prom.QueryData
-	6,  // 4: prom.QueryResponse.data:type_name -> prom.QueryData
-	6,  // 5: prom.QueryRpcResponse.data:type_name -> prom.QueryData
-	10, // 6: prom.PromService.QueryRpc:input_type -> prom.QueryRpcRequest
-	4,  // 7: prom.PromService.GetQueryRpc:input_type -> prom.GetQueryRpcRequest
-	7,  // 8: prom.PromService.QueryRangeRpc:input_type -> prom.QueryRangeRpcRequest
-	2,  // 9: prom.PromService.GetQueryRangeRpc:input_type -> prom.GetQueryRangeRpcRequest
-	0,  // 10: prom.PromService.GetLabelLabelNameValuesRpc:input_type -> prom.GetLabelLabelNameValuesRpcRequest
-	11, // 11: prom.PromService.QueryRpc:output_type -> prom.QueryRpcResponse
-	5,  // 12: prom.PromService.GetQueryRpc:output_type -> prom.GetQueryRpcResponse
-	8,  // 13: prom.PromService.QueryRangeRpc:output_type -> prom.QueryRangeRpcResponse
-	3,  // 14: prom.PromService.GetQueryRangeRpc:output_type -> prom.GetQueryRangeRpcResponse
-	1,  // 15: prom.PromService.GetLabelLabelNameValuesRpc:output_type -> prom.GetLabelLabelNameValuesRpcResponse
-	11, // [11:16] is the sub-list for method output_type
-	6,  // [6:11] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	33, // 0: prom.GetMetadataRpcResponse.data:type_name -> prom.GetMetadataRpcResponse.DataEntry
+	19, // 1: prom.GetQueryRangeRpcResponse.data:type_name -> prom.QueryData
+	19, // 2: prom.GetQueryRpcResponse.data:type_name -> prom.QueryData
+	12, // 3: prom.GetSeriesRpcResponse.data:type_name -> prom.LabelSet
+	34, // 4: prom.LabelSet.labels:type_name -> prom.LabelSet.LabelsEntry
+	28, // 5: prom.Matrix.series:type_name -> prom.Series
+	16, // 6: prom.MetricMetadataList.metadata:type_name -> prom.MetricMetadata
+	32, // 7: prom.QueryData.vector:type_name -> prom.Vector
+	15, // 8: prom.QueryData.matrix:type_name -> prom.Matrix
+	27, // 9: prom.QueryData.scalar:type_name -> prom.Scalar
+	31, // 10: prom.QueryData.string_value:type_name -> prom.String
+	19, // 11: prom.QueryRangeRpcResponse.data:type_name -> prom.QueryData
+	28, // 12: prom.QueryRangeStreamRpcResponse.series:type_name -> prom.Series
+	19, // 13: prom.QueryResponse.data:type_name -> prom.QueryData
+	19, // 14: prom.QueryRpcResponse.data:type_name -> prom.QueryData
+	35, // 15: prom.Sample.metric:type_name -> prom.Sample.MetricEntry
+	36, // 16: prom.Series.metric:type_name -> prom.Series.MetricEntry
+	18, // 17: prom.Series.points:type_name -> prom.Point
+	12, // 18: prom.SeriesRpcResponse.data:type_name -> prom.LabelSet
+	26, // 19: prom.Vector.samples:type_name -> prom.Sample
+	17, // 20: prom.GetMetadataRpcResponse.DataEntry.value:type_name -> prom.MetricMetadataList
+	24, // 21: prom.PromService.QueryRpc:input_type -> prom.QueryRpcRequest
+	8,  // 22: prom.PromService.GetQueryRpc:input_type -> prom.GetQueryRpcRequest
+	20, // 23: prom.PromService.QueryRangeRpc:input_type -> prom.QueryRangeRpcRequest
+	6,  // 24: prom.PromService.GetQueryRangeRpc:input_type -> prom.GetQueryRangeRpcRequest
+	20, // 25: prom.PromService.QueryRangeStreamRpc:input_type -> prom.QueryRangeRpcRequest
+	0,  // 26: prom.PromService.GetLabelLabelNameValuesRpc:input_type -> prom.GetLabelLabelNameValuesRpcRequest
+	29, // 27: prom.PromService.SeriesRpc:input_type -> prom.SeriesRpcRequest
+	10, // 28: prom.PromService.GetSeriesRpc:input_type -> prom.GetSeriesRpcRequest
+	13, // 29: prom.PromService.LabelsRpc:input_type -> prom.LabelsRpcRequest
+	2,  // 30: prom.PromService.GetLabelsRpc:input_type -> prom.GetLabelsRpcRequest
+	4,  // 31: prom.PromService.GetMetadataRpc:input_type -> prom.GetMetadataRpcRequest
+	25, // 32: prom.PromService.QueryRpc:output_type -> prom.QueryRpcResponse
+	9,  // 33: prom.PromService.GetQueryRpc:output_type -> prom.GetQueryRpcResponse
+	21, // 34: prom.PromService.QueryRangeRpc:output_type -> prom.QueryRangeRpcResponse
+	7,  // 35: prom.PromService.GetQueryRangeRpc:output_type -> prom.GetQueryRangeRpcResponse
+	22, // 36: prom.PromService.QueryRangeStreamRpc:output_type -> prom.QueryRangeStreamRpcResponse
+	1,  // 37: prom.PromService.GetLabelLabelNameValuesRpc:output_type -> prom.GetLabelLabelNameValuesRpcResponse
+	30, // 38: prom.PromService.SeriesRpc:output_type -> prom.SeriesRpcResponse
+	11, // 39: prom.PromService.GetSeriesRpc:output_type -> prom.GetSeriesRpcResponse
+	14, // 40: prom.PromService.LabelsRpc:output_type -> prom.LabelsRpcResponse
+	3,  // 41: prom.PromService.GetLabelsRpc:output_type -> prom.GetLabelsRpcResponse
+	5,  // 42: prom.PromService.GetMetadataRpc:output_type -> prom.GetMetadataRpcResponse
+	32, // [32:43] is the sub-list for method output_type
+	21, // [21:32] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_transport_grpc_prom_proto_init() }
@@ -1032,7 +2621,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetQueryRangeRpcRequest); i {
+			switch v := v.(*GetLabelsRpcRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1044,7 +2633,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetQueryRangeRpcResponse); i {
+			switch v := v.(*GetLabelsRpcResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1056,7 +2645,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetQueryRpcRequest); i {
+			switch v := v.(*GetMetadataRpcRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1068,7 +2657,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetQueryRpcResponse); i {
+			switch v := v.(*GetMetadataRpcResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1080,7 +2669,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryData); i {
+			switch v := v.(*GetQueryRangeRpcRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1092,7 +2681,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryRangeRpcRequest); i {
+			switch v := v.(*GetQueryRangeRpcResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1104,7 +2693,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryRangeRpcResponse); i {
+			switch v := v.(*GetQueryRpcRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1116,7 +2705,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryResponse); i {
+			switch v := v.(*GetQueryRpcResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1128,7 +2717,7 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryRpcRequest); i {
+			switch v := v.(*GetSeriesRpcRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1140,6 +2729,174 @@ func file_transport_grpc_prom_proto_init() {
 			}
 		}
 		file_transport_grpc_prom_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSeriesRpcResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LabelSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LabelsRpcRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LabelsRpcResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Matrix); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetricMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetricMetadataList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Point); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryRangeRpcRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryRangeRpcResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryRangeStreamRpcResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryRpcRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*QueryRpcResponse); i {
 			case 0:
 				return &v.state
@@ -1151,6 +2908,96 @@ func file_transport_grpc_prom_proto_init() {
 				return nil
 			}
 		}
+		file_transport_grpc_prom_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Sample); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Scalar); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Series); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SeriesRpcRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SeriesRpcResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*String); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_transport_grpc_prom_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Vector); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_transport_grpc_prom_proto_msgTypes[19].OneofWrappers = []interface{}{
+		(*QueryData_Vector)(nil),
+		(*QueryData_Matrix)(nil),
+		(*QueryData_Scalar)(nil),
+		(*QueryData_StringValue)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1158,7 +3005,7 @@ func file_transport_grpc_prom_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_transport_grpc_prom_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
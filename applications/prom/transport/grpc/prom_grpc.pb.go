@@ -26,12 +26,45 @@ type PromServiceClient interface {
 	QueryRangeRpc(ctx context.Context, in *QueryRangeRpcRequest, opts ...grpc.CallOption) (*QueryRangeRpcResponse, error)
 	// GetQuery_range is compatible to Prometheus GET /api/v1/query_range
 	GetQueryRangeRpc(ctx context.Context, in *GetQueryRangeRpcRequest, opts ...grpc.CallOption) (*GetQueryRangeRpcResponse, error)
+	// QueryRangeStream streams matrix result of range query chunk by chunk as soon as they are decoded from InfluxDB
+	QueryRangeStreamRpc(ctx context.Context, in *QueryRangeRpcRequest, opts ...grpc.CallOption) (PromService_QueryRangeStreamRpcClient, error)
 	// GetLabel_Label_nameValues Returns label values
 	// The following endpoint returns a list of label values for a provided label name
 	//
 	// The "data" section of the JSON response is a list of string label values.
 	//
 	GetLabelLabelNameValuesRpc(ctx context.Context, in *GetLabelLabelNameValuesRpcRequest, opts ...grpc.CallOption) (*GetLabelLabelNameValuesRpcResponse, error)
+	// Series is compatible to Prometheus POST /api/v1/series
+	// The following endpoint returns the list of time series that match a certain label set.
+	//
+	// The "data" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.
+	//
+	SeriesRpc(ctx context.Context, in *SeriesRpcRequest, opts ...grpc.CallOption) (*SeriesRpcResponse, error)
+	// GetSeries is compatible to Prometheus GET /api/v1/series
+	// The following endpoint returns the list of time series that match a certain label set.
+	//
+	// The "data" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.
+	//
+	GetSeriesRpc(ctx context.Context, in *GetSeriesRpcRequest, opts ...grpc.CallOption) (*GetSeriesRpcResponse, error)
+	// Labels is compatible to Prometheus POST /api/v1/labels
+	// The following endpoint returns a list of label names.
+	//
+	// The "data" section of the JSON response is a list of string label names.
+	//
+	LabelsRpc(ctx context.Context, in *LabelsRpcRequest, opts ...grpc.CallOption) (*LabelsRpcResponse, error)
+	// GetLabels is compatible to Prometheus GET /api/v1/labels
+	// The following endpoint returns a list of label names.
+	//
+	// The "data" section of the JSON response is a list of string label names.
+	//
+	GetLabelsRpc(ctx context.Context, in *GetLabelsRpcRequest, opts ...grpc.CallOption) (*GetLabelsRpcResponse, error)
+	// GetMetadata is compatible to Prometheus GET /api/v1/metadata
+	// The following endpoint returns metadata about metrics. As InfluxDB doesn't store metric metadata,
+	// metric types are inferred from Prometheus naming conventions, and help and unit text are read from the metadata file.
+	//
+	// The "data" section of the JSON response is an object where each key is a metric name and each value is a list of unique metadata objects.
+	//
+	GetMetadataRpc(ctx context.Context, in *GetMetadataRpcRequest, opts ...grpc.CallOption) (*GetMetadataRpcResponse, error)
 }
 
 type promServiceClient struct {
@@ -78,6 +111,38 @@ func (c *promServiceClient) GetQueryRangeRpc(ctx context.Context, in *GetQueryRa
 	return out, nil
 }
 
+func (c *promServiceClient) QueryRangeStreamRpc(ctx context.Context, in *QueryRangeRpcRequest, opts ...grpc.CallOption) (PromService_QueryRangeStreamRpcClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PromService_ServiceDesc.Streams[0], "/prom.PromService/QueryRangeStreamRpc", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &promServiceQueryRangeStreamRpcClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PromService_QueryRangeStreamRpcClient interface {
+	Recv() (*QueryRangeStreamRpcResponse, error)
+	grpc.ClientStream
+}
+
+type promServiceQueryRangeStreamRpcClient struct {
+	grpc.ClientStream
+}
+
+func (x *promServiceQueryRangeStreamRpcClient) Recv() (*QueryRangeStreamRpcResponse, error) {
+	m := new(QueryRangeStreamRpcResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *promServiceClient) GetLabelLabelNameValuesRpc(ctx context.Context, in *GetLabelLabelNameValuesRpcRequest, opts ...grpc.CallOption) (*GetLabelLabelNameValuesRpcResponse, error) {
 	out := new(GetLabelLabelNameValuesRpcResponse)
 	err := c.cc.Invoke(ctx, "/prom.PromService/GetLabelLabelNameValuesRpc", in, out, opts...)
@@ -87,6 +152,51 @@ func (c *promServiceClient) GetLabelLabelNameValuesRpc(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *promServiceClient) SeriesRpc(ctx context.Context, in *SeriesRpcRequest, opts ...grpc.CallOption) (*SeriesRpcResponse, error) {
+	out := new(SeriesRpcResponse)
+	err := c.cc.Invoke(ctx, "/prom.PromService/SeriesRpc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *promServiceClient) GetSeriesRpc(ctx context.Context, in *GetSeriesRpcRequest, opts ...grpc.CallOption) (*GetSeriesRpcResponse, error) {
+	out := new(GetSeriesRpcResponse)
+	err := c.cc.Invoke(ctx, "/prom.PromService/GetSeriesRpc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *promServiceClient) LabelsRpc(ctx context.Context, in *LabelsRpcRequest, opts ...grpc.CallOption) (*LabelsRpcResponse, error) {
+	out := new(LabelsRpcResponse)
+	err := c.cc.Invoke(ctx, "/prom.PromService/LabelsRpc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *promServiceClient) GetLabelsRpc(ctx context.Context, in *GetLabelsRpcRequest, opts ...grpc.CallOption) (*GetLabelsRpcResponse, error) {
+	out := new(GetLabelsRpcResponse)
+	err := c.cc.Invoke(ctx, "/prom.PromService/GetLabelsRpc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *promServiceClient) GetMetadataRpc(ctx context.Context, in *GetMetadataRpcRequest, opts ...grpc.CallOption) (*GetMetadataRpcResponse, error) {
+	out := new(GetMetadataRpcResponse)
+	err := c.cc.Invoke(ctx, "/prom.PromService/GetMetadataRpc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PromServiceServer is the server API for PromService service.
 // All implementations must embed UnimplementedPromServiceServer
 // for forward compatibility
@@ -99,12 +209,45 @@ type PromServiceServer interface {
 	QueryRangeRpc(context.Context, *QueryRangeRpcRequest) (*QueryRangeRpcResponse, error)
 	// GetQuery_range is compatible to Prometheus GET /api/v1/query_range
 	GetQueryRangeRpc(context.Context, *GetQueryRangeRpcRequest) (*GetQueryRangeRpcResponse, error)
+	// QueryRangeStream streams matrix result of range query chunk by chunk as soon as they are decoded from InfluxDB
+	QueryRangeStreamRpc(*QueryRangeRpcRequest, PromService_QueryRangeStreamRpcServer) error
 	// GetLabel_Label_nameValues Returns label values
 	// The following endpoint returns a list of label values for a provided label name
 	//
 	// The "data" section of the JSON response is a list of string label values.
 	//
 	GetLabelLabelNameValuesRpc(context.Context, *GetLabelLabelNameValuesRpcRequest) (*GetLabelLabelNameValuesRpcResponse, error)
+	// Series is compatible to Prometheus POST /api/v1/series
+	// The following endpoint returns the list of time series that match a certain label set.
+	//
+	// The "data" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.
+	//
+	SeriesRpc(context.Context, *SeriesRpcRequest) (*SeriesRpcResponse, error)
+	// GetSeries is compatible to Prometheus GET /api/v1/series
+	// The following endpoint returns the list of time series that match a certain label set.
+	//
+	// The "data" section of the JSON response is a list of objects that contain the label name/value pairs which identify each series.
+	//
+	GetSeriesRpc(context.Context, *GetSeriesRpcRequest) (*GetSeriesRpcResponse, error)
+	// Labels is compatible to Prometheus POST /api/v1/labels
+	// The following endpoint returns a list of label names.
+	//
+	// The "data" section of the JSON response is a list of string label names.
+	//
+	LabelsRpc(context.Context, *LabelsRpcRequest) (*LabelsRpcResponse, error)
+	// GetLabels is compatible to Prometheus GET /api/v1/labels
+	// The following endpoint returns a list of label names.
+	//
+	// The "data" section of the JSON response is a list of string label names.
+	//
+	GetLabelsRpc(context.Context, *GetLabelsRpcRequest) (*GetLabelsRpcResponse, error)
+	// GetMetadata is compatible to Prometheus GET /api/v1/metadata
+	// The following endpoint returns metadata about metrics. As InfluxDB doesn't store metric metadata,
+	// metric types are inferred from Prometheus naming conventions, and help and unit text are read from the metadata file.
+	//
+	// The "data" section of the JSON response is an object where each key is a metric name and each value is a list of unique metadata objects.
+	//
+	GetMetadataRpc(context.Context, *GetMetadataRpcRequest) (*GetMetadataRpcResponse, error)
 	mustEmbedUnimplementedPromServiceServer()
 }
 
@@ -124,9 +267,27 @@ func (UnimplementedPromServiceServer) QueryRangeRpc(context.Context, *QueryRange
 func (UnimplementedPromServiceServer) GetQueryRangeRpc(context.Context, *GetQueryRangeRpcRequest) (*GetQueryRangeRpcResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetQueryRangeRpc not implemented")
 }
+func (UnimplementedPromServiceServer) QueryRangeStreamRpc(*QueryRangeRpcRequest, PromService_QueryRangeStreamRpcServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryRangeStreamRpc not implemented")
+}
 func (UnimplementedPromServiceServer) GetLabelLabelNameValuesRpc(context.Context, *GetLabelLabelNameValuesRpcRequest) (*GetLabelLabelNameValuesRpcResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLabelLabelNameValuesRpc not implemented")
 }
+func (UnimplementedPromServiceServer) SeriesRpc(context.Context, *SeriesRpcRequest) (*SeriesRpcResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SeriesRpc not implemented")
+}
+func (UnimplementedPromServiceServer) GetSeriesRpc(context.Context, *GetSeriesRpcRequest) (*GetSeriesRpcResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeriesRpc not implemented")
+}
+func (UnimplementedPromServiceServer) LabelsRpc(context.Context, *LabelsRpcRequest) (*LabelsRpcResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LabelsRpc not implemented")
+}
+func (UnimplementedPromServiceServer) GetLabelsRpc(context.Context, *GetLabelsRpcRequest) (*GetLabelsRpcResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLabelsRpc not implemented")
+}
+func (UnimplementedPromServiceServer) GetMetadataRpc(context.Context, *GetMetadataRpcRequest) (*GetMetadataRpcResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadataRpc not implemented")
+}
 func (UnimplementedPromServiceServer) mustEmbedUnimplementedPromServiceServer() {}
 
 // UnsafePromServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -212,6 +373,27 @@ func _PromService_GetQueryRangeRpc_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PromService_QueryRangeStreamRpc_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryRangeRpcRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PromServiceServer).QueryRangeStreamRpc(m, &promServiceQueryRangeStreamRpcServer{stream})
+}
+
+type PromService_QueryRangeStreamRpcServer interface {
+	Send(*QueryRangeStreamRpcResponse) error
+	grpc.ServerStream
+}
+
+type promServiceQueryRangeStreamRpcServer struct {
+	grpc.ServerStream
+}
+
+func (x *promServiceQueryRangeStreamRpcServer) Send(m *QueryRangeStreamRpcResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _PromService_GetLabelLabelNameValuesRpc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetLabelLabelNameValuesRpcRequest)
 	if err := dec(in); err != nil {
@@ -230,6 +412,96 @@ func _PromService_GetLabelLabelNameValuesRpc_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PromService_SeriesRpc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SeriesRpcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PromServiceServer).SeriesRpc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/prom.PromService/SeriesRpc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PromServiceServer).SeriesRpc(ctx, req.(*SeriesRpcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PromService_GetSeriesRpc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSeriesRpcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PromServiceServer).GetSeriesRpc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/prom.PromService/GetSeriesRpc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PromServiceServer).GetSeriesRpc(ctx, req.(*GetSeriesRpcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PromService_LabelsRpc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelsRpcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PromServiceServer).LabelsRpc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/prom.PromService/LabelsRpc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PromServiceServer).LabelsRpc(ctx, req.(*LabelsRpcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PromService_GetLabelsRpc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLabelsRpcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PromServiceServer).GetLabelsRpc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/prom.PromService/GetLabelsRpc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PromServiceServer).GetLabelsRpc(ctx, req.(*GetLabelsRpcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PromService_GetMetadataRpc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRpcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PromServiceServer).GetMetadataRpc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/prom.PromService/GetMetadataRpc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PromServiceServer).GetMetadataRpc(ctx, req.(*GetMetadataRpcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PromService_ServiceDesc is the grpc.ServiceDesc for PromService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -257,7 +529,33 @@ var PromService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLabelLabelNameValuesRpc",
 			Handler:    _PromService_GetLabelLabelNameValuesRpc_Handler,
 		},
+		{
+			MethodName: "SeriesRpc",
+			Handler:    _PromService_SeriesRpc_Handler,
+		},
+		{
+			MethodName: "GetSeriesRpc",
+			Handler:    _PromService_GetSeriesRpc_Handler,
+		},
+		{
+			MethodName: "LabelsRpc",
+			Handler:    _PromService_LabelsRpc_Handler,
+		},
+		{
+			MethodName: "GetLabelsRpc",
+			Handler:    _PromService_GetLabelsRpc_Handler,
+		},
+		{
+			MethodName: "GetMetadataRpc",
+			Handler:    _PromService_GetMetadataRpc_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryRangeStreamRpc",
+			Handler:       _PromService_QueryRangeStreamRpc_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "transport/grpc/prom.proto",
 }
@@ -0,0 +1,160 @@
+package httpsrv
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/unionj-cloud/go-doudou/v2/framework/rest"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	"github.com/wubin1989/promql2influxql/applications"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// exportContentTypes are Content-Type response headers of export formats
+var exportContentTypes = map[string]string{
+	"":                                "application/x-ndjson",
+	service.EXPORT_FORMAT_JSON_LINES:  "application/x-ndjson",
+	service.EXPORT_FORMAT_OPENMETRICS: "application/openmetrics-text; version=1.0.0; charset=utf-8",
+	service.EXPORT_FORMAT_CSV:         "text/csv; charset=utf-8",
+}
+
+// ExportHandler serves bulk export and import of raw samples. Unlike PromHandler it is not generated from svc.go,
+// because response bodies of export and request bodies of import are streamed in formats other than JSON.
+type ExportHandler struct {
+	exporter service.Exporter
+	// maxBodyBytes limits the size of import request bodies, both compressed and decompressed. 0 means no limit.
+	maxBodyBytes int64
+}
+
+// bodyTooLargeMessage is the error message of http.MaxBytesReader when the limit is exceeded
+const bodyTooLargeMessage = "http: request body too large"
+
+// Export streams raw samples of the series selected by match[] between start and end in format, one of jsonl (default),
+// openmetrics and csv. Errors can't be reported by status code once the first sample has been written, so the response
+// is truncated instead.
+func (receiver *ExportHandler) Export(_writer http.ResponseWriter, _req *http.Request) {
+	if _err := _req.ParseForm(); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusBadRequest)
+		return
+	}
+	match := _req.Form["match[]"]
+	if len(match) == 0 {
+		match = _req.Form["match"]
+	}
+	var start, end *string
+	if _, exists := _req.Form["start"]; exists {
+		_start := _req.FormValue("start")
+		start = &_start
+	}
+	if _, exists := _req.Form["end"]; exists {
+		_end := _req.FormValue("end")
+		end = &_end
+	}
+	format := _req.FormValue("format")
+	if contentType, ok := exportContentTypes[format]; ok {
+		_writer.Header().Set("Content-Type", contentType)
+	}
+	writer := &countingWriter{writer: _writer}
+	if err := receiver.exporter.Export(_req.Context(), writer, format, match, start, end); err != nil {
+		switch {
+		case writer.written > 0:
+			zlogger.Error().Err(err).Msgf("export aborted after %d bytes", writer.written)
+		case errors.Is(err, service.ErrBadRequest):
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// Import writes samples of request body in format, one of jsonl (default), openmetrics and csv, optionally gzip compressed.
+// Samples decoded before an invalid line are still written, and the number of imported samples is responded.
+// Bodies larger than maxBodyBytes are responded with 413 status code.
+func (receiver *ExportHandler) Import(_writer http.ResponseWriter, _req *http.Request) {
+	format := _req.URL.Query().Get("format")
+	body := receiver.limitBody(_writer, _req.Body)
+	if _req.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gzipReader.Close()
+		body = receiver.limitBody(_writer, gzipReader)
+	}
+	samples, err := receiver.exporter.Import(_req.Context(), body, format)
+	if err != nil {
+		if strings.Contains(err.Error(), bodyTooLargeMessage) {
+			http.Error(_writer, err.Error(), http.StatusRequestEntityTooLarge)
+		} else if errors.Is(err, service.ErrBadRequest) || errors.Is(err, applications.ErrRejectedSamples) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if _err := json.NewEncoder(_writer).Encode(struct {
+		Data   int    `json:"data"`
+		Status string `json:"status"`
+	}{
+		Data:   samples,
+		Status: service.SUCCESS_STATUS,
+	}); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// countingWriter counts bytes written to the response, to tell whether an error can still be responded
+type countingWriter struct {
+	writer  io.Writer
+	written int
+}
+
+func (receiver *countingWriter) Write(p []byte) (int, error) {
+	n, err := receiver.writer.Write(p)
+	receiver.written += n
+	return n, err
+}
+
+// limitBody limits body to maxBodyBytes by http.MaxBytesReader
+func (receiver *ExportHandler) limitBody(_writer http.ResponseWriter, body io.ReadCloser) io.ReadCloser {
+	if receiver.maxBodyBytes <= 0 {
+		return body
+	}
+	return http.MaxBytesReader(_writer, body, receiver.maxBodyBytes)
+}
+
+func NewExportHandler(exporter service.Exporter, maxBodyBytes int64) *ExportHandler {
+	return &ExportHandler{
+		exporter:     exporter,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+// ExportRoutes returns routes of bulk export and import
+func ExportRoutes(handler *ExportHandler) []rest.Route {
+	return []rest.Route{
+		{
+			Name:        "GetExport",
+			Method:      "GET",
+			Pattern:     "/export",
+			HandlerFunc: handler.Export,
+		},
+		{
+			Name:        "Export",
+			Method:      "POST",
+			Pattern:     "/export",
+			HandlerFunc: handler.Export,
+		},
+		{
+			Name:        "Import",
+			Method:      "POST",
+			Pattern:     "/import",
+			HandlerFunc: handler.Import,
+		},
+	}
+}
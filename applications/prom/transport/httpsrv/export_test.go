@@ -0,0 +1,86 @@
+package httpsrv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"github.com/pkg/errors"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stubExporter imports every byte of the body as a sample
+type stubExporter struct {
+	service.Exporter
+}
+
+func (receiver *stubExporter) Import(ctx context.Context, r io.Reader, format string) (int, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return 0, errors.Wrap(service.ErrBadRequest, err.Error())
+	}
+	return len(content), nil
+}
+
+func TestExportHandler_Import(t *testing.T) {
+	gzipped := func(content []byte) []byte {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		writer.Write(content)
+		writer.Close()
+		return buf.Bytes()
+	}
+	tests := []struct {
+		name         string
+		maxBodyBytes int64
+		body         []byte
+		gzip         bool
+		wantStatus   int
+	}{
+		{
+			name:         "within limit",
+			maxBodyBytes: 16,
+			body:         bytes.Repeat([]byte("a"), 16),
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "too large",
+			maxBodyBytes: 16,
+			body:         bytes.Repeat([]byte("a"), 17),
+			wantStatus:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "too large after decompression",
+			maxBodyBytes: 64,
+			body:         bytes.Repeat([]byte("a"), 1024),
+			gzip:         true,
+			wantStatus:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "no limit",
+			body:       bytes.Repeat([]byte("a"), 1024),
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewExportHandler(&stubExporter{}, tt.maxBodyBytes)
+			body := tt.body
+			if tt.gzip {
+				body = gzipped(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/import", bytes.NewReader(body))
+			if tt.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+			w := httptest.NewRecorder()
+			handler.Import(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Import() status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
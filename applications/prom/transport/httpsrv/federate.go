@@ -0,0 +1,60 @@
+package httpsrv
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/expfmt"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/zlogger"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"net/http"
+)
+
+// FederateHandler serves Prometheus federation. Unlike PromHandler it is not generated from svc.go,
+// because response bodies are in Prometheus text exposition or OpenMetrics format rather than JSON.
+type FederateHandler struct {
+	federator service.Federator
+}
+
+// Federate is compatible to Prometheus GET /federate. The format is negotiated by Accept request header as Prometheus does,
+// so OpenMetrics format is responded to scrapers asking for it, and text exposition format to the others.
+func (receiver *FederateHandler) Federate(_writer http.ResponseWriter, _req *http.Request) {
+	if _err := _req.ParseForm(); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := expfmt.NegotiateIncludingOpenMetrics(_req.Header)
+	_writer.Header().Set("Content-Type", string(format))
+	writer := &countingWriter{writer: _writer}
+	if err := receiver.federator.Federate(_req.Context(), writer, format, _req.Form["match[]"]); err != nil {
+		switch {
+		case writer.written > 0:
+			zlogger.Error().Err(err).Msgf("federation aborted after %d bytes", writer.written)
+		case errors.Is(err, service.ErrBadRequest):
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func NewFederateHandler(federator service.Federator) *FederateHandler {
+	return &FederateHandler{
+		federator: federator,
+	}
+}
+
+// FederatePath is the path of Prometheus federation, served at root rather than under GDD_ROUTE_ROOT_PATH as Prometheus does
+const FederatePath = "/federate"
+
+// Federate serves GET FederatePath by handler. It is a middleware rather than a route, because routes are mounted under
+// GDD_ROUTE_ROOT_PATH, while requests to other paths fall through to the not found handler wrapped by middlewares as well.
+func Federate(handler *FederateHandler) func(http.Handler) http.Handler {
+	return func(inner http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet && r.URL.Path == FederatePath {
+				handler.Federate(w, r)
+				return
+			}
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package httpsrv
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/expfmt"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// stubFederator writes the match[] parameters it received
+type stubFederator struct {
+	match []string
+}
+
+func (receiver *stubFederator) Federate(ctx context.Context, w io.Writer, format expfmt.Format, match []string) error {
+	if len(match) == 0 {
+		return errors.Wrap(service.ErrBadRequest, "no match[] parameter provided")
+	}
+	receiver.match = match
+	_, err := io.WriteString(w, "up 1\n")
+	return err
+}
+
+func TestFederate(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantBody   string
+		wantMatch  []string
+	}{
+		{
+			name:       "served at root",
+			method:     http.MethodGet,
+			target:     "/federate?match[]=up&match[]=%7Bjob%3D%22prometheus%22%7D",
+			wantStatus: http.StatusOK,
+			wantBody:   "up 1\n",
+			wantMatch:  []string{"up", `{job="prometheus"}`},
+		},
+		{
+			name:       "bad request",
+			method:     http.MethodGet,
+			target:     "/federate",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "no match[] parameter provided: bad request\n",
+		},
+		{
+			name:       "not under root path",
+			method:     http.MethodGet,
+			target:     "/api/v1/federate?match[]=up",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			target:     "/federate?match[]=up",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			federator := &stubFederator{}
+			handler := Federate(NewFederateHandler(federator))(http.NotFoundHandler())
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("Federate() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("Federate() body = %q, want %q", got, tt.wantBody)
+			}
+			if !reflect.DeepEqual(federator.match, tt.wantMatch) {
+				t.Errorf("Federate() match = %v, want %v", federator.match, tt.wantMatch)
+			}
+		})
+	}
+}
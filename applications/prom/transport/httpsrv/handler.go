@@ -17,6 +17,11 @@ type PromHandler interface {
 	Query_range(w http.ResponseWriter, r *http.Request)
 	GetQuery_range(w http.ResponseWriter, r *http.Request)
 	GetLabel_Label_nameValues(w http.ResponseWriter, r *http.Request)
+	Series(w http.ResponseWriter, r *http.Request)
+	GetSeries(w http.ResponseWriter, r *http.Request)
+	Labels(w http.ResponseWriter, r *http.Request)
+	GetLabels(w http.ResponseWriter, r *http.Request)
+	GetMetadata(w http.ResponseWriter, r *http.Request)
 }
 
 func Routes(handler PromHandler) []rest.Route {
@@ -51,6 +56,36 @@ func Routes(handler PromHandler) []rest.Route {
 			Pattern:     "/label/:label_name/values",
 			HandlerFunc: handler.GetLabel_Label_nameValues,
 		},
+		{
+			Name:        "Series",
+			Method:      "POST",
+			Pattern:     "/series",
+			HandlerFunc: handler.Series,
+		},
+		{
+			Name:        "GetSeries",
+			Method:      "GET",
+			Pattern:     "/series",
+			HandlerFunc: handler.GetSeries,
+		},
+		{
+			Name:        "Labels",
+			Method:      "POST",
+			Pattern:     "/labels",
+			HandlerFunc: handler.Labels,
+		},
+		{
+			Name:        "GetLabels",
+			Method:      "GET",
+			Pattern:     "/labels",
+			HandlerFunc: handler.GetLabels,
+		},
+		{
+			Name:        "GetMetadata",
+			Method:      "GET",
+			Pattern:     "/metadata",
+			HandlerFunc: handler.GetMetadata,
+		},
 	}
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/unionj-cloud/go-doudou/v2/framework/rest"
 	"github.com/unionj-cloud/go-doudou/v2/framework/rest/httprouter"
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/cast"
 	service "github.com/wubin1989/promql2influxql/applications/prom"
 	"github.com/wubin1989/promql2influxql/applications/prom/dto"
 )
@@ -22,13 +23,14 @@ type PromHandlerImpl struct {
 
 func (receiver *PromHandlerImpl) Query(_writer http.ResponseWriter, _req *http.Request) {
 	var (
-		ctx     context.Context
-		query   string
-		time    *string
-		timeout *string
-		data    dto.QueryData
-		status  string
-		err     error
+		ctx      context.Context
+		query    string
+		time     *string
+		timezone *string
+		timeout  *string
+		data     dto.QueryData
+		status   string
+		err      error
 	)
 	ctx = _req.Context()
 	if _err := _req.ParseForm(); _err != nil {
@@ -53,6 +55,14 @@ func (receiver *PromHandlerImpl) Query(_writer http.ResponseWriter, _req *http.R
 			return
 		}
 	}
+	if _, exists := _req.Form["timezone"]; exists {
+		_timezone := _req.FormValue("timezone")
+		timezone = &_timezone
+		if _err := rest.ValidateVar(timezone, "", "timezone"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 	if _, exists := _req.Form["timeout"]; exists {
 		_timeout := _req.FormValue("timeout")
 		timeout = &_timeout
@@ -65,6 +75,7 @@ func (receiver *PromHandlerImpl) Query(_writer http.ResponseWriter, _req *http.R
 		ctx,
 		query,
 		time,
+		timezone,
 		timeout,
 	)
 	if err != nil {
@@ -90,13 +101,14 @@ func (receiver *PromHandlerImpl) Query(_writer http.ResponseWriter, _req *http.R
 }
 func (receiver *PromHandlerImpl) GetQuery(_writer http.ResponseWriter, _req *http.Request) {
 	var (
-		ctx     context.Context
-		query   string
-		time    *string
-		timeout *string
-		data    dto.QueryData
-		status  string
-		err     error
+		ctx      context.Context
+		query    string
+		time     *string
+		timezone *string
+		timeout  *string
+		data     dto.QueryData
+		status   string
+		err      error
 	)
 	ctx = _req.Context()
 	if _err := _req.ParseForm(); _err != nil {
@@ -121,6 +133,14 @@ func (receiver *PromHandlerImpl) GetQuery(_writer http.ResponseWriter, _req *htt
 			return
 		}
 	}
+	if _, exists := _req.Form["timezone"]; exists {
+		_timezone := _req.FormValue("timezone")
+		timezone = &_timezone
+		if _err := rest.ValidateVar(timezone, "", "timezone"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 	if _, exists := _req.Form["timeout"]; exists {
 		_timeout := _req.FormValue("timeout")
 		timeout = &_timeout
@@ -133,6 +153,7 @@ func (receiver *PromHandlerImpl) GetQuery(_writer http.ResponseWriter, _req *htt
 		ctx,
 		query,
 		time,
+		timezone,
 		timeout,
 	)
 	if err != nil {
@@ -158,15 +179,17 @@ func (receiver *PromHandlerImpl) GetQuery(_writer http.ResponseWriter, _req *htt
 }
 func (receiver *PromHandlerImpl) Query_range(_writer http.ResponseWriter, _req *http.Request) {
 	var (
-		ctx     context.Context
-		query   string
-		start   *string
-		end     *string
-		step    *string
-		timeout *string
-		data    dto.QueryData
-		status  string
-		err     error
+		ctx      context.Context
+		query    string
+		start    *string
+		end      *string
+		step     *string
+		fill     *string
+		timezone *string
+		timeout  *string
+		data     dto.QueryData
+		status   string
+		err      error
 	)
 	ctx = _req.Context()
 	if _err := _req.ParseForm(); _err != nil {
@@ -207,6 +230,22 @@ func (receiver *PromHandlerImpl) Query_range(_writer http.ResponseWriter, _req *
 			return
 		}
 	}
+	if _, exists := _req.Form["fill"]; exists {
+		_fill := _req.FormValue("fill")
+		fill = &_fill
+		if _err := rest.ValidateVar(fill, "", "fill"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["timezone"]; exists {
+		_timezone := _req.FormValue("timezone")
+		timezone = &_timezone
+		if _err := rest.ValidateVar(timezone, "", "timezone"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 	if _, exists := _req.Form["timeout"]; exists {
 		_timeout := _req.FormValue("timeout")
 		timeout = &_timeout
@@ -221,6 +260,8 @@ func (receiver *PromHandlerImpl) Query_range(_writer http.ResponseWriter, _req *
 		start,
 		end,
 		step,
+		fill,
+		timezone,
 		timeout,
 	)
 	if err != nil {
@@ -246,15 +287,17 @@ func (receiver *PromHandlerImpl) Query_range(_writer http.ResponseWriter, _req *
 }
 func (receiver *PromHandlerImpl) GetQuery_range(_writer http.ResponseWriter, _req *http.Request) {
 	var (
-		ctx     context.Context
-		query   string
-		start   *string
-		end     *string
-		step    *string
-		timeout *string
-		data    dto.QueryData
-		status  string
-		err     error
+		ctx      context.Context
+		query    string
+		start    *string
+		end      *string
+		step     *string
+		fill     *string
+		timezone *string
+		timeout  *string
+		data     dto.QueryData
+		status   string
+		err      error
 	)
 	ctx = _req.Context()
 	if _err := _req.ParseForm(); _err != nil {
@@ -295,6 +338,22 @@ func (receiver *PromHandlerImpl) GetQuery_range(_writer http.ResponseWriter, _re
 			return
 		}
 	}
+	if _, exists := _req.Form["fill"]; exists {
+		_fill := _req.FormValue("fill")
+		fill = &_fill
+		if _err := rest.ValidateVar(fill, "", "fill"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["timezone"]; exists {
+		_timezone := _req.FormValue("timezone")
+		timezone = &_timezone
+		if _err := rest.ValidateVar(timezone, "", "timezone"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 	if _, exists := _req.Form["timeout"]; exists {
 		_timeout := _req.FormValue("timeout")
 		timeout = &_timeout
@@ -309,6 +368,8 @@ func (receiver *PromHandlerImpl) GetQuery_range(_writer http.ResponseWriter, _re
 		start,
 		end,
 		step,
+		fill,
+		timezone,
 		timeout,
 	)
 	if err != nil {
@@ -415,6 +476,397 @@ func (receiver *PromHandlerImpl) GetLabel_Label_nameValues(_writer http.Response
 		return
 	}
 }
+func (receiver *PromHandlerImpl) Series(_writer http.ResponseWriter, _req *http.Request) {
+	var (
+		ctx    context.Context
+		match  []string
+		start  *string
+		end    *string
+		data   []map[string]string
+		status string
+		err    error
+	)
+	ctx = _req.Context()
+	if _err := _req.ParseForm(); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, exists := _req.Form["match"]; exists {
+		match = _req.Form["match"]
+		if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		if _, exists := _req.Form["match[]"]; exists {
+			match = _req.Form["match[]"]
+			if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+				http.Error(_writer, _err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			http.Error(_writer, "missing parameter match", http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["start"]; exists {
+		_start := _req.FormValue("start")
+		start = &_start
+		if _err := rest.ValidateVar(start, "", "start"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["end"]; exists {
+		_end := _req.FormValue("end")
+		end = &_end
+		if _err := rest.ValidateVar(end, "", "end"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	data, status, err = receiver.prom.Series(
+		ctx,
+		match,
+		start,
+		end,
+	)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		} else if _err, ok := err.(*rest.BizError); ok {
+			http.Error(_writer, _err.Error(), _err.StatusCode)
+		} else {
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if _err := json.NewEncoder(_writer).Encode(struct {
+		Data   []map[string]string `json:"data"`
+		Status string              `json:"status"`
+	}{
+		Data:   data,
+		Status: status,
+	}); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+func (receiver *PromHandlerImpl) GetSeries(_writer http.ResponseWriter, _req *http.Request) {
+	var (
+		ctx    context.Context
+		match  []string
+		start  *string
+		end    *string
+		data   []map[string]string
+		status string
+		err    error
+	)
+	ctx = _req.Context()
+	if _err := _req.ParseForm(); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, exists := _req.Form["match"]; exists {
+		match = _req.Form["match"]
+		if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		if _, exists := _req.Form["match[]"]; exists {
+			match = _req.Form["match[]"]
+			if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+				http.Error(_writer, _err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			http.Error(_writer, "missing parameter match", http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["start"]; exists {
+		_start := _req.FormValue("start")
+		start = &_start
+		if _err := rest.ValidateVar(start, "", "start"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["end"]; exists {
+		_end := _req.FormValue("end")
+		end = &_end
+		if _err := rest.ValidateVar(end, "", "end"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	data, status, err = receiver.prom.GetSeries(
+		ctx,
+		match,
+		start,
+		end,
+	)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		} else if _err, ok := err.(*rest.BizError); ok {
+			http.Error(_writer, _err.Error(), _err.StatusCode)
+		} else {
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if _err := json.NewEncoder(_writer).Encode(struct {
+		Data   []map[string]string `json:"data"`
+		Status string              `json:"status"`
+	}{
+		Data:   data,
+		Status: status,
+	}); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+func (receiver *PromHandlerImpl) Labels(_writer http.ResponseWriter, _req *http.Request) {
+	var (
+		ctx    context.Context
+		match  *[]string
+		start  *string
+		end    *string
+		limit  *int
+		data   []string
+		status string
+		err    error
+	)
+	ctx = _req.Context()
+	if _err := _req.ParseForm(); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, exists := _req.Form["match"]; exists {
+		_match := _req.Form["match"]
+		match = &_match
+		if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		if _, exists := _req.Form["match[]"]; exists {
+			_match := _req.Form["match[]"]
+			match = &_match
+			if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+				http.Error(_writer, _err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if _, exists := _req.Form["start"]; exists {
+		_start := _req.FormValue("start")
+		start = &_start
+		if _err := rest.ValidateVar(start, "", "start"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["end"]; exists {
+		_end := _req.FormValue("end")
+		end = &_end
+		if _err := rest.ValidateVar(end, "", "end"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["limit"]; exists {
+		if casted, _err := cast.ToIntE(_req.FormValue("limit")); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		} else {
+			limit = &casted
+		}
+		if _err := rest.ValidateVar(limit, "", "limit"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	data, status, err = receiver.prom.Labels(
+		ctx,
+		match,
+		start,
+		end,
+		limit,
+	)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		} else if _err, ok := err.(*rest.BizError); ok {
+			http.Error(_writer, _err.Error(), _err.StatusCode)
+		} else {
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if _err := json.NewEncoder(_writer).Encode(struct {
+		Data   []string `json:"data"`
+		Status string   `json:"status"`
+	}{
+		Data:   data,
+		Status: status,
+	}); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+func (receiver *PromHandlerImpl) GetLabels(_writer http.ResponseWriter, _req *http.Request) {
+	var (
+		ctx    context.Context
+		match  *[]string
+		start  *string
+		end    *string
+		limit  *int
+		data   []string
+		status string
+		err    error
+	)
+	ctx = _req.Context()
+	if _err := _req.ParseForm(); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, exists := _req.Form["match"]; exists {
+		_match := _req.Form["match"]
+		match = &_match
+		if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		if _, exists := _req.Form["match[]"]; exists {
+			_match := _req.Form["match[]"]
+			match = &_match
+			if _err := rest.ValidateVar(match, "", "match"); _err != nil {
+				http.Error(_writer, _err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if _, exists := _req.Form["start"]; exists {
+		_start := _req.FormValue("start")
+		start = &_start
+		if _err := rest.ValidateVar(start, "", "start"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["end"]; exists {
+		_end := _req.FormValue("end")
+		end = &_end
+		if _err := rest.ValidateVar(end, "", "end"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["limit"]; exists {
+		if casted, _err := cast.ToIntE(_req.FormValue("limit")); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		} else {
+			limit = &casted
+		}
+		if _err := rest.ValidateVar(limit, "", "limit"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	data, status, err = receiver.prom.GetLabels(
+		ctx,
+		match,
+		start,
+		end,
+		limit,
+	)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		} else if _err, ok := err.(*rest.BizError); ok {
+			http.Error(_writer, _err.Error(), _err.StatusCode)
+		} else {
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if _err := json.NewEncoder(_writer).Encode(struct {
+		Data   []string `json:"data"`
+		Status string   `json:"status"`
+	}{
+		Data:   data,
+		Status: status,
+	}); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (receiver *PromHandlerImpl) GetMetadata(_writer http.ResponseWriter, _req *http.Request) {
+	var (
+		ctx    context.Context
+		limit  *int
+		metric *string
+		data   map[string][]dto.MetricMetadata
+		status string
+		err    error
+	)
+	ctx = _req.Context()
+	if _err := _req.ParseForm(); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, exists := _req.Form["limit"]; exists {
+		if casted, _err := cast.ToIntE(_req.FormValue("limit")); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		} else {
+			limit = &casted
+		}
+		if _err := rest.ValidateVar(limit, "", "limit"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if _, exists := _req.Form["metric"]; exists {
+		_metric := _req.FormValue("metric")
+		metric = &_metric
+		if _err := rest.ValidateVar(metric, "", "metric"); _err != nil {
+			http.Error(_writer, _err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	data, status, err = receiver.prom.GetMetadata(
+		ctx,
+		limit,
+		metric,
+	)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		} else if _err, ok := err.(*rest.BizError); ok {
+			http.Error(_writer, _err.Error(), _err.StatusCode)
+		} else {
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if _err := json.NewEncoder(_writer).Encode(struct {
+		Data   map[string][]dto.MetricMetadata `json:"data"`
+		Status string                          `json:"status"`
+	}{
+		Data:   data,
+		Status: status,
+	}); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
 
 func NewPromHandler(prom service.Prom) PromHandler {
 	return &PromHandlerImpl{
@@ -1,5 +1,28 @@
 /**
 * Generated by go-doudou v2.0.4.
 * You can edit it as your need.
-*/
-package httpsrv
\ No newline at end of file
+ */
+package httpsrv
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/unionj-cloud/go-doudou/v2/toolkit/stringutils"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+)
+
+// Timezone validates IANA timezone name from service.TimezoneHeader request header and puts it into request context.
+// It is used when the timezone parameter is omitted.
+func Timezone(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tz := r.Header.Get(service.TimezoneHeader); stringutils.IsNotEmpty(tz) {
+			if _, err := time.LoadLocation(tz); err != nil {
+				http.Error(w, "invalid "+service.TimezoneHeader+" header: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			r = r.WithContext(service.NewTimezoneContext(r.Context(), tz))
+		}
+		inner.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,118 @@
+package httpsrv
+
+import (
+	"compress/gzip"
+	"github.com/pkg/errors"
+	"github.com/unionj-cloud/go-doudou/v2/framework/rest"
+	"github.com/wubin1989/promql2influxql/applications"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"mime"
+	"net/http"
+)
+
+const (
+	otlpProtobufContentType = "application/x-protobuf"
+	otlpJSONContentType     = "application/json"
+)
+
+// OTLPHandler serves OTLP/HTTP metrics protocol. Unlike PromHandler it is not generated from svc.go,
+// because request and response bodies are protobuf or protobuf JSON messages rather than forms and JSON.
+type OTLPHandler struct {
+	writer service.OTLPWriter
+}
+
+// Metrics is compatible to OTLP/HTTP POST /v1/metrics. Both binary protobuf and JSON protobuf encodings are accepted,
+// optionally gzip compressed, and the response is encoded the same way as the request.
+// Errors are responded with google.rpc.Status message, 400 status code for invalid requests and rejected samples
+// that the exporter shouldn't retry, 503 status code for other errors that the exporter should retry.
+func (receiver *OTLPHandler) Metrics(_writer http.ResponseWriter, _req *http.Request) {
+	contentType, _, _ := mime.ParseMediaType(_req.Header.Get("Content-Type"))
+	if contentType != otlpProtobufContentType && contentType != otlpJSONContentType {
+		otlpError(_writer, otlpProtobufContentType, http.StatusUnsupportedMediaType,
+			status.Newf(codes.InvalidArgument, "unsupported content type %q", contentType))
+		return
+	}
+	body := _req.Body
+	if _req.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(_req.Body)
+		if err != nil {
+			otlpError(_writer, contentType, http.StatusBadRequest, status.New(codes.InvalidArgument, err.Error()))
+			return
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		otlpError(_writer, contentType, http.StatusBadRequest, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+	var req colmetricspb.ExportMetricsServiceRequest
+	if contentType == otlpJSONContentType {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(content, &req)
+	} else {
+		err = proto.Unmarshal(content, &req)
+	}
+	if err != nil {
+		otlpError(_writer, contentType, http.StatusBadRequest, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+	resp, err := receiver.writer.OTLPWrite(_req.Context(), &req)
+	if err != nil {
+		if errors.Is(err, applications.ErrRejectedSamples) {
+			otlpError(_writer, contentType, http.StatusBadRequest, status.New(codes.InvalidArgument, err.Error()))
+			return
+		}
+		otlpError(_writer, contentType, http.StatusServiceUnavailable, status.New(codes.Unavailable, err.Error()))
+		return
+	}
+	otlpWrite(_writer, contentType, http.StatusOK, resp)
+}
+
+// otlpError writes st as google.rpc.Status message required by OTLP/HTTP for failed requests
+func otlpError(_writer http.ResponseWriter, contentType string, statusCode int, st *status.Status) {
+	otlpWrite(_writer, contentType, statusCode, st.Proto())
+}
+
+func otlpWrite(_writer http.ResponseWriter, contentType string, statusCode int, message proto.Message) {
+	var (
+		content []byte
+		err     error
+	)
+	if contentType == otlpJSONContentType {
+		content, err = protojson.Marshal(message)
+	} else {
+		content, err = proto.Marshal(message)
+	}
+	if err != nil {
+		http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_writer.Header().Set("Content-Type", contentType)
+	_writer.WriteHeader(statusCode)
+	_writer.Write(content)
+}
+
+func NewOTLPHandler(writer service.OTLPWriter) *OTLPHandler {
+	return &OTLPHandler{
+		writer: writer,
+	}
+}
+
+// OTLPRoutes returns routes of OTLP/HTTP metrics protocol, the path is the same as Prometheus OTLP receiver
+func OTLPRoutes(handler *OTLPHandler) []rest.Route {
+	return []rest.Route{
+		{
+			Name:        "Metrics",
+			Method:      "POST",
+			Pattern:     "/otlp/v1/metrics",
+			HandlerFunc: handler.Metrics,
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package httpsrv
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/unionj-cloud/go-doudou/v2/framework/rest"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"net/http"
+	"sync"
+)
+
+// RemoteReadHandler serves Prometheus remote read protocol. Unlike PromHandler it is not generated from svc.go,
+// because request and response bodies are snappy-compressed protobuf messages rather than forms and JSON.
+type RemoteReadHandler struct {
+	reader service.RemoteReader
+	// sampleLimit is the maximum number of samples returned for a single query of the sampled response type, 0 means no limit
+	sampleLimit int
+	// maxBytesInFrame is the maximum size of a single frame of the streamed response type
+	maxBytesInFrame int
+	marshalPool     *sync.Pool
+}
+
+// Read is compatible to Prometheus POST /api/v1/read. The response type is negotiated from the accepted response types
+// of the request, streamed XOR chunks if accepted, otherwise samples.
+func (receiver *RemoteReadHandler) Read(_writer http.ResponseWriter, _req *http.Request) {
+	ctx := _req.Context()
+	req, err := remote.DecodeReadRequest(_req)
+	if err != nil {
+		http.Error(_writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	responseType, err := remote.NegotiateResponseType(req.AcceptedResponseTypes)
+	if err != nil {
+		http.Error(_writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch responseType {
+	case prompb.ReadRequest_STREAMED_XOR_CHUNKS:
+		receiver.readStreamedXORChunks(ctx, _writer, req)
+	default:
+		receiver.readSamples(ctx, _writer, req)
+	}
+}
+
+func (receiver *RemoteReadHandler) readSamples(ctx context.Context, _writer http.ResponseWriter, req *prompb.ReadRequest) {
+	resp := prompb.ReadResponse{
+		Results: make([]*prompb.QueryResult, len(req.Queries)),
+	}
+	for i, query := range req.Queries {
+		seriesSet, err := receiver.reader.RemoteRead(ctx, query)
+		if err != nil {
+			remoteReadError(_writer, err)
+			return
+		}
+		if resp.Results[i], _, err = remote.ToQueryResult(seriesSet, receiver.sampleLimit); err != nil {
+			remoteReadError(_writer, err)
+			return
+		}
+	}
+	_writer.Header().Set("Content-Type", "application/x-protobuf")
+	_writer.Header().Set("Content-Encoding", "snappy")
+	if err := remote.EncodeReadResponse(&resp, _writer); err != nil {
+		http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// readStreamedXORChunks writes series of every query as soon as they are encoded to XOR chunks, one or more series per frame.
+// Errors can't be reported by status code once the first frame has been written.
+func (receiver *RemoteReadHandler) readStreamedXORChunks(ctx context.Context, _writer http.ResponseWriter, req *prompb.ReadRequest) {
+	_writer.Header().Set("Content-Type", "application/x-streamed-protobuf; proto=prometheus.ChunkedReadResponse")
+	flusher, ok := _writer.(http.Flusher)
+	if !ok {
+		http.Error(_writer, "internal http.ResponseWriter does not implement http.Flusher interface", http.StatusInternalServerError)
+		return
+	}
+	for i, query := range req.Queries {
+		seriesSet, err := receiver.reader.RemoteRead(ctx, query)
+		if err != nil {
+			remoteReadError(_writer, err)
+			return
+		}
+		if _, err = remote.StreamChunkedReadResponses(remote.NewChunkedWriter(_writer, flusher), int64(i),
+			storage.NewSeriesSetToChunkSet(seriesSet), nil, receiver.maxBytesInFrame, receiver.marshalPool); err != nil {
+			remoteReadError(_writer, err)
+			return
+		}
+	}
+}
+
+func remoteReadError(_writer http.ResponseWriter, err error) {
+	var httpErr remote.HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		http.Error(_writer, httpErr.Error(), httpErr.Status())
+	case errors.Is(err, context.Canceled):
+		http.Error(_writer, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(_writer, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewRemoteReadHandler(reader service.RemoteReader, sampleLimit int, maxBytesInFrame int) *RemoteReadHandler {
+	return &RemoteReadHandler{
+		reader:          reader,
+		sampleLimit:     sampleLimit,
+		maxBytesInFrame: maxBytesInFrame,
+		marshalPool:     &sync.Pool{},
+	}
+}
+
+// RemoteReadRoutes returns routes of Prometheus remote read protocol
+func RemoteReadRoutes(handler *RemoteReadHandler) []rest.Route {
+	return []rest.Route{
+		{
+			Name:        "Read",
+			Method:      "POST",
+			Pattern:     "/read",
+			HandlerFunc: handler.Read,
+		},
+	}
+}
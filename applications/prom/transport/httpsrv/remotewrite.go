@@ -0,0 +1,53 @@
+package httpsrv
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/unionj-cloud/go-doudou/v2/framework/rest"
+	"github.com/wubin1989/promql2influxql/applications"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"net/http"
+)
+
+// RemoteWriteHandler serves Prometheus remote write protocol. Unlike PromHandler it is not generated from svc.go,
+// because request bodies are snappy-compressed protobuf messages rather than forms.
+type RemoteWriteHandler struct {
+	writer service.RemoteWriter
+}
+
+// Write is compatible to Prometheus POST /api/v1/write. Rejected samples are responded with 400 status code
+// so that Prometheus drops them, while other errors are responded with 500 status code so that Prometheus retries.
+func (receiver *RemoteWriteHandler) Write(_writer http.ResponseWriter, _req *http.Request) {
+	req, err := remote.DecodeWriteRequest(_req.Body)
+	if err != nil {
+		http.Error(_writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = receiver.writer.RemoteWrite(_req.Context(), req); err != nil {
+		if errors.Is(err, applications.ErrRejectedSamples) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_writer.WriteHeader(http.StatusNoContent)
+}
+
+func NewRemoteWriteHandler(writer service.RemoteWriter) *RemoteWriteHandler {
+	return &RemoteWriteHandler{
+		writer: writer,
+	}
+}
+
+// RemoteWriteRoutes returns routes of Prometheus remote write protocol
+func RemoteWriteRoutes(handler *RemoteWriteHandler) []rest.Route {
+	return []rest.Route{
+		{
+			Name:        "Write",
+			Method:      "POST",
+			Pattern:     "/write",
+			HandlerFunc: handler.Write,
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/unionj-cloud/go-doudou/v2/framework/rest"
+	service "github.com/wubin1989/promql2influxql/applications/prom"
+	"net/http"
+)
+
+// RulesHandler serves states of recording rules and alerting rules. Unlike PromHandler it is not generated from svc.go,
+// because rules are evaluated by service.RuleManager rather than the Prom service.
+type RulesHandler struct {
+	manager *service.RuleManager
+}
+
+// Rules is compatible to Prometheus GET /api/v1/rules, and type parameter filters rules by alert or record
+func (receiver *RulesHandler) Rules(_writer http.ResponseWriter, _req *http.Request) {
+	data, err := receiver.manager.RuleGroups(_req.URL.Query().Get("type"))
+	if err != nil {
+		if errors.Is(err, service.ErrBadRequest) {
+			http.Error(_writer, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(_writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	rulesWrite(_writer, data)
+}
+
+// Alerts is compatible to Prometheus GET /api/v1/alerts
+func (receiver *RulesHandler) Alerts(_writer http.ResponseWriter, _req *http.Request) {
+	rulesWrite(_writer, receiver.manager.Alerts())
+}
+
+func rulesWrite(_writer http.ResponseWriter, data interface{}) {
+	_writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if _err := json.NewEncoder(_writer).Encode(struct {
+		Data   interface{} `json:"data"`
+		Status string      `json:"status"`
+	}{
+		Data:   data,
+		Status: service.SUCCESS_STATUS,
+	}); _err != nil {
+		http.Error(_writer, _err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func NewRulesHandler(manager *service.RuleManager) *RulesHandler {
+	return &RulesHandler{
+		manager: manager,
+	}
+}
+
+// RulesRoutes returns routes of rules and alerts
+func RulesRoutes(handler *RulesHandler) []rest.Route {
+	return []rest.Route{
+		{
+			Name:        "Rules",
+			Method:      "GET",
+			Pattern:     "/rules",
+			HandlerFunc: handler.Rules,
+		},
+		{
+			Name:        "Alerts",
+			Method:      "GET",
+			Pattern:     "/alerts",
+			HandlerFunc: handler.Alerts,
+		},
+	}
+}